@@ -0,0 +1,142 @@
+package binny
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anchore/binny/internal/log"
+)
+
+// AuditAction is the kind of change made to the store.
+type AuditAction string
+
+const (
+	// AuditActionAdd is recorded when a tool is added to the store for the first time.
+	AuditActionAdd AuditAction = "add"
+	// AuditActionReplace is recorded when an installed tool is replaced (e.g. with another version).
+	AuditActionReplace AuditAction = "replace"
+	// AuditActionPrune is recorded when an entry is dropped from the store because its binary no longer exists.
+	AuditActionPrune AuditAction = "prune"
+)
+
+// AuditActor identifies who (and what) made changes to the store.
+type AuditActor struct {
+	User         string
+	BinnyVersion string
+	Command      string
+}
+
+// NewAuditActor returns the actor for the current process: the current user and the command line it was run with.
+func NewAuditActor(binnyVersion string) AuditActor {
+	return AuditActor{
+		User:         currentUser(),
+		BinnyVersion: binnyVersion,
+		Command:      strings.Join(append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...), " "),
+	}
+}
+
+// AuditRecord is a single change to the store, as recorded in the audit log.
+type AuditRecord struct {
+	Time         time.Time         `json:"time"`
+	Action       AuditAction       `json:"action"`
+	User         string            `json:"user,omitempty"`
+	BinnyVersion string            `json:"binnyVersion,omitempty"`
+	Command      string            `json:"command,omitempty"`
+	Tool         string            `json:"tool"`
+	OldVersion   string            `json:"oldVersion,omitempty"`
+	NewVersion   string            `json:"newVersion,omitempty"`
+	OldDigests   map[string]string `json:"oldDigests,omitempty"`
+	NewDigests   map[string]string `json:"newDigests,omitempty"`
+	SourceURL    string            `json:"sourceURL,omitempty"`
+}
+
+// SetAuditActor sets who is recorded in the audit log for changes made through this store.
+func (s *Store) SetAuditActor(actor AuditActor) {
+	s.actor = actor
+}
+
+// AuditLog returns every change recorded in the audit log of the store, oldest first.
+func (s Store) AuditLog() ([]AuditRecord, error) {
+	fh, err := os.Open(s.auditFilePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(fh)
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("unable to parse audit log %q line %d: %w", s.auditFilePath(), line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+func (s Store) auditFilePath() string {
+	return filepath.Join(s.root, ".binny.audit.jsonl")
+}
+
+// audit appends the record (attributed to the actor of the store) to the audit log. The log is only ever appended to.
+func (s Store) audit(record AuditRecord) error {
+	record.Time = time.Now().UTC()
+	record.User = s.actor.User
+	record.BinnyVersion = s.actor.BinnyVersion
+	record.Command = s.actor.Command
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.auditLock.Lock()
+	defer s.auditLock.Unlock()
+
+	fh, err := os.OpenFile(s.auditFilePath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open audit log: %w", err)
+	}
+	defer fh.Close()
+
+	// a single write keeps the line intact even when several processes append at once
+	if _, err := fh.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("unable to write audit log: %w", err)
+	}
+	return nil
+}
+
+// auditOrWarn appends the record to the audit log, only logging a failure since the change to the store has already
+// been made.
+func (s Store) auditOrWarn(record AuditRecord) {
+	if err := s.audit(record); err != nil {
+		log.WithFields("tool", record.Tool, "action", record.Action, "error", err).Warn("unable to record change in the audit log")
+	}
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	for _, env := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(env); name != "" {
+			return name
+		}
+	}
+	return ""
+}
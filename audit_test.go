@@ -0,0 +1,134 @@
+package binny
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/binny/internal"
+)
+
+func TestStore_AuditLog(t *testing.T) {
+	outsideRoot := t.TempDir()
+	root := t.TempDir()
+
+	store, err := NewStore(root)
+	require.NoError(t, err)
+	store.SetAuditActor(AuditActor{User: "someone", BinnyVersion: "v1.2.3", Command: "binny install"})
+
+	// nothing has been recorded yet
+	records, err := store.AuditLog()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	addTool := func(name, version, contents string, source ToolSource) {
+		t.Helper()
+		path := filepath.Join(outsideRoot, name)
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+		require.NoError(t, store.AddTool(name, version, path, source))
+	}
+
+	before := time.Now().UTC().Add(-time.Second)
+
+	addTool("tool-1", "v0.1.0", "hello world", ToolSource{URL: "https://example.com/tool-1_v0.1.0.tar.gz"})
+	addTool("tool-2", "v0.2.0", "other", ToolSource{URL: "github.com/org/tool-2@v0.2.0"})
+	addTool("tool-1", "v0.1.1", "replace hello world", ToolSource{URL: "https://example.com/tool-1_v0.1.1.tar.gz"})
+
+	// removing the binary prunes the tool from the store the next time the state is saved
+	require.NoError(t, os.Remove(filepath.Join(root, "tool-2")))
+	addTool("tool-3", "v0.3.0", "another", ToolSource{})
+
+	records, err = store.AuditLog()
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+
+	for _, record := range records {
+		assert.True(t, record.Time.After(before), "time should be set")
+		assert.Equal(t, "someone", record.User)
+		assert.Equal(t, "v1.2.3", record.BinnyVersion)
+		assert.Equal(t, "binny install", record.Command)
+	}
+
+	helloSha := "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	replaceSha := "781ac3727fddd802c1f7f540b4cd91398c034dd0bd1eafad808561c189dc0501"
+
+	assert.Equal(t, AuditActionAdd, records[0].Action)
+	assert.Equal(t, "tool-1", records[0].Tool)
+	assert.Equal(t, "v0.1.0", records[0].NewVersion)
+	assert.Equal(t, helloSha, records[0].NewDigests[internal.SHA256Algorithm])
+	assert.Equal(t, "https://example.com/tool-1_v0.1.0.tar.gz", records[0].SourceURL)
+
+	assert.Equal(t, AuditActionAdd, records[1].Action)
+	assert.Equal(t, "tool-2", records[1].Tool)
+	assert.Equal(t, "github.com/org/tool-2@v0.2.0", records[1].SourceURL)
+
+	assert.Equal(t, AuditActionReplace, records[2].Action)
+	assert.Equal(t, "tool-1", records[2].Tool)
+	assert.Equal(t, "v0.1.0", records[2].OldVersion)
+	assert.Equal(t, "v0.1.1", records[2].NewVersion)
+	assert.Equal(t, helloSha, records[2].OldDigests[internal.SHA256Algorithm])
+	assert.Equal(t, replaceSha, records[2].NewDigests[internal.SHA256Algorithm])
+
+	assert.Equal(t, AuditActionAdd, records[3].Action)
+	assert.Equal(t, "tool-3", records[3].Tool)
+
+	assert.Equal(t, AuditActionPrune, records[4].Action)
+	assert.Equal(t, "tool-2", records[4].Tool)
+	assert.Equal(t, "v0.2.0", records[4].OldVersion)
+	assert.Empty(t, records[4].NewVersion)
+	assert.NotEmpty(t, records[4].OldDigests)
+
+	// the log is only appended to, so earlier lines are never rewritten
+	contents, err := os.ReadFile(filepath.Join(root, ".binny.audit.jsonl"))
+	require.NoError(t, err)
+	addTool("tool-3", "v0.3.1", "yet another", ToolSource{})
+	after, err := os.ReadFile(filepath.Join(root, ".binny.audit.jsonl"))
+	require.NoError(t, err)
+	assert.Equal(t, string(contents), string(after[:len(contents)]))
+}
+
+func TestStore_AddTool_auditFailure(t *testing.T) {
+	outsideRoot := t.TempDir()
+	root := t.TempDir()
+
+	store, err := NewStore(root)
+	require.NoError(t, err)
+
+	addTool := func(version, contents string) {
+		t.Helper()
+		path := filepath.Join(outsideRoot, "tool")
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+		require.NoError(t, store.AddTool("tool", version, path, ToolSource{}))
+	}
+
+	addTool("v0.1.0", "hello world")
+
+	// the audit log cannot be written to
+	require.NoError(t, os.Remove(filepath.Join(root, ".binny.audit.jsonl")))
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".binny.audit.jsonl"), 0o755))
+
+	addTool("v0.1.1", "replace hello world")
+
+	// the state still matches the binary in the store
+	reloaded, err := NewStore(root)
+	require.NoError(t, err)
+	entries := reloaded.GetByName("tool")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "v0.1.1", entries[0].InstalledVersion)
+	require.NoError(t, entries[0].Verify(true, true))
+}
+
+func TestStore_AuditLog_invalid(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".binny.audit.jsonl"), []byte("{\"tool\":\"a\"}\n\nnot json\n"), 0o600))
+
+	store, err := NewStore(root)
+	require.NoError(t, err)
+
+	_, err = store.AuditLog()
+	require.ErrorContains(t, err, "line 3")
+}
@@ -67,6 +67,8 @@ func New(id clio.Identification) clio.Application {
 		command.Run(app),
 		command.Update(app),
 		command.List(app),
+		command.Audit(app),
+		command.Doctor(app),
 	)
 
 	return app
@@ -97,5 +97,9 @@ func runGithubReleaseConfig(cmdCfg AddGithubReleaseConfig, repoVersion string) e
 		Parameters:    installParamMap,
 	}
 
+	if err := checkNewToolPolicy(cmdCfg.Core, toolCfg); err != nil {
+		return err
+	}
+
 	return updateConfiguration(cmdCfg.Config, toolCfg)
 }
@@ -132,5 +132,9 @@ func runAddGoBuildConfig(cmdCfg AddGoBuildConfig, nameVersion string) error {
 		Parameters:    installParamMap,
 	}
 
+	if err := checkNewToolPolicy(cmdCfg.Core, toolCfg); err != nil {
+		return err
+	}
+
 	return updateConfiguration(cmdCfg.Config, toolCfg)
 }
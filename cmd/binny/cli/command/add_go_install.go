@@ -108,5 +108,9 @@ func runAddGoInstallConfig(cmdCfg AddGoInstallConfig, nameVersion string) error
 		Parameters:    installParamMap,
 	}
 
+	if err := checkNewToolPolicy(cmdCfg.Core, toolCfg); err != nil {
+		return err
+	}
+
 	return updateConfiguration(cmdCfg.Config, toolCfg)
 }
@@ -0,0 +1,133 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/binny"
+	"github.com/anchore/binny/cmd/binny/cli/option"
+	"github.com/anchore/clio"
+)
+
+type AuditLogConfig struct {
+	Config        string `json:"config" yaml:"config" mapstructure:"config"`
+	option.Core   `json:"" yaml:",inline" mapstructure:",squash"`
+	option.Audit  `json:"" yaml:",inline" mapstructure:",squash"`
+	option.Format `json:"" yaml:",inline" mapstructure:",squash"`
+}
+
+func Audit(app clio.Application) *cobra.Command {
+	cmd := app.SetupCommand(&cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the changes made to the store",
+	})
+
+	cmd.AddCommand(
+		AuditLog(app),
+	)
+
+	return cmd
+}
+
+func AuditLog(app clio.Application) *cobra.Command {
+	cfg := &AuditLogConfig{
+		Core: option.DefaultCore(),
+		Format: option.Format{
+			Output:           "table",
+			AllowableFormats: []string{"table", "json"},
+		},
+	}
+
+	return app.SetupCommand(&cobra.Command{
+		Use:   "log [NAME...]",
+		Short: "Show when tools were added to, replaced in, or pruned from the store (optionally for the given tools)",
+		Args:  cobra.ArbitraryArgs,
+		PreRunE: func(_ *cobra.Command, args []string) error {
+			if cfg.JQCommand != "" && cfg.Output != "json" {
+				return fmt.Errorf("--jq can only be used when --output format is 'json'")
+			}
+			cfg.IncludeFilter = args
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runAuditLog(*cfg)
+		},
+	}, cfg)
+}
+
+func runAuditLog(cfg AuditLogConfig) error {
+	store, err := binny.NewStore(cfg.Root)
+	if err != nil {
+		return err
+	}
+
+	records, err := store.AuditLog()
+	if err != nil {
+		return err
+	}
+
+	records = filterAuditRecords(records, cfg.IncludeFilter, cfg.After(time.Now()))
+
+	if cfg.Output == "json" {
+		if records == nil {
+			// always allocate collections
+			records = make([]binny.AuditRecord, 0)
+		}
+		return reportOnBus(renderJSON(map[string]any{"records": records}, cfg.JQCommand))
+	}
+
+	return reportOnBus(renderAuditTable(records), nil)
+}
+
+// filterAuditRecords returns the records for the given tools (or all tools) made after the given time (if not zero).
+func filterAuditRecords(records []binny.AuditRecord, names []string, after time.Time) []binny.AuditRecord {
+	var filtered []binny.AuditRecord
+	for _, record := range records {
+		if len(names) > 0 && !slices.Contains(names, record.Tool) {
+			continue
+		}
+		if !after.IsZero() && record.Time.Before(after) {
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+	return filtered
+}
+
+func renderAuditTable(records []binny.AuditRecord) string {
+	if len(records) == 0 {
+		return "no changes recorded"
+	}
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleLight)
+	t.Style().Options.DrawBorder = false
+	t.Style().Options.SeparateColumns = false
+
+	t.AppendHeader(table.Row{"Time", "Tool", "Action", "Version", "User", "Command"})
+
+	for _, record := range records {
+		version := summarizeGitVersion(record.NewVersion)
+		switch {
+		case record.Action == binny.AuditActionPrune:
+			version = summarizeGitVersion(record.OldVersion)
+		case record.OldVersion != "" && record.OldVersion != record.NewVersion:
+			version = fmt.Sprintf("%s → %s", summarizeGitVersion(record.OldVersion), version)
+		}
+
+		t.AppendRow(table.Row{
+			record.Time.Local().Format(time.DateTime),
+			record.Tool,
+			string(record.Action),
+			version,
+			record.User,
+			record.Command,
+		})
+	}
+
+	return t.Render()
+}
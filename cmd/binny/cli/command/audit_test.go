@@ -0,0 +1,56 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/binny"
+)
+
+func Test_filterAuditRecords(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	records := []binny.AuditRecord{
+		{Time: now.Add(-48 * time.Hour), Tool: "a", Action: binny.AuditActionAdd},
+		{Time: now.Add(-2 * time.Hour), Tool: "b", Action: binny.AuditActionAdd},
+		{Time: now.Add(-time.Hour), Tool: "a", Action: binny.AuditActionReplace},
+	}
+
+	tests := []struct {
+		name  string
+		names []string
+		after time.Time
+		want  []binny.AuditRecord
+	}{
+		{
+			name: "everything",
+			want: records,
+		},
+		{
+			name:  "by tool",
+			names: []string{"a"},
+			want:  []binny.AuditRecord{records[0], records[2]},
+		},
+		{
+			name:  "by time",
+			after: now.Add(-24 * time.Hour),
+			want:  []binny.AuditRecord{records[1], records[2]},
+		},
+		{
+			name:  "by tool and time",
+			names: []string{"a"},
+			after: now.Add(-24 * time.Hour),
+			want:  []binny.AuditRecord{records[2]},
+		},
+		{
+			name:  "no matches",
+			names: []string{"c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, filterAuditRecords(records, tt.names, tt.after))
+		})
+	}
+}
@@ -27,7 +27,9 @@ type CheckConfig struct {
 // applies cooldown so that verification reflects what install/update would produce.
 func (c CheckConfig) toolOptions() option.ToolOptions {
 	return option.DefaultToolOptions().
-		WithGlobalCooldown(c.Cooldown)
+		WithGlobalCooldown(c.Cooldown).
+		WithGlobalPrerelease(c.Prerelease).
+		WithPolicy(c.Policy)
 }
 
 func Check(app clio.Application) *cobra.Command {
@@ -46,7 +48,7 @@ func Check(app clio.Application) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runCheck(cmd.Context(), *cfg, names)
+			return runCheck(withHTTPClient(cmd.Context(), cfg.Core), *cfg, names)
 		},
 	}, cfg)
 }
@@ -126,11 +128,28 @@ func checkTool(ctx context.Context, store *binny.Store, opt option.Tool, opts op
 	// otherwise continue to install the tool
 	err = tool.Check(store, t.Name(), resolvedVersion, tool.VerifyConfig{
 		VerifyXXH64Digest:  true,
+		VerifyLinkage:      true,
 		VerifySHA256Digest: verifySha256Digest,
 	})
 	if err != nil {
+		if moved := movedSinceInstall(store, t.Name(), intent.Want, resolvedVersion); moved != nil {
+			return resolvedVersion, moved
+		}
 		return resolvedVersion, err
 	}
 
 	return resolvedVersion, nil
 }
+
+// movedSinceInstall returns an error describing that a moving want (e.g. a branch or "latest") resolves to a
+// different version than the one installed, or nil if that is not the case.
+func movedSinceInstall(store *binny.Store, name, want, resolvedVersion string) error {
+	if want == resolvedVersion {
+		return nil
+	}
+	entries := store.GetByName(name)
+	if len(entries) != 1 || entries[0].InstalledVersion == resolvedVersion {
+		return nil
+	}
+	return fmt.Errorf("%q has moved since the tool was installed (installed %q, now resolves to %q)", want, entries[0].InstalledVersion, resolvedVersion)
+}
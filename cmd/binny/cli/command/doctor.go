@@ -0,0 +1,134 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/cobra"
+
+	"github.com/anchore/binny/cmd/binny/cli/option"
+	internalhttp "github.com/anchore/binny/internal/http"
+	"github.com/anchore/binny/tool/githubrelease"
+	"github.com/anchore/clio"
+)
+
+type DoctorConfig struct {
+	Config        string `json:"config" yaml:"config" mapstructure:"config"`
+	option.Core   `json:"" yaml:",inline" mapstructure:",squash"`
+	option.Format `json:"" yaml:",inline" mapstructure:",squash"`
+}
+
+type doctorReport struct {
+	GitHubToken   bool              `json:"githubToken"`
+	RateLimitWait string            `json:"rateLimitWait"`
+	RateLimits    []rateLimitStatus `json:"rateLimits"`
+}
+
+type rateLimitStatus struct {
+	internalhttp.RateLimit
+	Status string `json:"status"`
+}
+
+func Doctor(app clio.Application) *cobra.Command {
+	cfg := &DoctorConfig{
+		Core: option.DefaultCore(),
+		Format: option.Format{
+			Output:           "table",
+			AllowableFormats: []string{"table", "json"},
+		},
+	}
+
+	return app.SetupCommand(&cobra.Command{
+		Use:   "doctor",
+		Short: "Report on the state of the services binny depends on (e.g. GitHub API access and rate limits)",
+		Args:  cobra.NoArgs,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if cfg.JQCommand != "" && cfg.Output != "json" {
+				return fmt.Errorf("--jq can only be used when --output format is 'json'")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDoctor(cmd, *cfg)
+		},
+	}, cfg)
+}
+
+func runDoctor(cmd *cobra.Command, cfg DoctorConfig) error {
+	// the rate limits are fetched without the response cache, which could report a stale state
+	ctx := internalhttp.WithRateLimits(cmd.Context(), cfg.NewRateLimits())
+
+	limits, err := githubrelease.FetchRateLimits(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to fetch the GitHub API rate limits: %w", err)
+	}
+
+	report := doctorReport{
+		GitHubToken:   os.Getenv("GITHUB_TOKEN") != "",
+		RateLimitWait: cfg.RateLimit.Wait.Duration.String(),
+		RateLimits:    make([]rateLimitStatus, 0, len(limits)),
+	}
+	now := time.Now()
+	for _, limit := range limits {
+		report.RateLimits = append(report.RateLimits, rateLimitStatus{
+			RateLimit: limit,
+			Status:    describeRateLimit(limit, cfg.RateLimit.Wait.Duration, now),
+		})
+	}
+
+	if cfg.Output == "json" {
+		return reportOnBus(renderJSON(report, cfg.JQCommand))
+	}
+
+	return reportOnBus(renderDoctorTable(report, now), nil)
+}
+
+// describeRateLimit summarizes whether binny can make requests under the rate limit, and what happens when it cannot.
+func describeRateLimit(limit internalhttp.RateLimit, wait time.Duration, now time.Time) string {
+	switch {
+	case !limit.Exhausted(now):
+		if limit.Low() && limit.Reset.After(now) {
+			return "low"
+		}
+		return "ok"
+	case limit.Reset.Sub(now) <= wait:
+		return "exhausted (requests wait for the reset)"
+	default:
+		return "exhausted (requests fail until the reset)"
+	}
+}
+
+func renderDoctorTable(report doctorReport, now time.Time) string {
+	var sb strings.Builder
+
+	if report.GitHubToken {
+		sb.WriteString("GitHub token:    set (GITHUB_TOKEN)\n")
+	} else {
+		sb.WriteString("GitHub token:    not set (GITHUB_TOKEN), requests are unauthenticated\n")
+	}
+	fmt.Fprintf(&sb, "Rate limit wait: %s\n\n", report.RateLimitWait)
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleLight)
+	t.Style().Options.DrawBorder = false
+	t.Style().Options.SeparateColumns = false
+
+	t.AppendHeader(table.Row{"Host", "Resource", "Remaining", "Limit", "Resets", "Status"})
+
+	for _, limit := range report.RateLimits {
+		t.AppendRow(table.Row{
+			limit.Host,
+			limit.Resource,
+			limit.Remaining,
+			limit.Limit,
+			fmt.Sprintf("%s (in %s)", limit.Reset.Local().Format(time.TimeOnly), limit.Reset.Sub(now).Round(time.Second)),
+			limit.Status,
+		})
+	}
+
+	sb.WriteString(t.Render())
+	return sb.String()
+}
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	internalhttp "github.com/anchore/binny/internal/http"
+)
+
+func Test_describeRateLimit(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		limit internalhttp.RateLimit
+		wait  time.Duration
+		want  string
+	}{
+		{
+			name:  "plenty remaining",
+			limit: internalhttp.RateLimit{Limit: 5000, Remaining: 4000, Reset: now.Add(time.Hour)},
+			want:  "ok",
+		},
+		{
+			name:  "running low",
+			limit: internalhttp.RateLimit{Limit: 5000, Remaining: 20, Reset: now.Add(time.Hour)},
+			want:  "low",
+		},
+		{
+			name:  "exhausted beyond the wait",
+			limit: internalhttp.RateLimit{Limit: 60, Remaining: 0, Reset: now.Add(time.Hour)},
+			wait:  5 * time.Minute,
+			want:  "exhausted (requests fail until the reset)",
+		},
+		{
+			name:  "exhausted within the wait",
+			limit: internalhttp.RateLimit{Limit: 60, Remaining: 0, Reset: now.Add(time.Minute)},
+			wait:  5 * time.Minute,
+			want:  "exhausted (requests wait for the reset)",
+		},
+		{
+			name:  "reset already passed",
+			limit: internalhttp.RateLimit{Limit: 60, Remaining: 0, Reset: now.Add(-time.Minute)},
+			want:  "ok",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, describeRateLimit(tt.limit, tt.wait, now))
+		})
+	}
+}
@@ -22,17 +22,19 @@ import (
 )
 
 type InstallConfig struct {
-	Config          string `json:"config" yaml:"config" mapstructure:"config"`
-	StopOnError     bool   `json:"stopOnError" yaml:"stopOnError" mapstructure:"stopOnError"`
-	option.Cooldown `json:"" yaml:",inline" mapstructure:",squash"`
-	option.Check    `json:"" yaml:",inline" mapstructure:",squash"`
-	option.Core     `json:"" yaml:",inline" mapstructure:",squash"`
+	Config             string `json:"config" yaml:"config" mapstructure:"config"`
+	StopOnError        bool   `json:"stopOnError" yaml:"stopOnError" mapstructure:"stopOnError"`
+	option.Cooldown    `json:"" yaml:",inline" mapstructure:",squash"`
+	option.Check       `json:"" yaml:",inline" mapstructure:",squash"`
+	option.Core        `json:"" yaml:",inline" mapstructure:",squash"`
+	option.Concurrency `json:"" yaml:",inline" mapstructure:",squash"`
 }
 
 func Install(app clio.Application) *cobra.Command {
 	cfg := &InstallConfig{
 		StopOnError: false,
 		Core:        option.DefaultCore(),
+		Concurrency: option.DefaultConcurrency(),
 	}
 
 	var names []string
@@ -46,7 +48,7 @@ func Install(app clio.Application) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runInstall(cmd.Context(), *cfg, names)
+			return runInstall(withHTTPClient(cmd.Context(), cfg.Core), *cfg, names)
 		},
 	}, cfg)
 }
@@ -60,11 +62,17 @@ func runInstall(ctx context.Context, cmdCfg InstallConfig, names []string) error
 		return nil
 	}
 
+	// report every policy violation up front, before anything is installed
+	if err := cmdCfg.toolOptions().CheckPolicy(toolOpts...); err != nil {
+		return err
+	}
+
 	// get the current store state
 	store, err := binny.NewStore(cmdCfg.Root)
 	if err != nil {
 		return err
 	}
+	store.SetAuditActor(auditActor(ctx))
 
 	var (
 		errs                  error
@@ -89,7 +97,7 @@ func runInstall(ctx context.Context, cmdCfg InstallConfig, names []string) error
 	}()
 
 	g := errgroup.Group{}
-	g.SetLimit(3)
+	g.SetLimit(cmdCfg.Parallelism)
 	lock := sync.Mutex{}
 
 	for i := range toolOpts {
@@ -137,7 +145,9 @@ func runInstall(ctx context.Context, cmdCfg InstallConfig, names []string) error
 func (c InstallConfig) toolOptions() option.ToolOptions {
 	return option.DefaultToolOptions().
 		WithGlobalCooldown(c.Core.Cooldown).
-		WithIgnoreCooldown(c.IgnoreCooldown)
+		WithGlobalPrerelease(c.Core.Prerelease).
+		WithIgnoreCooldown(c.IgnoreCooldown).
+		WithPolicy(c.Core.Policy)
 }
 
 func trackInstallCmd(toolNames []string) (*progress.Manual, *progress.AtomicStage) {
@@ -168,7 +178,9 @@ func installTool(ctx context.Context, store *binny.Store, cfg InstallConfig, opt
 	// otherwise continue to install the tool
 	if err := tool.Install(ctx, t, *intent, store, tool.VerifyConfig{
 		VerifyXXH64Digest:  true,
+		VerifyLinkage:      true,
 		VerifySHA256Digest: cfg.VerifySHA256Digest,
+		RequireChecksum:    opt.RequiresChecksum(cfg.Core.RequireChecksum) || cfg.Core.Policy.RequireChecksum,
 	}); err != nil {
 		return fmt.Errorf("failed to install tool %q: %w", t.Name(), err)
 	}
@@ -17,6 +17,7 @@ import (
 	"github.com/anchore/binny"
 	"github.com/anchore/binny/cmd/binny/cli/option"
 	"github.com/anchore/binny/internal/bus"
+	"github.com/anchore/binny/internal/log"
 	"github.com/anchore/binny/tool"
 	"github.com/anchore/clio"
 )
@@ -33,7 +34,8 @@ type ListConfig struct {
 // applies cooldown so that status reflects what install/update would produce.
 func (c ListConfig) toolOptions() option.ToolOptions {
 	return option.DefaultToolOptions().
-		WithGlobalCooldown(c.Cooldown)
+		WithGlobalCooldown(c.Cooldown).
+		WithGlobalPrerelease(c.Prerelease)
 }
 
 func List(app clio.Application) *cobra.Command {
@@ -60,20 +62,22 @@ func List(app clio.Application) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runList(cmd.Context(), *cfg)
+			return runList(withHTTPClient(cmd.Context(), cfg.Core), *cfg)
 		},
 	}, cfg)
 }
 
 type toolStatus struct {
 	Name             string `json:"name"`
-	WantVersion      string `json:"wantVersion"`      // this is the version the user asked for
-	ResolvedVersion  string `json:"resolvedVersion"`  // if the user asks for a non-specific version (e.g. "latest") then this is what that would resolve to at this point in time
-	InstalledVersion string `json:"installedVersion"` // the actual version that is installed, which could vary from the user wanted or resolved values
-	Constraint       string `json:"constraint"`       // the version constraint the user asked for and used during version resolution
-	IsInstalled      bool   `json:"isInstalled"`      // is the tool installed at the desired version (says nothing about it being valid, only present)
-	HashIsValid      bool   `json:"hashIsValid"`      // is the installed tool have the correct xxh64 hash?
-	Error            error  `json:"error,omitempty"`  // if there was an error getting the status for this tool, it will be here
+	WantVersion      string `json:"wantVersion"`        // this is the version the user asked for
+	ResolvedVersion  string `json:"resolvedVersion"`    // if the user asks for a non-specific version (e.g. "latest") then this is what that would resolve to at this point in time
+	InstalledVersion string `json:"installedVersion"`   // the actual version that is installed, which could vary from the user wanted or resolved values
+	Constraint       string `json:"constraint"`         // the version constraint the user asked for and used during version resolution
+	IsInstalled      bool   `json:"isInstalled"`        // is the tool installed at the desired version (says nothing about it being valid, only present)
+	HashIsValid      bool   `json:"hashIsValid"`        // is the installed tool have the correct xxh64 hash?
+	Blocked          string `json:"blocked,omitempty"`  // why the installed version should no longer be used (e.g. retracted upstream or on the skip list)
+	Strategy         string `json:"strategy,omitempty"` // how the resolved version was chosen as the latest version (e.g. "github-latest"), when the version resolver reports one
+	Error            error  `json:"error,omitempty"`    // if there was an error getting the status for this tool, it will be here
 }
 
 func runList(ctx context.Context, cmdCfg ListConfig) error {
@@ -147,7 +151,7 @@ func filterToolsWithoutUpdates(statuses []toolStatus) []toolStatus {
 			continue
 		}
 
-		if !status.HashIsValid {
+		if !status.HashIsValid || status.Blocked != "" {
 			updates = append(updates, status)
 		}
 	}
@@ -162,6 +166,11 @@ func getAllStatuses(ctx context.Context, cmdCfg ListConfig, store *binny.Store,
 
 	names, toolOpts := selectNamesAndConfigs(cmdCfg.Core, nil)
 
+	// only tools that want the latest version are resolved
+	ctx = prefetchReleases(ctx, toolOpts, opts, func(intent binny.VersionIntent) bool {
+		return intent.Want == "latest"
+	})
+
 	storedEntries := store.Entries()
 
 	for _, opt := range toolOpts {
@@ -230,6 +239,7 @@ func getStatus(ctx context.Context, store *binny.Store, opt option.Tool, opts op
 	var (
 		isHashValid      bool
 		installedVersion string
+		blocked          string
 		isInstalled      = len(entries) == 1
 		entry            *binny.StoreEntry
 	)
@@ -241,9 +251,11 @@ func getStatus(ctx context.Context, store *binny.Store, opt option.Tool, opts op
 		if err != nil {
 			return nil, nil, err
 		}
+
+		blocked = getBlockedReason(ctx, t, *intent, installedVersion)
 	}
 
-	resolvedVersion, err := tool.ResolveVersion(ctx, t, *intent)
+	resolvedVersion, strategy, err := tool.ResolveVersionWithStrategy(ctx, t, *intent)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -255,10 +267,39 @@ func getStatus(ctx context.Context, store *binny.Store, opt option.Tool, opts op
 		Constraint:       opt.Version.Constraint,
 		IsInstalled:      isInstalled,
 		HashIsValid:      isHashValid,
+		Blocked:          blocked,
+		Strategy:         string(strategy),
 		InstalledVersion: installedVersion,
 	}, entry, nil
 }
 
+// getBlockedReason describes why the installed version should no longer be used (it is on the skip list or was
+// retracted upstream). An empty string means there is no known problem with the installed version.
+func getBlockedReason(ctx context.Context, t binny.Tool, intent binny.VersionIntent, installedVersion string) string {
+	if skipped, err := intent.Filter().IsSkipped(installedVersion); err == nil && skipped {
+		return "on the skip list"
+	}
+
+	checker, ok := t.(binny.RetractionChecker)
+	if !ok {
+		return ""
+	}
+
+	rationale, retracted, err := checker.Retraction(ctx, installedVersion)
+	if err != nil {
+		log.WithFields("tool", t.Name(), "version", installedVersion).Debugf("unable to check for retraction: %v", err)
+		return ""
+	}
+
+	switch {
+	case !retracted:
+		return ""
+	case rationale != "":
+		return fmt.Sprintf("retracted: %s", rationale)
+	}
+	return "retracted"
+}
+
 func getInstallationStatus(entry binny.StoreEntry) (installedVersion string, isHashValid bool, err error) {
 	installedVersion = entry.InstalledVersion
 
@@ -293,13 +334,16 @@ func renderListJSON(statuses []toolStatus, updatesOnly bool, jqCommand string) (
 		statuses = filterToolsWithoutUpdates(statuses)
 	}
 
-	doc := make(map[string]any)
 	if statuses == nil {
 		// always allocate collections
 		statuses = make([]toolStatus, 0)
 	}
-	doc["tools"] = statuses
 
+	return renderJSON(map[string]any{"tools": statuses}, jqCommand)
+}
+
+// renderJSON encodes the document, applying the JQ command to it (if given).
+func renderJSON(doc any, jqCommand string) (string, error) {
 	buf := bytes.Buffer{}
 	enc := json.NewEncoder(&buf)
 	enc.SetIndent("", "  ")
@@ -356,19 +400,30 @@ func renderListUpdatesTable(statuses []toolStatus) string {
 	t.Style().Options.DrawBorder = false
 	t.Style().Options.SeparateColumns = false
 
+	var strategyNeeded bool
+	for _, status := range statuses {
+		if status.Strategy != "" {
+			strategyNeeded = true
+			break
+		}
+	}
+
 	titles := []string{
-		"Tool", "Update",
+		"Tool", "Update", "Strategy",
 	}
 
 	var header table.Row
 	for _, title := range titles {
+		if title == "Strategy" && !strategyNeeded {
+			continue
+		}
 		header = append(header, title)
 	}
 	t.AppendHeader(header)
 
 	var rows []table.Row
 	for _, status := range statuses {
-		row := getToolUpdatesRow(status)
+		row := getToolUpdatesRow(status, strategyNeeded)
 		if row != nil {
 			rows = append(rows, row)
 		}
@@ -390,7 +445,7 @@ func renderListUpdatesTable(statuses []toolStatus) string {
 	return t.Render()
 }
 
-func getToolUpdatesRow(item toolStatus) table.Row {
+func getToolUpdatesRow(item toolStatus, strategyNeeded bool) table.Row {
 	var (
 		commentary string
 		style      lipgloss.Style
@@ -408,6 +463,12 @@ func getToolUpdatesRow(item toolStatus) table.Row {
 				commentary = ""
 			case item.InstalledVersion != item.ResolvedVersion:
 				commentary = fmt.Sprintf("%s → %s", summarizeGitVersion(item.InstalledVersion), summarizeGitVersion(item.ResolvedVersion))
+				if item.Blocked != "" {
+					commentary += fmt.Sprintf(" (installed version is %s)", item.Blocked)
+				}
+			case item.Blocked != "":
+				commentary = fmt.Sprintf("installed version (%s) is %s", summarizeGitVersion(item.InstalledVersion), item.Blocked)
+				style = badStatus
 			case !item.HashIsValid:
 				commentary = ""
 			}
@@ -423,6 +484,10 @@ func getToolUpdatesRow(item toolStatus) table.Row {
 		style.Render(commentary),
 	}
 
+	if strategyNeeded {
+		row = append(row, item.Strategy)
+	}
+
 	return row
 }
 
@@ -501,6 +566,9 @@ func getToolStatusRow(item toolStatus, constraintNeeded bool) table.Row {
 			case item.WantVersion == "?":
 				commentary = "tool is not configured"
 				severity = 2
+			case item.Blocked != "":
+				commentary = fmt.Sprintf("installed version (%s) is %s", summarizeGitVersion(item.InstalledVersion), item.Blocked)
+				severity = 2
 			case item.InstalledVersion != item.ResolvedVersion:
 				commentary = fmt.Sprintf("installed version (%s) does not match resolved version (%s)", summarizeGitVersion(item.InstalledVersion), summarizeGitVersion(item.ResolvedVersion))
 				severity = 1
@@ -110,6 +110,58 @@ func renderListTableTestCases() []renderListTableTest {
 				},
 			},
 		},
+		{
+			name: "retracted installed version",
+			statuses: []toolStatus{
+				{
+					Name:             "syft",
+					WantVersion:      "v1.0.0",
+					ResolvedVersion:  "v1.0.0",
+					InstalledVersion: "v1.0.0",
+					IsInstalled:      true,
+					HashIsValid:      true,
+					Blocked:          "retracted: published with a broken build",
+					Error:            nil,
+				},
+			},
+		},
+		{
+			name: "skipped installed version with update",
+			statuses: []toolStatus{
+				{
+					Name:             "syft",
+					WantVersion:      "latest",
+					ResolvedVersion:  "v1.0.1",
+					InstalledVersion: "v1.0.0",
+					IsInstalled:      true,
+					HashIsValid:      true,
+					Blocked:          "on the skip list",
+					Error:            nil,
+				},
+			},
+		},
+		{
+			name: "update with latest strategy",
+			statuses: []toolStatus{
+				{
+					Name:             "syft",
+					WantVersion:      "latest",
+					ResolvedVersion:  "v1.0.0",
+					InstalledVersion: "v0.105.1",
+					IsInstalled:      true,
+					HashIsValid:      true,
+					Strategy:         "highest-semver",
+				},
+				{
+					Name:             "grype",
+					WantVersion:      "latest",
+					ResolvedVersion:  "v0.74.0",
+					InstalledVersion: "v0.53.0",
+					IsInstalled:      true,
+					HashIsValid:      true,
+				},
+			},
+		},
 		{
 			name: "sort by name",
 			statuses: []toolStatus{
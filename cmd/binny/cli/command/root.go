@@ -4,6 +4,7 @@ import (
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/spf13/cobra"
 
+	"github.com/anchore/binny"
 	internalhttp "github.com/anchore/binny/internal/http"
 	"github.com/anchore/binny/internal/log"
 	"github.com/anchore/clio"
@@ -34,6 +35,9 @@ func Root(app clio.Application) *cobra.Command {
 		httpClient.Logger = internalhttp.NewLeveledLogger(lgr.Nested("component", "http-client"))
 		ctx = internalhttp.WithHTTPClient(ctx, httpClient)
 
+		// changes to the store are attributed to this invocation in the audit log
+		ctx = withAuditActor(ctx, binny.NewAuditActor(app.ID().Version))
+
 		cmd.SetContext(ctx)
 
 		return nil
@@ -9,6 +9,7 @@ import (
 
 	"github.com/anchore/binny"
 	"github.com/anchore/binny/cmd/binny/cli/option"
+	"github.com/anchore/binny/internal/sandbox"
 	"github.com/anchore/clio"
 )
 
@@ -23,13 +24,21 @@ func Run(app clio.Application) *cobra.Command {
 	}
 
 	var isHelpFlag bool
+	var flags option.ToolRun
 
 	return app.SetupCommand(&cobra.Command{
-		Use:                "run NAME [flags] [args]",
-		Short:              "run a specific tool",
+		Use:   "run [--sandbox] [--deny-network] NAME [flags] [args]",
+		Short: "run a specific tool",
+		Long: `Run a specific tool with the given arguments.
+
+Flags before the tool name:
+  --sandbox       only allow writing to the working tree and temp directories (linux only)
+  --deny-network  run without network access in its own network namespace (implies --sandbox); when user
+                  namespaces are unavailable only TCP connections are denied, with a warning`,
 		DisableFlagParsing: true, // pass these as arguments to the tool
 		Args:               cobra.ArbitraryArgs,
 		PreRunE: func(_ *cobra.Command, args []string) error {
+			flags, args = parseRunFlags(args)
 			if len(args) == 0 {
 				return fmt.Errorf("no tool name provided")
 			}
@@ -49,6 +58,7 @@ func Run(app clio.Application) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			_, args = parseRunFlags(args)
 			var toolArgs []string
 			if len(args) > 1 {
 				toolArgs = args[1:]
@@ -58,12 +68,29 @@ func Run(app clio.Application) *cobra.Command {
 				return cmd.Help()
 			}
 
-			return runRunRUN(*cfg, args[0], toolArgs)
+			return runRunRUN(*cfg, args[0], toolArgs, flags)
 		},
 	}, cfg)
 }
 
-func runRunRUN(cfg RunConfig, name string, args []string) error {
+// parseRunFlags consumes the binny flags that precede the tool name, everything after is passed to the tool.
+func parseRunFlags(args []string) (option.ToolRun, []string) {
+	var flags option.ToolRun
+	for len(args) > 0 {
+		switch args[0] {
+		case "--sandbox":
+			flags.Sandbox = true
+		case "--deny-network":
+			flags.DenyNetwork = true
+		default:
+			return flags, args
+		}
+		args = args[1:]
+	}
+	return flags, args
+}
+
+func runRunRUN(cfg RunConfig, name string, args []string, flags option.ToolRun) error {
 	store, err := binny.NewStore(cfg.Root)
 	if err != nil {
 		return err
@@ -86,5 +113,31 @@ func runRunRUN(cfg RunConfig, name string, args []string) error {
 		return fmt.Errorf("unable to resolve path to tool: %w", err)
 	}
 
-	return run(fullPath, args)
+	sandboxCfg, err := runSandbox(cfg, name, flags)
+	if err != nil {
+		return err
+	}
+
+	return run(fullPath, args, sandboxCfg)
+}
+
+// runSandbox returns the sandbox restrictions for the tool (from the command flags and the tool configuration), or
+// nil when the tool is not sandboxed.
+func runSandbox(cfg RunConfig, name string, flags option.ToolRun) (*sandbox.Config, error) {
+	runCfg := flags
+	if tool := cfg.Tools.GetOption(name); tool != nil {
+		runCfg.Sandbox = runCfg.Sandbox || tool.Run.Sandbox
+		runCfg.DenyNetwork = runCfg.DenyNetwork || tool.Run.DenyNetwork
+		runCfg.Writable = tool.Run.Writable
+	}
+
+	if !runCfg.Sandboxed() {
+		return nil, nil
+	}
+
+	sandboxCfg, err := runCfg.SandboxConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure the sandbox: %w", err)
+	}
+	return &sandboxCfg, nil
 }
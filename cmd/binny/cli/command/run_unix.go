@@ -12,12 +12,26 @@ import (
 
 	"github.com/creack/pty"
 	"golang.org/x/term"
+
+	"github.com/anchore/binny/internal/sandbox"
 )
 
-func run(path string, args []string) error {
+func run(path string, args []string, sandboxCfg *sandbox.Config) error {
 	c := exec.Command(path, args...)
 
-	ptmx, err := pty.Start(c)
+	var ptmx *os.File
+	start := func() error {
+		var err error
+		ptmx, err = pty.Start(c)
+		return err
+	}
+
+	var err error
+	if sandboxCfg != nil {
+		err = sandbox.Start(*sandboxCfg, c, start)
+	} else {
+		err = start()
+	}
 	if err != nil {
 		return err
 	}
@@ -3,11 +3,19 @@ package command
 import (
 	"os"
 	"os/exec"
+
+	"github.com/anchore/binny/internal/sandbox"
 )
 
-func run(path string, args []string) error {
+func run(path string, args []string, sandboxCfg *sandbox.Config) error {
 	c := exec.Command(path, args...)
 	c.Stdout = os.Stdout
 	c.Stderr = os.Stderr
-	return c.Run()
+	if sandboxCfg == nil {
+		return c.Run()
+	}
+	if err := sandbox.Start(*sandboxCfg, c, c.Start); err != nil {
+		return err
+	}
+	return c.Wait()
 }
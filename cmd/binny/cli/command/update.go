@@ -13,6 +13,7 @@ import (
 	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v3"
 
+	"github.com/anchore/binny"
 	"github.com/anchore/binny/cmd/binny/cli/internal/yamlpatch"
 	"github.com/anchore/binny/cmd/binny/cli/option"
 	"github.com/anchore/binny/event"
@@ -23,22 +24,26 @@ import (
 )
 
 type UpdateConfig struct {
-	Config          string `json:"config" yaml:"config" mapstructure:"config"`
-	StopOnError     bool   `json:"stopOnError" yaml:"stopOnError" mapstructure:"stopOnError"`
-	option.Cooldown `json:"" yaml:",inline" mapstructure:",squash"`
-	option.Core     `json:"" yaml:",inline" mapstructure:",squash"`
+	Config             string `json:"config" yaml:"config" mapstructure:"config"`
+	StopOnError        bool   `json:"stopOnError" yaml:"stopOnError" mapstructure:"stopOnError"`
+	option.Cooldown    `json:"" yaml:",inline" mapstructure:",squash"`
+	option.Core        `json:"" yaml:",inline" mapstructure:",squash"`
+	option.Concurrency `json:"" yaml:",inline" mapstructure:",squash"`
 }
 
 func (c UpdateConfig) toolOptions() option.ToolOptions {
 	return option.DefaultToolOptions().
 		WithGlobalCooldown(c.Core.Cooldown).
-		WithIgnoreCooldown(c.IgnoreCooldown)
+		WithGlobalPrerelease(c.Core.Prerelease).
+		WithIgnoreCooldown(c.IgnoreCooldown).
+		WithPolicy(c.Core.Policy)
 }
 
 func Update(app clio.Application) *cobra.Command {
 	cfg := &UpdateConfig{
 		StopOnError: false,
 		Core:        option.DefaultCore(),
+		Concurrency: option.DefaultConcurrency(),
 	}
 
 	var names []string
@@ -52,7 +57,7 @@ func Update(app clio.Application) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runUpdate(cmd.Context(), *cfg, names)
+			return runUpdate(withHTTPClient(cmd.Context(), cfg.Core), *cfg, names)
 		},
 	}, cfg)
 }
@@ -80,6 +85,19 @@ func (p updateLockYamlPatcher) PatchYaml(node *yaml.Node) error {
 	for _, toolCfg := range p.cfg.Tools {
 		toolVersionWantNode := yamlpatch.FindToolVersionWantNode(toolsNode, toolCfg.Name)
 		toolVersionWantNode.Value = toolCfg.Version.Want
+		if toolCfg.Version.Ref != "" {
+			yamlpatch.SetToolVersionValue(toolsNode, toolCfg.Name, "ref", toolCfg.Version.Ref)
+		}
+		if checksum := toolCfg.ScriptChecksum(); checksum != "" {
+			yamlpatch.SetToolParameterValue(toolsNode, toolCfg.Name, []string{"script-checksum"}, checksum)
+		}
+		pinned, err := toolCfg.PinnedChecksums()
+		if err != nil {
+			return err
+		}
+		for _, platform := range pinned.Platforms() {
+			yamlpatch.SetToolParameterValue(toolsNode, toolCfg.Name, []string{"checksums", platform}, pinned[platform])
+		}
 	}
 	return nil
 }
@@ -94,6 +112,16 @@ func getUpdatedConfig(ctx context.Context, cfg UpdateConfig, names []string) (*o
 
 	names, ogCfgs := selectNamesAndConfigs(cfg.Core, names)
 
+	// report every policy violation up front, before any tool is updated
+	if err := cfg.toolOptions().CheckPolicy(ogCfgs...); err != nil {
+		return nil, err
+	}
+
+	// pinned versions are updated from the full list of releases
+	ctx = prefetchReleases(ctx, ogCfgs, cfg.toolOptions(), func(intent binny.VersionIntent) bool {
+		return intent.Want != "latest" && intent.Filter().IsVersion(intent.Want)
+	})
+
 	prog, stage := trackUpdateLockCmd(names)
 
 	defer func() {
@@ -108,7 +136,7 @@ func getUpdatedConfig(ctx context.Context, cfg UpdateConfig, names []string) (*o
 	}()
 
 	g := errgroup.Group{}
-	g.SetLimit(3)
+	g.SetLimit(cfg.Parallelism)
 	lock := sync.Mutex{}
 
 	for i := range ogCfgs {
@@ -133,6 +161,12 @@ func getUpdatedConfig(ctx context.Context, cfg UpdateConfig, names []string) (*o
 
 			tProg.Increment()
 			newVersion, err = getUpdatedToolVersion(ctx, toolCfg, cfg.toolOptions())
+			if err == nil {
+				toolCfg, err = getUpdatedScriptChecksum(ctx, toolCfg, cfg.toolOptions())
+			}
+			if err == nil {
+				toolCfg, err = getUpdatedChecksums(ctx, toolCfg, newVersion, cfg.toolOptions())
+			}
 
 			lock.Lock()
 
@@ -148,7 +182,7 @@ func getUpdatedConfig(ctx context.Context, cfg UpdateConfig, names []string) (*o
 				if *newVersion == ogVersion {
 					newVersion = nil
 				} else {
-					toolCfg.Version.Want = *newVersion
+					toolCfg.Version = toolCfg.Version.WithUpdatedWant(*newVersion)
 				}
 			} else {
 				alreadyUpToDateTools = append(alreadyUpToDateTools, toolCfg.Name)
@@ -265,7 +299,8 @@ func getUpdatedToolVersion(ctx context.Context, toolCfg option.Tool, opts option
 		return nil, fmt.Errorf("unable to update version for tool %q: %w", toolCfg.Name, err)
 	}
 
-	if newVersion == toolCfg.Version.Want {
+	// note: the intent holds the want normalized to a version (the config may express it as a tag)
+	if newVersion == intent.Want {
 		fields := logger.Fields{
 			"tool":    toolCfg.Name,
 			"version": toolCfg.Version.Want,
@@ -290,3 +325,94 @@ func getUpdatedToolVersion(ctx context.Context, toolCfg option.Tool, opts option
 
 	return &newVersion, nil
 }
+
+// getUpdatedScriptChecksum refreshes the digest that the install script is pinned to (if any). Scripts are typically
+// served from a branch and change independently of the tool version, so this is done on every update.
+func getUpdatedScriptChecksum(ctx context.Context, toolCfg option.Tool, opts option.ToolOptions) (option.Tool, error) {
+	pinned := toolCfg.ScriptChecksum()
+	if pinned == "" {
+		return toolCfg, nil
+	}
+
+	t, _, err := toolCfg.ToTool(opts)
+	if err != nil {
+		return toolCfg, err
+	}
+
+	pinner, ok := t.(binny.ScriptChecksumPinner)
+	if !ok {
+		return toolCfg, fmt.Errorf("tool %q does not support a script checksum", toolCfg.Name)
+	}
+
+	checksum, err := pinner.PinScriptChecksum(ctx, pinned)
+	if err != nil {
+		return toolCfg, fmt.Errorf("unable to update script checksum for tool %q: %w", toolCfg.Name, err)
+	}
+
+	if checksum == pinned {
+		return toolCfg, nil
+	}
+
+	log.WithFields("tool", toolCfg.Name, "checksum", fmt.Sprintf("%s ➔ %s", pinned, checksum)).Info("updated script checksum")
+
+	return toolCfg.WithScriptChecksum(checksum), nil
+}
+
+// getUpdatedChecksums refreshes the checksums pinned for the tool (if any) when the version has been updated, or when a
+// platform has been listed without a checksum yet.
+func getUpdatedChecksums(ctx context.Context, toolCfg option.Tool, newVersion *string, opts option.ToolOptions) (option.Tool, error) {
+	pinned, err := toolCfg.PinnedChecksums()
+	if err != nil || len(pinned) == 0 {
+		return toolCfg, err
+	}
+
+	version := toolCfg.Version.Want
+	if newVersion != nil {
+		version = *newVersion
+	} else if !hasMissingChecksum(pinned) {
+		return toolCfg, nil
+	}
+
+	t, intent, err := toolCfg.ToTool(opts)
+	if err != nil {
+		return toolCfg, err
+	}
+
+	pinner, ok := t.(binny.ChecksumPinner)
+	if !ok {
+		return toolCfg, fmt.Errorf("tool %q does not support pinned checksums", toolCfg.Name)
+	}
+
+	tag, err := intent.Tags.Tag(intent.Tags.Normalize(version))
+	if err != nil {
+		return toolCfg, err
+	}
+
+	refreshed, err := pinner.PinChecksums(ctx, tag, pinned)
+	if err != nil {
+		return toolCfg, fmt.Errorf("unable to update pinned checksums for tool %q: %w", toolCfg.Name, err)
+	}
+
+	if newVersion == nil {
+		// the version has not changed, so existing pins are still valid and must not be cleared by an installer that
+		// cannot refresh them from this platform
+		for platform, checksum := range refreshed {
+			if checksum == "" && pinned[platform] != "" {
+				delete(refreshed, platform)
+			}
+		}
+	}
+
+	log.WithFields("tool", toolCfg.Name, "platforms", refreshed.Platforms()).Info("updated pinned checksums")
+
+	return toolCfg.WithPinnedChecksums(refreshed), nil
+}
+
+func hasMissingChecksum(pinned binny.PinnedChecksums) bool {
+	for _, checksum := range pinned {
+		if checksum == "" {
+			return true
+		}
+	}
+	return false
+}
@@ -2,16 +2,20 @@ package command
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 
 	"github.com/mitchellh/mapstructure"
 	"gopkg.in/yaml.v3"
 
+	"github.com/anchore/binny"
 	"github.com/anchore/binny/cmd/binny/cli/internal/yamlpatch"
 	"github.com/anchore/binny/cmd/binny/cli/option"
 	"github.com/anchore/binny/internal/bus"
+	internalhttp "github.com/anchore/binny/internal/http"
 	"github.com/anchore/binny/internal/log"
+	"github.com/anchore/binny/tool"
 )
 
 func toMap(s any) (map[string]any, error) {
@@ -41,6 +45,50 @@ func toMap(s any) (map[string]any, error) {
 	return m, nil
 }
 
+// withHTTPClient layers the per-host request limits, rate limit tracking, and the on-disk response cache into the HTTP
+// client in the context (the cache sits above the rest, so cached responses are neither limited nor mistaken for the
+// current rate limit state). Failing to set up the cache is not fatal, requests are made without it.
+func withHTTPClient(ctx context.Context, core option.Core) context.Context {
+	ctx = internalhttp.WithLimiter(ctx, core.NewLimiter())
+	ctx = internalhttp.WithRateLimits(ctx, core.NewRateLimits())
+
+	cache, err := core.HTTPCache.NewCache()
+	if err != nil {
+		log.WithFields("error", err).Warn("unable to set up the HTTP cache, continuing without it")
+		return ctx
+	}
+	return internalhttp.WithCache(ctx, cache)
+}
+
+// prefetchReleases lists the GitHub releases of the tools that need them to resolve a version (as decided by needed)
+// ahead of time, batching many repositories into each query instead of querying once per tool.
+func prefetchReleases(ctx context.Context, toolCfgs []option.Tool, opts option.ToolOptions, needed func(binny.VersionIntent) bool) context.Context {
+	var resolvers []binny.VersionResolver
+	for _, toolCfg := range toolCfgs {
+		t, intent, err := toolCfg.ToTool(opts)
+		if err != nil || !needed(*intent) {
+			// errors are reported when the tool itself is processed
+			continue
+		}
+		resolvers = append(resolvers, t)
+	}
+	return tool.PrefetchReleases(ctx, resolvers...)
+}
+
+type auditActorKey struct{}
+
+func withAuditActor(ctx context.Context, actor binny.AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// auditActor returns who the changes made to the store are attributed to in the audit log.
+func auditActor(ctx context.Context) binny.AuditActor {
+	if actor, ok := ctx.Value(auditActorKey{}).(binny.AuditActor); ok {
+		return actor
+	}
+	return binny.NewAuditActor("")
+}
+
 var _ yamlpatch.Patcher = (*yamlToolAppender)(nil)
 
 type yamlToolAppender struct {
@@ -64,6 +112,14 @@ func (p yamlToolAppender) PatchYaml(node *yaml.Node) error {
 	return nil
 }
 
+// checkNewToolPolicy evaluates a tool that is about to be added to the configuration against the configured policy.
+func checkNewToolPolicy(core option.Core, cfg option.Tool) error {
+	return option.DefaultToolOptions().
+		WithGlobalCooldown(core.Cooldown).
+		WithPolicy(core.Policy).
+		CheckPolicy(cfg)
+}
+
 func updateConfiguration(path string, cfg option.Tool) error {
 	if path == "" {
 		path = ".binny.yaml"
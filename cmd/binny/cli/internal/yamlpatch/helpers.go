@@ -11,6 +11,78 @@ func FindToolVersionWantNode(toolsNode *yaml.Node, toolName string) *yaml.Node {
 	return toolVersionWantNode
 }
 
+// SetToolVersionValue sets the value for the given key within the version section of a tool, adding the key when it
+// is not already present.
+func SetToolVersionValue(toolsNode *yaml.Node, toolName, key, value string) {
+	toolNode := FindToolNode(toolsNode, toolName)
+	if toolNode == nil {
+		return
+	}
+	toolVersionNode := findToolVersionNode(toolNode)
+	if toolVersionNode == nil {
+		return
+	}
+
+	for idx, v := range toolVersionNode.Content {
+		if idx%2 == 0 && v.Value == key {
+			toolVersionNode.Content[idx+1].Value = value
+			return
+		}
+	}
+
+	toolVersionNode.Content = append(toolVersionNode.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value},
+	)
+}
+
+// SetToolParameterValue sets the value at the given path of keys within the install parameters ("with") of a tool,
+// adding any maps and keys that are not already present.
+func SetToolParameterValue(toolsNode *yaml.Node, toolName string, path []string, value string) {
+	node := FindToolNode(toolsNode, toolName)
+	if node == nil {
+		return
+	}
+
+	for _, key := range append([]string{"with"}, path[:len(path)-1]...) {
+		node = findOrAddMapNode(node, key)
+	}
+	setMapValue(node, path[len(path)-1], value)
+}
+
+func findOrAddMapNode(node *yaml.Node, key string) *yaml.Node {
+	for idx, v := range node.Content {
+		if idx%2 == 0 && v.Value == key {
+			next := node.Content[idx+1]
+			if next.Kind != yaml.MappingNode {
+				// e.g. an empty value ("with:"), which is null
+				*next = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+			return next
+		}
+	}
+
+	next := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, next)
+	return next
+}
+
+func setMapValue(node *yaml.Node, key, value string) {
+	for idx, v := range node.Content {
+		if idx%2 == 0 && v.Value == key {
+			node.Content[idx+1].Kind = yaml.ScalarNode
+			node.Content[idx+1].Tag = "!!str"
+			node.Content[idx+1].Value = value
+			return
+		}
+	}
+
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value},
+	)
+}
+
 func FindToolsSequenceNode(node *yaml.Node) *yaml.Node {
 	for idx, v := range node.Content {
 		var next *yaml.Node
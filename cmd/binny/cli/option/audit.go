@@ -0,0 +1,38 @@
+package option
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anchore/clio"
+)
+
+type Audit struct {
+	// SinceRaw is the raw value of the --since flag. Use Since after PostLoad has been called.
+	SinceRaw      string       `json:"since" yaml:"since" mapstructure:"since"`
+	Since         JSONDuration `json:"-" yaml:"-" mapstructure:"-"`
+	IncludeFilter []string     `json:"includeFilter" yaml:"includeFilter" mapstructure:"includeFilter"`
+}
+
+func (o *Audit) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.SinceRaw, "since", "", "Only show changes made within the given duration (e.g. 7d or 12h)")
+}
+
+// PostLoad is called by fangs after config loading to parse raw config values.
+func (o *Audit) PostLoad() error {
+	if o.SinceRaw == "" {
+		return nil
+	}
+	if err := o.Since.ParseFrom(o.SinceRaw); err != nil {
+		return fmt.Errorf("invalid since value: %w", err)
+	}
+	return nil
+}
+
+// After returns the time that shown changes must be made after (zero when every change is shown).
+func (o Audit) After(now time.Time) time.Time {
+	if o.Since.Duration == 0 {
+		return time.Time{}
+	}
+	return now.Add(-o.Since.Duration)
+}
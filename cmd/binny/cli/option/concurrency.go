@@ -0,0 +1,98 @@
+package option
+
+import (
+	"fmt"
+
+	internalhttp "github.com/anchore/binny/internal/http"
+	"github.com/anchore/clio"
+)
+
+// defaultParallelism is how many tools are installed (or updated) at once.
+const defaultParallelism = 3
+
+// HostLimit bounds the requests made to a single host, across all tools being processed at once.
+type HostLimit struct {
+	Host string `json:"host" yaml:"host" mapstructure:"host"`
+	// MaxConcurrent is the number of requests to the host that can be in flight at once (0 is unlimited).
+	MaxConcurrent int `json:"max-concurrent" yaml:"max-concurrent,omitempty" mapstructure:"max-concurrent"`
+	// RequestsPerSecond is the rate that requests to the host can be started at (0 is unlimited).
+	RequestsPerSecond float64 `json:"requests-per-second" yaml:"requests-per-second,omitempty" mapstructure:"requests-per-second"`
+}
+
+// defaultHTTPLimits keep fan-out below the GitHub secondary rate limits (which penalize concurrent requests) while the
+// go proxy and checksum database are built for far more concurrent traffic.
+func defaultHTTPLimits() []HostLimit {
+	return []HostLimit{
+		{Host: "api.github.com", MaxConcurrent: 4, RequestsPerSecond: 10},
+		{Host: "github.com", MaxConcurrent: 8},
+		{Host: "proxy.golang.org", MaxConcurrent: 16},
+		{Host: "sum.golang.org", MaxConcurrent: 16},
+	}
+}
+
+// Concurrency configures how many tools are installed (or updated) at once.
+type Concurrency struct {
+	Parallelism int `json:"parallelism" yaml:"parallelism" mapstructure:"parallelism"`
+}
+
+func DefaultConcurrency() Concurrency {
+	return Concurrency{
+		Parallelism: defaultParallelism,
+	}
+}
+
+func (o *Concurrency) AddFlags(flags clio.FlagSet) {
+	flags.IntVarP(&o.Parallelism, "parallel", "", "Number of tools to process at once")
+}
+
+// PostLoad is called by fangs after config loading to validate the config values.
+func (o *Concurrency) PostLoad() error {
+	if o.Parallelism < 1 {
+		return fmt.Errorf("invalid parallelism %d: must be at least 1", o.Parallelism)
+	}
+	return nil
+}
+
+func validateHTTPLimits(limits []HostLimit) error {
+	for _, limit := range limits {
+		if limit.Host == "" {
+			return fmt.Errorf("invalid http-limits entry: no host given")
+		}
+		if limit.MaxConcurrent < 0 || limit.RequestsPerSecond < 0 {
+			return fmt.Errorf("invalid http-limits entry for %q: limits must be non-negative", limit.Host)
+		}
+	}
+	return nil
+}
+
+// EffectiveHTTPLimits returns the default host limits, overridden by any configured limits for the same host.
+func (c Core) EffectiveHTTPLimits() []HostLimit {
+	var limits []HostLimit
+	for _, limit := range defaultHTTPLimits() {
+		if !hasHostLimit(c.HTTPLimits, limit.Host) {
+			limits = append(limits, limit)
+		}
+	}
+	return append(limits, c.HTTPLimits...)
+}
+
+// NewLimiter creates the limiter that enforces the host limits on every HTTP request.
+func (c Core) NewLimiter() *internalhttp.Limiter {
+	limits := make(map[string]internalhttp.HostLimit)
+	for _, limit := range c.EffectiveHTTPLimits() {
+		limits[limit.Host] = internalhttp.HostLimit{
+			MaxConcurrent:     limit.MaxConcurrent,
+			RequestsPerSecond: limit.RequestsPerSecond,
+		}
+	}
+	return internalhttp.NewLimiter(limits)
+}
+
+func hasHostLimit(limits []HostLimit, host string) bool {
+	for _, limit := range limits {
+		if limit.Host == host {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package option
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_EffectiveHTTPLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits []HostLimit
+		want   []HostLimit
+	}{
+		{
+			name: "defaults",
+			want: defaultHTTPLimits(),
+		},
+		{
+			name: "override and extend the defaults",
+			limits: []HostLimit{
+				{Host: "api.github.com", MaxConcurrent: 1},
+				{Host: "example.com", RequestsPerSecond: 2},
+			},
+			want: []HostLimit{
+				{Host: "github.com", MaxConcurrent: 8},
+				{Host: "proxy.golang.org", MaxConcurrent: 16},
+				{Host: "sum.golang.org", MaxConcurrent: 16},
+				{Host: "api.github.com", MaxConcurrent: 1},
+				{Host: "example.com", RequestsPerSecond: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Core{HTTPLimits: tt.limits}.EffectiveHTTPLimits())
+		})
+	}
+}
+
+func TestCore_PostLoad_httpLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  []HostLimit
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:   "valid",
+			limits: []HostLimit{{Host: "example.com", MaxConcurrent: 1, RequestsPerSecond: 0.5}},
+		},
+		{
+			name:    "missing host",
+			limits:  []HostLimit{{MaxConcurrent: 1}},
+			wantErr: require.Error,
+		},
+		{
+			name:    "negative limit",
+			limits:  []HostLimit{{Host: "example.com", RequestsPerSecond: -1}},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			core := DefaultCore()
+			core.HTTPLimits = tt.limits
+			tt.wantErr(t, core.PostLoad())
+		})
+	}
+}
+
+func TestConcurrency_PostLoad(t *testing.T) {
+	require.NoError(t, (&Concurrency{Parallelism: 1}).PostLoad())
+	require.Error(t, (&Concurrency{Parallelism: 0}).PostLoad())
+}
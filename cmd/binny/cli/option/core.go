@@ -1,6 +1,10 @@
 package option
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/anchore/binny"
+)
 
 // Core options make up the static application configuration on disk.
 type Core struct {
@@ -9,12 +13,28 @@ type Core struct {
 	// Use Cooldown field after PostLoad has been called.
 	CooldownRaw any          `json:"cooldown" yaml:"cooldown,omitempty" mapstructure:"cooldown"`
 	Cooldown    JSONDuration `json:"-" yaml:"-" mapstructure:"-"`
-	Tools       Tools        `json:"tools" yaml:"tools" mapstructure:"tools"`
+	// PrereleaseRaw is the raw config value for the global prerelease policy (exclude, include, or only).
+	// Use Prerelease field after PostLoad has been called.
+	PrereleaseRaw string                 `json:"prerelease" yaml:"prerelease,omitempty" mapstructure:"prerelease"`
+	Prerelease    binny.PrereleasePolicy `json:"-" yaml:"-" mapstructure:"-"`
+	// RequireChecksum fails installations that download files without a checksum (or signature) to verify them with.
+	// Individual tools can override this value.
+	RequireChecksum bool      `json:"require-checksum" yaml:"require-checksum,omitempty" mapstructure:"require-checksum"`
+	Tools           Tools     `json:"tools" yaml:"tools" mapstructure:"tools"`
+	HTTPCache       HTTPCache `json:"http-cache" yaml:"http-cache" mapstructure:"http-cache"`
+	// Policy restricts the tools that can be added, updated, and installed (e.g. allowed install methods and hosts).
+	Policy Policy `json:"policy" yaml:"policy,omitempty" mapstructure:"policy"`
+	// HTTPLimits bound the requests made to each host (overriding the defaults for the same host).
+	HTTPLimits []HostLimit `json:"http-limits" yaml:"http-limits,omitempty" mapstructure:"http-limits"`
+	// RateLimit decides whether to wait for exhausted rate limits (e.g. of the GitHub API) to reset.
+	RateLimit `json:"" yaml:",inline" mapstructure:",squash"`
 }
 
 func DefaultCore() Core {
 	return Core{
-		Store: DefaultStore(),
+		Store:     DefaultStore(),
+		HTTPCache: DefaultHTTPCache(),
+		RateLimit: DefaultRateLimit(),
 	}
 }
 
@@ -23,5 +43,10 @@ func (c *Core) PostLoad() error {
 	if err := c.Cooldown.ParseFrom(c.CooldownRaw); err != nil {
 		return fmt.Errorf("invalid cooldown value: %w", err)
 	}
-	return nil
+	prerelease, err := binny.ParsePrereleasePolicy(c.PrereleaseRaw)
+	if err != nil {
+		return err
+	}
+	c.Prerelease = prerelease
+	return validateHTTPLimits(c.HTTPLimits)
 }
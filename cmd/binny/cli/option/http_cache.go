@@ -0,0 +1,69 @@
+package option
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	internalhttp "github.com/anchore/binny/internal/http"
+	"github.com/anchore/clio"
+)
+
+// defaultHTTPCacheTTL is how long version resolution responses are used before they are revalidated.
+const defaultHTTPCacheTTL = 15 * time.Minute
+
+// HTTPCache configures the on-disk cache for version resolution responses (GitHub API and go proxy documents).
+type HTTPCache struct {
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+	// Dir is where cached responses are stored (defaults to a "binny" directory in the user cache directory).
+	Dir string `json:"dir" yaml:"dir,omitempty" mapstructure:"dir"`
+	// TTLRaw is the raw config value for how long a cached response is used before it is revalidated.
+	// Use TTL field after PostLoad has been called.
+	TTLRaw  any          `json:"ttl" yaml:"ttl,omitempty" mapstructure:"ttl"`
+	TTL     JSONDuration `json:"-" yaml:"-" mapstructure:"-"`
+	Refresh bool         `json:"refresh" yaml:"refresh" mapstructure:"refresh"`
+}
+
+func DefaultHTTPCache() HTTPCache {
+	return HTTPCache{
+		Enabled: true,
+	}
+}
+
+func (o *HTTPCache) AddFlags(flags clio.FlagSet) {
+	flags.BoolVarP(&o.Refresh, "refresh", "", "Revalidate all cached HTTP responses with the server (ignoring the cache TTL)")
+}
+
+// PostLoad is called by fangs after config loading to parse raw config values.
+func (o *HTTPCache) PostLoad() error {
+	if err := o.TTL.ParseFrom(o.TTLRaw); err != nil {
+		return fmt.Errorf("invalid http-cache.ttl value: %w", err)
+	}
+	if !o.TTL.IsSet {
+		o.TTL = JSONDuration{Duration: defaultHTTPCacheTTL}
+	}
+	return nil
+}
+
+// NewCache creates the configured cache, returning nil when caching is disabled.
+func (o HTTPCache) NewCache() (*internalhttp.Cache, error) {
+	if !o.Enabled {
+		return nil, nil
+	}
+
+	dir := o.Dir
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine the user cache directory: %w", err)
+		}
+		dir = filepath.Join(userCacheDir, "binny", "http")
+	}
+
+	return internalhttp.NewCache(internalhttp.CacheConfig{
+		Dir:     dir,
+		TTL:     o.TTL.Duration,
+		Refresh: o.Refresh,
+	})
+}
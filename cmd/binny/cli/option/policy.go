@@ -0,0 +1,326 @@
+package option
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anchore/binny/internal/gosumdb"
+	"github.com/anchore/binny/tool/githubrelease"
+	"github.com/anchore/binny/tool/gobuild"
+	"github.com/anchore/binny/tool/goinstall"
+	"github.com/anchore/binny/tool/hostedshell"
+)
+
+// policy rules, named the same as their config keys
+const (
+	PolicyRuleAllowedInstallMethods = "allowed-install-methods"
+	PolicyRuleAllowedHosts          = "allowed-hosts"
+	PolicyRuleAllowedOwners         = "allowed-owners"
+	PolicyRuleAllowedRepos          = "allowed-repos"
+	PolicyRuleMinCooldown           = "min-cooldown"
+	PolicyRuleRequireChecksum       = "require-checksum"
+	PolicyRuleRequireSignature      = "require-signature"
+)
+
+// Policy restricts which tools can be configured and how they must be verified (e.g. as set by an organization). The
+// rules are either configured inline or in a separate policy file, which is easier to share between repositories.
+type Policy struct {
+	// File is a YAML file with the policy rules (the same keys as the inline rules), used instead of inline rules.
+	File string `json:"file" yaml:"file,omitempty" mapstructure:"file"`
+
+	PolicyRules `json:"" yaml:",inline" mapstructure:",squash"`
+}
+
+// PolicyRules are the rules that tools are evaluated against. Empty rules allow everything.
+type PolicyRules struct {
+	// AllowedInstallMethods is the install methods tools can use (e.g. "github-release").
+	AllowedInstallMethods []string `json:"allowed-install-methods" yaml:"allowed-install-methods,omitempty" mapstructure:"allowed-install-methods"`
+	// AllowedHosts is the hosts tools can be downloaded from (e.g. "github.com"), matched with path.Match patterns.
+	AllowedHosts []string `json:"allowed-hosts" yaml:"allowed-hosts,omitempty" mapstructure:"allowed-hosts"`
+	// AllowedOwners is the GitHub owners (users or organizations) tools can come from, matched with path.Match patterns.
+	AllowedOwners []string `json:"allowed-owners" yaml:"allowed-owners,omitempty" mapstructure:"allowed-owners"`
+	// AllowedRepos is the GitHub repositories ("owner/repo") tools can come from, matched with path.Match patterns.
+	AllowedRepos []string `json:"allowed-repos" yaml:"allowed-repos,omitempty" mapstructure:"allowed-repos"`
+	// MinCooldownRaw is the raw config value for the minimum release cooldown that tools cannot override below.
+	// Use MinCooldown field after PostLoad has been called.
+	MinCooldownRaw any          `json:"min-cooldown" yaml:"min-cooldown,omitempty" mapstructure:"min-cooldown"`
+	MinCooldown    JSONDuration `json:"-" yaml:"-" mapstructure:"-"`
+	// RequireChecksum requires every tool to be installed with require-checksum (tools cannot turn it off).
+	RequireChecksum bool `json:"require-checksum" yaml:"require-checksum,omitempty" mapstructure:"require-checksum"`
+	// RequireSignature requires the downloads of every tool to have a verified signature.
+	RequireSignature bool `json:"require-signature" yaml:"require-signature,omitempty" mapstructure:"require-signature"`
+}
+
+// PostLoad is called by fangs after config loading to load the policy file and parse raw config values.
+func (p *Policy) PostLoad() error {
+	if p.File != "" {
+		if !p.PolicyRules.isEmpty() {
+			return fmt.Errorf("policy rules must be configured either inline or in the policy file %q, not both", p.File)
+		}
+		rules, err := readPolicyFile(p.File)
+		if err != nil {
+			return err
+		}
+		p.PolicyRules = *rules
+	}
+
+	for _, pattern := range slices.Concat(p.AllowedHosts, p.AllowedOwners, p.AllowedRepos) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid policy pattern %q: %w", pattern, err)
+		}
+	}
+
+	if err := p.MinCooldown.ParseFrom(p.MinCooldownRaw); err != nil {
+		return fmt.Errorf("invalid policy min-cooldown value: %w", err)
+	}
+	return nil
+}
+
+func readPolicyFile(path string) (*PolicyRules, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read policy file: %w", err)
+	}
+
+	var rules PolicyRules
+	dec := yaml.NewDecoder(bytes.NewReader(contents))
+	// a misspelled rule would otherwise silently allow everything
+	dec.KnownFields(true)
+	if err := dec.Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unable to parse policy file %q: %w", path, err)
+	}
+	return &rules, nil
+}
+
+func (r PolicyRules) isEmpty() bool {
+	return len(r.AllowedInstallMethods) == 0 && len(r.AllowedHosts) == 0 && len(r.AllowedOwners) == 0 &&
+		len(r.AllowedRepos) == 0 && r.MinCooldownRaw == nil && !r.MinCooldown.IsSet && !r.RequireChecksum && !r.RequireSignature
+}
+
+// PolicyViolation is a tool configuration that breaks a policy rule.
+type PolicyViolation struct {
+	Tool   string
+	Rule   string
+	Reason string
+}
+
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("tool %q violates policy rule %q: %s", v.Tool, v.Rule, v.Reason)
+}
+
+// PolicyError reports every policy violation found for the evaluated tools.
+type PolicyError struct {
+	Violations []PolicyViolation
+}
+
+func (e *PolicyError) Error() string {
+	if len(e.Violations) == 1 {
+		return e.Violations[0].String()
+	}
+	lines := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		lines = append(lines, "  - "+v.String())
+	}
+	return fmt.Sprintf("%d policy violations:\n%s", len(e.Violations), strings.Join(lines, "\n"))
+}
+
+// CheckPolicy evaluates the tools against the policy, returning a PolicyError with every violation found.
+func (o ToolOptions) CheckPolicy(tools ...Tool) error {
+	var violations []PolicyViolation
+	for _, t := range tools {
+		found, err := o.policy.evaluate(t, resolveEffectiveCooldown(false, o.globalCooldown, t.Version.Cooldown))
+		if err != nil {
+			return err
+		}
+		violations = append(violations, found...)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &PolicyError{Violations: violations}
+}
+
+// evaluate returns the rules broken by the tool, given the cooldown that applies to it.
+func (r PolicyRules) evaluate(t Tool, cooldown time.Duration) ([]PolicyViolation, error) {
+	if r.isEmpty() {
+		return nil, nil
+	}
+
+	src, err := toolSourceOf(t)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []PolicyViolation
+	violate := func(rule, format string, args ...any) {
+		violations = append(violations, PolicyViolation{Tool: t.Name, Rule: rule, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	if len(r.AllowedInstallMethods) > 0 && !slices.Contains(r.AllowedInstallMethods, t.InstallMethod) {
+		violate(PolicyRuleAllowedInstallMethods, "install method %q is not allowed", t.InstallMethod)
+	}
+
+	if !src.local {
+		if len(r.AllowedHosts) > 0 && !matchesAny(r.AllowedHosts, src.host) {
+			violate(PolicyRuleAllowedHosts, "host %q is not allowed", src.host)
+		}
+		if len(r.AllowedOwners) > 0 && !matchesAny(r.AllowedOwners, src.owner()) {
+			violate(PolicyRuleAllowedOwners, "%s is not allowed", src.describeRepo("owner"))
+		}
+		if len(r.AllowedRepos) > 0 && !matchesAny(r.AllowedRepos, src.repo) {
+			violate(PolicyRuleAllowedRepos, "%s is not allowed", src.describeRepo("repository"))
+		}
+	}
+
+	if r.MinCooldown.IsSet && cooldown < r.MinCooldown.Duration {
+		violate(PolicyRuleMinCooldown, "cooldown %s is less than the minimum of %s", formatDuration(cooldown), formatDuration(r.MinCooldown.Duration))
+	}
+
+	if r.RequireChecksum && t.RequireChecksum != nil && !*t.RequireChecksum {
+		violate(PolicyRuleRequireChecksum, "require-checksum cannot be turned off")
+	}
+
+	if r.RequireSignature {
+		if reason := missingSignatureVerification(t, src); reason != "" {
+			violate(PolicyRuleRequireSignature, "%s", reason)
+		}
+	}
+
+	return violations, nil
+}
+
+// missingSignatureVerification describes why the tool cannot have its signature verified (empty when it can).
+func missingSignatureVerification(t Tool, src toolSource) string {
+	switch {
+	case githubrelease.IsInstallMethod(t.InstallMethod):
+		if !src.signed {
+			return "no cosign or gpg signature verification is configured (with.verify)"
+		}
+		if src.signatureErr != nil {
+			return fmt.Sprintf("signature verification is not valid (with.verify): %v", src.signatureErr)
+		}
+	case hostedshell.IsInstallMethod(t.InstallMethod):
+		return "hosted-shell installs cannot have their signature verified"
+	case src.module != "" && !gosumdb.ConfigFromEnv().Enabled(src.module):
+		return fmt.Sprintf("go module %q is excluded from the go checksum database (GOSUMDB, GONOSUMDB, or GOPRIVATE)", src.module)
+	}
+	// other go modules are verified against the signed Go checksum database (versions that are not in it, such as
+	// branches, fail when installing)
+	return ""
+}
+
+// toolSource is where a tool is downloaded from.
+type toolSource struct {
+	// local sources (e.g. a go module within the repository) are not downloaded
+	local bool
+	host  string
+	// repo is the GitHub repository ("owner/repo"), when the tool comes from one
+	repo string
+	// signed is true when signature verification is configured
+	signed bool
+	// signatureErr is why the configured signature verification cannot be used (e.g. a keyless identity without a
+	// trusted-root)
+	signatureErr error
+	// module is the go module path, when the tool is built from a remote go module
+	module string
+}
+
+func (s toolSource) owner() string {
+	owner, _, _ := strings.Cut(s.repo, "/")
+	return owner
+}
+
+func (s toolSource) describeRepo(kind string) string {
+	if s.repo == "" {
+		return fmt.Sprintf("a tool that is not from a GitHub repository (host %q)", s.host)
+	}
+	if kind == "owner" {
+		return fmt.Sprintf("owner %q", s.owner())
+	}
+	return fmt.Sprintf("repository %q", s.repo)
+}
+
+func toolSourceOf(t Tool) (toolSource, error) {
+	params, err := deriveInstallParameters(t.Name, t.InstallMethod, t.Parameters, runtime.GOOS)
+	if err != nil {
+		return toolSource{}, fmt.Errorf("failed to derive install parameters for tool %q: %w", t.Name, err)
+	}
+
+	switch p := params.(type) {
+	case githubrelease.InstallerParameters:
+		src := toolSource{
+			host:   "github.com",
+			repo:   p.Repo,
+			signed: p.Verify.IsSet(),
+		}
+		if src.signed {
+			src.signatureErr = p.Verify.Validate()
+		}
+		return src, nil
+	case goinstall.InstallerParameters:
+		return moduleSource(p.Module, ""), nil
+	case gobuild.InstallerParameters:
+		return moduleSource(p.Module, p.RepoURL), nil
+	case hostedshell.InstallerParameters:
+		return urlSource(p.URL)
+	}
+	return toolSource{}, fmt.Errorf("unsupported install method %q for tool %q", t.InstallMethod, t.Name)
+}
+
+func moduleSource(module, repoURL string) toolSource {
+	if gobuild.IsLocalModule(module) {
+		return toolSource{local: true}
+	}
+	if repoURL != "" {
+		if src, err := urlSource(repoURL); err == nil {
+			src.module = module
+			return src
+		}
+	}
+	host, _, _ := strings.Cut(module, "/")
+	return toolSource{host: host, repo: gobuild.DeriveGitHubRepo(module), module: module}
+}
+
+func urlSource(rawURL string) (toolSource, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return toolSource{}, fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	src := toolSource{host: u.Hostname()}
+	if src.host == "github.com" || src.host == "raw.githubusercontent.com" {
+		parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+		if len(parts) >= 2 {
+			src.repo = parts[0] + "/" + strings.TrimSuffix(parts[1], ".git")
+		}
+	}
+	return src, nil
+}
+
+// matchesAny reports whether the value matches any of the path.Match patterns (an empty value never matches).
+func matchesAny(patterns []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func formatDuration(d time.Duration) string {
+	text, _ := JSONDuration{Duration: d}.MarshalText()
+	return string(text)
+}
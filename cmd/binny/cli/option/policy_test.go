@@ -0,0 +1,299 @@
+package option
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/binny/tool/githubrelease"
+	"github.com/anchore/binny/tool/goinstall"
+)
+
+func TestToolOptions_CheckPolicy(t *testing.T) {
+	week := JSONDuration{Duration: 7 * 24 * time.Hour, IsSet: true}
+	day := JSONDuration{Duration: 24 * time.Hour, IsSet: true}
+	disabled := false
+
+	release := Tool{Name: "syft", InstallMethod: "github-release", Parameters: map[string]any{"repo": "anchore/syft"}}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	signedRelease := Tool{Name: "syft", InstallMethod: "github-release", Parameters: map[string]any{
+		"repo":   "anchore/syft",
+		"verify": map[string]any{"cosign": map[string]any{"key": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))}},
+	}}
+	module := Tool{Name: "tools", InstallMethod: "go-install", Parameters: map[string]any{"module": "golang.org/x/tools/cmd/stringer"}}
+	localModule := Tool{Name: "binny", InstallMethod: "go-install", Parameters: map[string]any{"module": "./cmd/binny"}}
+	script := Tool{Name: "tool", InstallMethod: "hosted-shell", Parameters: map[string]any{"url": "https://raw.githubusercontent.com/owner/tool/main/install.sh"}}
+
+	tests := []struct {
+		name    string
+		rules   PolicyRules
+		global  JSONDuration
+		env     map[string]string
+		tool    Tool
+		want    []PolicyViolation
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name: "empty policy allows everything",
+			tool: script,
+		},
+		{
+			name:  "install method not allowed",
+			rules: PolicyRules{AllowedInstallMethods: []string{"github-release", "go-install"}},
+			tool:  script,
+			want:  []PolicyViolation{{Tool: "tool", Rule: PolicyRuleAllowedInstallMethods, Reason: `install method "hosted-shell" is not allowed`}},
+		},
+		{
+			name:  "host allowed by pattern",
+			rules: PolicyRules{AllowedHosts: []string{"*.githubusercontent.com"}},
+			tool:  script,
+		},
+		{
+			name:  "module host not allowed",
+			rules: PolicyRules{AllowedHosts: []string{"github.com"}},
+			tool:  module,
+			want:  []PolicyViolation{{Tool: "tools", Rule: PolicyRuleAllowedHosts, Reason: `host "golang.org" is not allowed`}},
+		},
+		{
+			name:  "local modules are not downloaded",
+			rules: PolicyRules{AllowedHosts: []string{"github.com"}, AllowedOwners: []string{"anchore"}},
+			tool:  localModule,
+		},
+		{
+			name:  "owner and repo allowed",
+			rules: PolicyRules{AllowedOwners: []string{"anchore"}, AllowedRepos: []string{"anchore/*"}},
+			tool:  release,
+		},
+		{
+			name:  "owner and repo not allowed",
+			rules: PolicyRules{AllowedOwners: []string{"anchore"}, AllowedRepos: []string{"anchore/*"}},
+			tool:  script,
+			want: []PolicyViolation{
+				{Tool: "tool", Rule: PolicyRuleAllowedOwners, Reason: `owner "owner" is not allowed`},
+				{Tool: "tool", Rule: PolicyRuleAllowedRepos, Reason: `repository "owner/tool" is not allowed`},
+			},
+		},
+		{
+			name:  "owner of a tool not from a github repository",
+			rules: PolicyRules{AllowedOwners: []string{"golang"}},
+			tool:  module,
+			want:  []PolicyViolation{{Tool: "tools", Rule: PolicyRuleAllowedOwners, Reason: `a tool that is not from a GitHub repository (host "golang.org") is not allowed`}},
+		},
+		{
+			name:   "global cooldown below the minimum",
+			rules:  PolicyRules{MinCooldown: week},
+			global: day,
+			tool:   release,
+			want:   []PolicyViolation{{Tool: "syft", Rule: PolicyRuleMinCooldown, Reason: "cooldown 1d is less than the minimum of 7d"}},
+		},
+		{
+			name:   "tool cooldown below the minimum",
+			rules:  PolicyRules{MinCooldown: week},
+			global: week,
+			tool:   Tool{Name: "syft", InstallMethod: "github-release", Version: ToolVersionConfig{Cooldown: day}, Parameters: release.Parameters},
+			want:   []PolicyViolation{{Tool: "syft", Rule: PolicyRuleMinCooldown, Reason: "cooldown 1d is less than the minimum of 7d"}},
+		},
+		{
+			name:   "cooldown meets the minimum",
+			rules:  PolicyRules{MinCooldown: week},
+			global: week,
+			tool:   release,
+		},
+		{
+			name:  "require-checksum turned off",
+			rules: PolicyRules{RequireChecksum: true},
+			tool:  Tool{Name: "syft", InstallMethod: "github-release", RequireChecksum: &disabled, Parameters: release.Parameters},
+			want:  []PolicyViolation{{Tool: "syft", Rule: PolicyRuleRequireChecksum, Reason: "require-checksum cannot be turned off"}},
+		},
+		{
+			name:  "signature verification not configured",
+			rules: PolicyRules{RequireSignature: true},
+			tool:  release,
+			want:  []PolicyViolation{{Tool: "syft", Rule: PolicyRuleRequireSignature, Reason: "no cosign or gpg signature verification is configured (with.verify)"}},
+		},
+		{
+			name:  "incomplete keyless signature verification",
+			rules: PolicyRules{RequireSignature: true},
+			tool: Tool{Name: "syft", InstallMethod: "github-release", Parameters: map[string]any{
+				"repo":   "anchore/syft",
+				"verify": map[string]any{"cosign": map[string]any{"identity": "https://github.com/anchore/syft/.github/workflows/release.yaml@refs/heads/main", "issuer": "https://token.actions.githubusercontent.com"}},
+			}},
+			want: []PolicyViolation{{Tool: "syft", Rule: PolicyRuleRequireSignature, Reason: "signature verification is not valid (with.verify): cosign identity, issuer, and tlog-key require a trusted-root to verify certificates with"}},
+		},
+		{
+			name:  "signature verification configured",
+			rules: PolicyRules{RequireSignature: true},
+			tool:  signedRelease,
+		},
+		{
+			name:  "go modules are verified by the checksum database",
+			rules: PolicyRules{RequireSignature: true},
+			tool:  module,
+		},
+		{
+			name:  "go module excluded from the checksum database",
+			rules: PolicyRules{RequireSignature: true},
+			env:   map[string]string{"GONOSUMDB": "golang.org/x"},
+			tool:  module,
+			want:  []PolicyViolation{{Tool: "tools", Rule: PolicyRuleRequireSignature, Reason: `go module "golang.org/x/tools/cmd/stringer" is excluded from the go checksum database (GOSUMDB, GONOSUMDB, or GOPRIVATE)`}},
+		},
+		{
+			name:  "checksum database turned off",
+			rules: PolicyRules{RequireSignature: true},
+			env:   map[string]string{"GOSUMDB": "off"},
+			tool:  module,
+			want:  []PolicyViolation{{Tool: "tools", Rule: PolicyRuleRequireSignature, Reason: `go module "golang.org/x/tools/cmd/stringer" is excluded from the go checksum database (GOSUMDB, GONOSUMDB, or GOPRIVATE)`}},
+		},
+		{
+			name:  "hosted-shell cannot be signed",
+			rules: PolicyRules{RequireSignature: true},
+			tool:  script,
+			want:  []PolicyViolation{{Tool: "tool", Rule: PolicyRuleRequireSignature, Reason: "hosted-shell installs cannot have their signature verified"}},
+		},
+		{
+			name:    "invalid install parameters",
+			rules:   PolicyRules{RequireSignature: true},
+			tool:    Tool{Name: "syft", InstallMethod: "github-release", Parameters: map[string]any{"repo": []string{"bogus"}}},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			for _, key := range []string{"GOSUMDB", "GONOSUMDB", "GOPRIVATE"} {
+				t.Setenv(key, tt.env[key])
+			}
+			opts := DefaultToolOptions().WithGlobalCooldown(tt.global).WithPolicy(Policy{PolicyRules: tt.rules})
+
+			err := opts.CheckPolicy(tt.tool)
+
+			var policyErr *PolicyError
+			if errors.As(err, &policyErr) {
+				assert.Equal(t, tt.want, policyErr.Violations)
+				return
+			}
+			tt.wantErr(t, err)
+			assert.Empty(t, tt.want)
+		})
+	}
+}
+
+func TestToolOptions_CheckPolicy_reportsEveryViolation(t *testing.T) {
+	opts := DefaultToolOptions().WithPolicy(Policy{PolicyRules: PolicyRules{
+		AllowedInstallMethods: []string{"github-release"},
+		AllowedHosts:          []string{"github.com"},
+	}})
+
+	err := opts.CheckPolicy(
+		Tool{Name: "a", InstallMethod: "hosted-shell", Parameters: map[string]any{"url": "https://example.com/install.sh"}},
+		Tool{Name: "b", InstallMethod: "github-release", Parameters: map[string]any{"repo": "owner/b"}},
+		Tool{Name: "c", InstallMethod: "go-install", Parameters: map[string]any{"module": "github.com/owner/c"}},
+	)
+	require.Error(t, err)
+	assert.Equal(t, `3 policy violations:
+  - tool "a" violates policy rule "allowed-install-methods": install method "hosted-shell" is not allowed
+  - tool "a" violates policy rule "allowed-hosts": host "example.com" is not allowed
+  - tool "c" violates policy rule "allowed-install-methods": install method "go-install" is not allowed`, err.Error())
+}
+
+func TestTool_ToConfig_policy(t *testing.T) {
+	opts := DefaultToolOptions().
+		WithIgnoreCooldown(true).
+		WithPolicy(Policy{PolicyRules: PolicyRules{
+			MinCooldown:      JSONDuration{Duration: 7 * 24 * time.Hour, IsSet: true},
+			RequireSignature: true,
+		}})
+
+	cfg, intent, err := Tool{Name: "syft", InstallMethod: "github-release", Parameters: map[string]any{"repo": "anchore/syft"}}.ToConfig(opts)
+	require.NoError(t, err)
+
+	// ignoring the cooldown cannot go below the policy minimum
+	assert.Equal(t, 7*24*time.Hour, intent.Cooldown)
+	params, ok := cfg.InstallerConfig.Parameters.(githubrelease.InstallerParameters)
+	require.True(t, ok)
+	assert.True(t, params.Verify.Required)
+
+	cfg, _, err = Tool{Name: "binny", InstallMethod: "go-install", Parameters: map[string]any{"module": "github.com/anchore/binny"}}.ToConfig(opts)
+	require.NoError(t, err)
+	moduleParams, ok := cfg.InstallerConfig.Parameters.(goinstall.InstallerParameters)
+	require.True(t, ok)
+	assert.True(t, moduleParams.RequireChecksumDatabase)
+}
+
+func TestPolicy_PostLoad(t *testing.T) {
+	dir := t.TempDir()
+	policyFile := filepath.Join(dir, "policy.yaml")
+	require.NoError(t, os.WriteFile(policyFile, []byte("allowed-install-methods: [github-release]\nmin-cooldown: 3d\n"), 0o600))
+	typoFile := filepath.Join(dir, "typo.yaml")
+	require.NoError(t, os.WriteFile(typoFile, []byte("allowed-install-method: [github-release]\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		policy  Policy
+		want    PolicyRules
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:   "inline rules",
+			policy: Policy{PolicyRules: PolicyRules{MinCooldownRaw: "1d"}},
+			want:   PolicyRules{MinCooldownRaw: "1d", MinCooldown: JSONDuration{Duration: 24 * time.Hour, IsSet: true}},
+		},
+		{
+			name:   "policy file",
+			policy: Policy{File: policyFile},
+			want: PolicyRules{
+				AllowedInstallMethods: []string{"github-release"},
+				MinCooldownRaw:        "3d",
+				MinCooldown:           JSONDuration{Duration: 3 * 24 * time.Hour, IsSet: true},
+			},
+		},
+		{
+			name:    "inline rules and a policy file",
+			policy:  Policy{File: policyFile, PolicyRules: PolicyRules{RequireChecksum: true}},
+			wantErr: require.Error,
+		},
+		{
+			name:    "unknown rule in the policy file",
+			policy:  Policy{File: typoFile},
+			wantErr: require.Error,
+		},
+		{
+			name:    "missing policy file",
+			policy:  Policy{File: filepath.Join(dir, "missing.yaml")},
+			wantErr: require.Error,
+		},
+		{
+			name:    "invalid pattern",
+			policy:  Policy{PolicyRules: PolicyRules{AllowedRepos: []string{"anchore/["}}},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			err := tt.policy.PostLoad()
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, tt.policy.PolicyRules)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package option
+
+import "github.com/anchore/binny"
+
+// resolveEffectivePrerelease returns the prerelease policy that should apply for a tool.
+// Per-tool policy overrides the global policy. An unset value means "inherit".
+func resolveEffectivePrerelease(global, perTool binny.PrereleasePolicy) binny.PrereleasePolicy {
+	if perTool != "" {
+		return perTool
+	}
+	if global != "" {
+		return global
+	}
+	return binny.PrereleaseExclude
+}
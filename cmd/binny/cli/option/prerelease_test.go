@@ -0,0 +1,47 @@
+package option
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/binny"
+)
+
+func Test_resolveEffectivePrerelease(t *testing.T) {
+	tests := []struct {
+		name    string
+		global  binny.PrereleasePolicy
+		perTool binny.PrereleasePolicy
+		want    binny.PrereleasePolicy
+	}{
+		{
+			name: "both unset excludes prereleases",
+			want: binny.PrereleaseExclude,
+		},
+		{
+			name:   "global set, per-tool unset inherits global",
+			global: binny.PrereleaseInclude,
+			want:   binny.PrereleaseInclude,
+		},
+		{
+			name:    "per-tool set overrides global",
+			global:  binny.PrereleaseInclude,
+			perTool: binny.PrereleaseOnly,
+			want:    binny.PrereleaseOnly,
+		},
+		{
+			name:    "per-tool explicitly excluding overrides global",
+			global:  binny.PrereleaseInclude,
+			perTool: binny.PrereleaseExclude,
+			want:    binny.PrereleaseExclude,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveEffectivePrerelease(tt.global, tt.perTool)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package option
+
+import (
+	"fmt"
+
+	internalhttp "github.com/anchore/binny/internal/http"
+	"github.com/anchore/clio"
+)
+
+// defaultRateLimitWait is how long to wait for an exhausted rate limit to reset. Secondary GitHub rate limits usually
+// ask for a minute or so, while the primary limits reset hourly (which is better to fail on than to sit through).
+const defaultRateLimitWait = "5m"
+
+// RateLimit configures what happens when a host (e.g. the GitHub API) reports that its rate limit is exhausted.
+type RateLimit struct {
+	// WaitRaw is the raw config value for how long to wait for an exhausted rate limit to reset before failing.
+	// Use Wait field after PostLoad has been called.
+	WaitRaw string       `json:"rate-limit-wait" yaml:"rate-limit-wait" mapstructure:"rate-limit-wait"`
+	Wait    JSONDuration `json:"-" yaml:"-" mapstructure:"-"`
+}
+
+func DefaultRateLimit() RateLimit {
+	return RateLimit{
+		WaitRaw: defaultRateLimitWait,
+	}
+}
+
+func (o *RateLimit) AddFlags(flags clio.FlagSet) {
+	flags.StringVarP(&o.WaitRaw, "rate-limit-wait", "", "How long to wait for an exhausted rate limit to reset before failing (0 fails right away)")
+}
+
+// PostLoad is called by fangs after config loading to parse raw config values.
+func (o *RateLimit) PostLoad() error {
+	if err := o.Wait.ParseFrom(o.WaitRaw); err != nil {
+		return fmt.Errorf("invalid rate-limit-wait value: %w", err)
+	}
+	return nil
+}
+
+// NewRateLimits creates the tracker that waits for (or fails on) exhausted rate limits.
+func (o RateLimit) NewRateLimits() *internalhttp.RateLimits {
+	return internalhttp.NewRateLimits(o.Wait.Duration)
+}
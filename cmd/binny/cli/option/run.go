@@ -0,0 +1,51 @@
+package option
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anchore/binny/internal/sandbox"
+)
+
+// ToolRun configures how "binny run" executes a tool.
+type ToolRun struct {
+	// Sandbox restricts the tool to writing within the working tree and temp directories (linux only).
+	Sandbox bool `json:"sandbox" yaml:"sandbox,omitempty" mapstructure:"sandbox"`
+	// DenyNetwork runs the sandboxed tool without network access (implies sandbox). Kernels that cannot isolate the
+	// network only deny TCP connections.
+	DenyNetwork bool `json:"deny-network" yaml:"deny-network,omitempty" mapstructure:"deny-network"`
+	// Writable are additional paths the sandboxed tool can write to (e.g. "~/.cache/golangci-lint"). Environment
+	// variables are expanded.
+	Writable []string `json:"writable" yaml:"writable,omitempty" mapstructure:"writable"`
+}
+
+// Sandboxed reports whether the tool is run within a sandbox.
+func (r ToolRun) Sandboxed() bool {
+	return r.Sandbox || r.DenyNetwork
+}
+
+// SandboxConfig returns the restrictions for running the tool within a sandbox.
+func (r ToolRun) SandboxConfig() (sandbox.Config, error) {
+	writable, err := sandbox.DefaultWritable()
+	if err != nil {
+		return sandbox.Config{}, err
+	}
+	for _, path := range r.Writable {
+		writable = append(writable, expandPath(path))
+	}
+	return sandbox.Config{
+		Writable:    writable,
+		DenyNetwork: r.DenyNetwork,
+	}, nil
+}
+
+func expandPath(path string) string {
+	path = os.ExpandEnv(path)
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/anchore/binny/tool/goinstall"
 	"github.com/anchore/binny/tool/goproxy"
 	"github.com/anchore/binny/tool/hostedshell"
+	"github.com/anchore/binny/tool/httpapi"
 )
 
 type Tool struct {
@@ -22,16 +23,107 @@ type Tool struct {
 
 	InstallMethod string         `json:"method" yaml:"method,omitempty" mapstructure:"method"`
 	Parameters    map[string]any `json:"with" yaml:"with,omitempty" mapstructure:"with"`
+
+	// RequireChecksum overrides the global require-checksum setting for this tool (when set).
+	RequireChecksum *bool `json:"require-checksum" yaml:"require-checksum,omitempty" mapstructure:"require-checksum"`
+
+	Run ToolRun `json:"run" yaml:"run,omitempty" mapstructure:"run"`
+}
+
+// RequiresChecksum reports whether installing this tool must fail when there is no checksum to verify downloads with,
+// given the global setting.
+func (t Tool) RequiresChecksum(global bool) bool {
+	if t.RequireChecksum != nil {
+		return *t.RequireChecksum
+	}
+	return global
+}
+
+// PinnedChecksums returns the per-platform checksums pinned in the install parameters ("with.checksums").
+func (t Tool) PinnedChecksums() (binny.PinnedChecksums, error) {
+	var params struct {
+		Checksums binny.PinnedChecksums `mapstructure:"checksums"`
+	}
+	if err := mapstructure.Decode(t.Parameters, &params); err != nil {
+		return nil, fmt.Errorf("invalid checksums for tool %q: %w", t.Name, err)
+	}
+	if err := params.Checksums.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid checksums for tool %q: %w", t.Name, err)
+	}
+	return params.Checksums, nil
+}
+
+// WithPinnedChecksums returns the tool with the given checksums merged into the pinned checksums.
+func (t Tool) WithPinnedChecksums(checksums binny.PinnedChecksums) Tool {
+	if len(checksums) == 0 {
+		return t
+	}
+
+	pinned, _ := t.PinnedChecksums()
+	merged := make(map[string]any, len(pinned)+len(checksums))
+	for platform, checksum := range pinned {
+		merged[platform] = checksum
+	}
+	for platform, checksum := range checksums {
+		merged[platform] = checksum
+	}
+
+	return t.withParameter("checksums", merged)
+}
+
+// ScriptChecksum returns the digest that the install script is pinned to ("with.script-checksum"), if any.
+func (t Tool) ScriptChecksum() string {
+	checksum, _ := t.Parameters["script-checksum"].(string)
+	return checksum
+}
+
+// WithScriptChecksum returns the tool with the install script pinned to the given digest.
+func (t Tool) WithScriptChecksum(checksum string) Tool {
+	return t.withParameter("script-checksum", checksum)
+}
+
+// withParameter returns the tool with the given install parameter set, leaving the original parameters as-is.
+func (t Tool) withParameter(key string, value any) Tool {
+	params := make(map[string]any, len(t.Parameters)+1)
+	for k, v := range t.Parameters {
+		params[k] = v
+	}
+	params[key] = value
+	t.Parameters = params
+	return t
 }
 
 type ToolVersionConfig struct {
-	Want       string `json:"want" yaml:"want" mapstructure:"want"`
+	Want string `json:"want" yaml:"want" mapstructure:"want"`
+	// Ref is the branch or commit that the want was pinned from. This is set by "update" when the want is a branch
+	// or commit, after which the want is moved forward to what the reference resolves to on every update.
+	Ref        string `json:"ref" yaml:"ref,omitempty" mapstructure:"ref"`
 	Constraint string `json:"constraint" yaml:"constraint,omitempty" mapstructure:"constraint"`
 	// CooldownRaw is the raw config value for the per-tool cooldown duration.
 	// Use Cooldown field after PostLoad has been called.
-	CooldownRaw   any          `json:"cooldown" yaml:"cooldown,omitempty" mapstructure:"cooldown"`
-	Cooldown      JSONDuration `json:"-" yaml:"-" mapstructure:"-"`
-	ResolveMethod string       `json:"method" yaml:"method,omitempty" mapstructure:"method"`
+	CooldownRaw any          `json:"cooldown" yaml:"cooldown,omitempty" mapstructure:"cooldown"`
+	Cooldown    JSONDuration `json:"-" yaml:"-" mapstructure:"-"`
+	// PrereleaseRaw is the raw config value for the per-tool prerelease policy (exclude, include, or only).
+	// Use Prerelease field after PostLoad has been called.
+	PrereleaseRaw string                 `json:"prerelease" yaml:"prerelease,omitempty" mapstructure:"prerelease"`
+	Prerelease    binny.PrereleasePolicy `json:"-" yaml:"-" mapstructure:"-"`
+	// SchemeRaw is the raw config value for how versions are ordered (semver, calver, numeric, or lexical).
+	// Use Scheme field after PostLoad has been called.
+	SchemeRaw string              `json:"scheme" yaml:"scheme,omitempty" mapstructure:"scheme"`
+	Scheme    binny.VersionScheme `json:"-" yaml:"-" mapstructure:"-"`
+	// Skip is a deny-list of versions (e.g. "v1.2.3") or constraints (e.g. ">= v1.2.0, < v1.2.3") that must never be
+	// resolved.
+	Skip []string `json:"skip" yaml:"skip,omitempty" mapstructure:"skip"`
+	// TagPattern is a regular expression with a capture group that extracts the version from a tag (e.g.
+	// "^cli/(v.*)$"). Tags that do not match are not considered.
+	TagPattern string `json:"tag-pattern" yaml:"tag-pattern,omitempty" mapstructure:"tag-pattern"`
+	// TagTemplate renders the tag for a version (e.g. "cli/{{ .Version }}"), which is what installers are given.
+	TagTemplate string `json:"tag-template" yaml:"tag-template,omitempty" mapstructure:"tag-template"`
+	// LatestStrategyRaw is the raw config value for how the latest version is chosen (github-latest, highest-semver,
+	// or newest-date). Use LatestStrategy field after PostLoad has been called.
+	LatestStrategyRaw string               `json:"latest-strategy" yaml:"latest-strategy,omitempty" mapstructure:"latest-strategy"`
+	LatestStrategy    binny.LatestStrategy `json:"-" yaml:"-" mapstructure:"-"`
+	ResolveMethod     string               `json:"method" yaml:"method,omitempty" mapstructure:"method"`
 
 	Parameters map[string]any `json:"with" yaml:"with,omitempty" mapstructure:"with"`
 }
@@ -41,13 +133,56 @@ func (t *ToolVersionConfig) PostLoad() error {
 	if err := t.Cooldown.ParseFrom(t.CooldownRaw); err != nil {
 		return fmt.Errorf("invalid cooldown value: %w", err)
 	}
+	prerelease, err := binny.ParsePrereleasePolicy(t.PrereleaseRaw)
+	if err != nil {
+		return err
+	}
+	t.Prerelease = prerelease
+	scheme, err := binny.ParseVersionScheme(t.SchemeRaw)
+	if err != nil {
+		return err
+	}
+	t.Scheme = scheme
+	latestStrategy, err := binny.ParseLatestStrategy(t.LatestStrategyRaw)
+	if err != nil {
+		return err
+	}
+	t.LatestStrategy = latestStrategy
+	if _, err := binny.NewSkipList(t.Skip, t.Scheme); err != nil {
+		return err
+	}
+	if _, err := t.tagMapping(); err != nil {
+		return err
+	}
 	return nil
 }
 
+func (t ToolVersionConfig) tagMapping() (binny.TagMapping, error) {
+	return binny.NewTagMapping(t.TagPattern, t.TagTemplate)
+}
+
+// WithUpdatedWant returns the version config with the want moved to the given version. When the want was a branch or
+// commit (rather than a version), it is kept as the reference that the version was pinned from.
+func (t ToolVersionConfig) WithUpdatedWant(version string) ToolVersionConfig {
+	if t.Ref == "" && t.Want != "latest" {
+		tags, err := t.tagMapping()
+		if err == nil {
+			filter := binny.VersionFilter{Scheme: t.Scheme}
+			if !filter.IsVersion(tags.Normalize(t.Want)) && filter.IsVersion(version) {
+				t.Ref = t.Want
+			}
+		}
+	}
+	t.Want = version
+	return t
+}
+
 // ToolOptions holds configuration for tool construction behavior.
 type ToolOptions struct {
-	globalCooldown JSONDuration
-	ignoreCooldown bool
+	globalCooldown   JSONDuration
+	ignoreCooldown   bool
+	globalPrerelease binny.PrereleasePolicy
+	policy           PolicyRules
 }
 
 // DefaultToolOptions returns a ToolOptions with default values.
@@ -67,7 +202,24 @@ func (o ToolOptions) WithIgnoreCooldown(ignore bool) ToolOptions {
 	return o
 }
 
+// WithGlobalPrerelease sets the global prerelease policy that applies to all tools (unless overridden per-tool).
+func (o ToolOptions) WithGlobalPrerelease(p binny.PrereleasePolicy) ToolOptions {
+	o.globalPrerelease = p
+	return o
+}
+
+// WithPolicy sets the policy that tools are evaluated against (see CheckPolicy).
+func (o ToolOptions) WithPolicy(p Policy) ToolOptions {
+	o.policy = p.PolicyRules
+	return o
+}
+
+// ToTool inflates the tool from its configuration, failing when the tool violates the policy.
 func (t Tool) ToTool(opts ToolOptions) (binny.Tool, *binny.VersionIntent, error) {
+	if err := opts.CheckPolicy(t); err != nil {
+		return nil, nil, err
+	}
+
 	cfg, intent, err := t.ToConfig(opts)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to read tool %q config: %w", t.Name, err)
@@ -87,6 +239,20 @@ func (t Tool) ToConfig(opts ToolOptions) (*tool.Config, *binny.VersionIntent, er
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to derive install parameters for tool %q: %w", t.Name, err)
 	}
+	if o.policy.RequireSignature {
+		switch params := installParams.(type) {
+		case githubrelease.InstallerParameters:
+			params.Verify.Required = true
+			installParams = params
+		case goinstall.InstallerParameters:
+			// go modules are signed by way of the go checksum database
+			params.RequireChecksumDatabase = true
+			installParams = params
+		case gobuild.InstallerParameters:
+			params.RequireChecksumDatabase = true
+			installParams = params
+		}
+	}
 
 	versionResolveMethod, versionResolveParams, err := deriveVersionResolveParameters(t.Version.ResolveMethod, t.Version.Parameters)
 	if err != nil {
@@ -105,10 +271,22 @@ func (t Tool) ToConfig(opts ToolOptions) (*tool.Config, *binny.VersionIntent, er
 		},
 	}
 
+	tags, err := t.Version.tagMapping()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to derive tag mapping for tool %q: %w", t.Name, err)
+	}
+
 	intent := &binny.VersionIntent{
-		Want:       t.Version.Want,
-		Constraint: t.Version.Constraint,
-		Cooldown:   resolveEffectiveCooldown(o.ignoreCooldown, o.globalCooldown, t.Version.Cooldown),
+		// a want that is expressed as a tag is normalized to a version, since all comparisons are made on versions
+		Want:           tags.Normalize(t.Version.Want),
+		Ref:            t.Version.Ref,
+		Constraint:     t.Version.Constraint,
+		Cooldown:       max(resolveEffectiveCooldown(o.ignoreCooldown, o.globalCooldown, t.Version.Cooldown), o.policy.MinCooldown.Duration),
+		Prerelease:     resolveEffectivePrerelease(o.globalPrerelease, t.Version.Prerelease),
+		Tags:           tags,
+		Scheme:         t.Version.Scheme,
+		Skip:           t.Version.Skip,
+		LatestStrategy: t.Version.LatestStrategy,
 	}
 
 	return cfg, intent, nil
@@ -171,6 +349,12 @@ func deriveVersionResolveParameters(resolveMethod string, versionParameters map[
 			return resolveMethod, nil, err
 		}
 		return resolveMethod, params, nil
+	case httpapi.IsResolveMethod(resolveMethod):
+		var params httpapi.VersionResolutionParameters
+		if err := mapstructure.Decode(versionParameters, &params); err != nil {
+			return resolveMethod, nil, err
+		}
+		return resolveMethod, params, nil
 	case resolveMethod == "":
 		return resolveMethod, nil, nil
 	}
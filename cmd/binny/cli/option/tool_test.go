@@ -3,8 +3,10 @@ package option
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/anchore/binny"
 	"github.com/anchore/binny/tool/githubrelease"
 )
 
@@ -69,3 +71,139 @@ func TestDeriveInstallParameters_GithubRelease(t *testing.T) {
 		})
 	}
 }
+
+func TestToolVersionConfig_WithUpdatedWant(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   ToolVersionConfig
+		version  string
+		wantWant string
+		wantRef  string
+	}{
+		{
+			name:     "version is replaced",
+			config:   ToolVersionConfig{Want: "v1.0.0"},
+			version:  "v1.1.0",
+			wantWant: "v1.1.0",
+		},
+		{
+			name:     "branch is kept as the reference",
+			config:   ToolVersionConfig{Want: "main"},
+			version:  "v0.0.0-20240601120000-abcdef123456",
+			wantWant: "v0.0.0-20240601120000-abcdef123456",
+			wantRef:  "main",
+		},
+		{
+			name:     "existing reference is kept",
+			config:   ToolVersionConfig{Want: "v0.0.0-20240601120000-abcdef123456", Ref: "main"},
+			version:  "v0.0.0-20240701120000-123456abcdef",
+			wantWant: "v0.0.0-20240701120000-123456abcdef",
+			wantRef:  "main",
+		},
+		{
+			name:     "tag is not a reference",
+			config:   ToolVersionConfig{Want: "cli/v1.0.0", TagPattern: "^cli/(v.*)$", TagTemplate: "cli/{{ .Version }}"},
+			version:  "v1.1.0",
+			wantWant: "v1.1.0",
+		},
+		{
+			name:     "reference resolving to something other than a version",
+			config:   ToolVersionConfig{Want: "main"},
+			version:  "abcdef123456",
+			wantWant: "abcdef123456",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.config.WithUpdatedWant(tt.version)
+			require.Equal(t, tt.wantWant, got.Want)
+			require.Equal(t, tt.wantRef, got.Ref)
+		})
+	}
+}
+
+func TestToolVersionConfig_PostLoad_tagMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ToolVersionConfig
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:   "pattern and template",
+			config: ToolVersionConfig{Want: "v1.0.0", TagPattern: "^cli/(v.*)$", TagTemplate: "cli/{{ .Version }}"},
+		},
+		{
+			// installing would otherwise be given the version "v1.0.0" instead of the tag "cli/v1.0.0"
+			name:    "pattern without template",
+			config:  ToolVersionConfig{Want: "v1.0.0", TagPattern: "^cli/(v.*)$"},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			tt.wantErr(t, tt.config.PostLoad())
+		})
+	}
+}
+
+func TestTool_RequiresChecksum(t *testing.T) {
+	enabled, disabled := true, false
+	tests := []struct {
+		name   string
+		tool   Tool
+		global bool
+		want   bool
+	}{
+		{name: "global setting applies by default", global: true, want: true},
+		{name: "not required by default", want: false},
+		{name: "tool can require checksums", tool: Tool{RequireChecksum: &enabled}, want: true},
+		{name: "tool can opt out", tool: Tool{RequireChecksum: &disabled}, global: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.tool.RequiresChecksum(tt.global))
+		})
+	}
+}
+
+func TestTool_WithPinnedChecksums(t *testing.T) {
+	original := Tool{
+		Name: "syft",
+		Parameters: map[string]any{
+			"repo": "anchore/syft",
+			"checksums": map[string]any{
+				"linux/amd64":  "sha256:stale",
+				"darwin/arm64": "sha256:unchanged",
+			},
+		},
+	}
+
+	updated := original.WithPinnedChecksums(binny.PinnedChecksums{"linux/amd64": "sha256:fresh"})
+
+	got, err := updated.PinnedChecksums()
+	require.NoError(t, err)
+	assert.Equal(t, binny.PinnedChecksums{"linux/amd64": "sha256:fresh", "darwin/arm64": "sha256:unchanged"}, got)
+	assert.Equal(t, "anchore/syft", updated.Parameters["repo"])
+
+	// the original config is left as-is
+	got, err = original.PinnedChecksums()
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:stale", got["linux/amd64"])
+
+	_, err = Tool{Parameters: map[string]any{"checksums": map[string]any{"linux": "abc"}}}.PinnedChecksums()
+	require.Error(t, err)
+}
+
+func TestTool_WithScriptChecksum(t *testing.T) {
+	original := Tool{Name: "syft", Parameters: map[string]any{"url": "https://example.com/install.sh", "script-checksum": "sha256:stale"}}
+
+	updated := original.WithScriptChecksum("sha256:fresh")
+
+	assert.Equal(t, "sha256:fresh", updated.ScriptChecksum())
+	assert.Equal(t, "https://example.com/install.sh", updated.Parameters["url"])
+	assert.Equal(t, "sha256:stale", original.ScriptChecksum())
+	assert.Empty(t, Tool{}.ScriptChecksum())
+}
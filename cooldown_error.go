@@ -10,19 +10,9 @@ type CooldownError struct {
 	Cooldown      time.Duration
 	LatestVersion string
 	LatestDate    *time.Time
-
-	// CheckedCount and TotalCount are set when the resolver hit a candidate limit
-	// before checking all available versions.
-	CheckedCount int
-	TotalCount   int
 }
 
 func (e *CooldownError) Error() string {
-	suffix := ""
-	if e.CheckedCount > 0 && e.CheckedCount < e.TotalCount {
-		suffix = fmt.Sprintf(" (only checked %d of %d candidates)", e.CheckedCount, e.TotalCount)
-	}
-
 	if e.LatestVersion != "" && e.LatestDate != nil {
 		age := time.Since(*e.LatestDate).Truncate(time.Minute)
 		remaining := e.Cooldown - age
@@ -31,19 +21,19 @@ func (e *CooldownError) Error() string {
 		// no version matched the constraints that also passed cooldown
 		if remaining <= 0 {
 			return fmt.Sprintf(
-				"version %q was published %s ago (cooldown period has passed); no matching version found (use --ignore-cooldown to bypass)%s",
-				e.LatestVersion, formatDuration(age), suffix,
+				"version %q was published %s ago (cooldown period has passed); no matching version found (use --ignore-cooldown to bypass)",
+				e.LatestVersion, formatDuration(age),
 			)
 		}
 
 		return fmt.Sprintf(
-			"version %q was published %s ago, but the release cooldown requires %s (try again in %s, or use --ignore-cooldown to bypass)%s",
-			e.LatestVersion, formatDuration(age), formatDuration(e.Cooldown), formatDuration(remaining), suffix,
+			"version %q was published %s ago, but the release cooldown requires %s (try again in %s, or use --ignore-cooldown to bypass)",
+			e.LatestVersion, formatDuration(age), formatDuration(e.Cooldown), formatDuration(remaining),
 		)
 	}
 	return fmt.Sprintf(
-		"no version found that satisfies the release cooldown of %s (use --ignore-cooldown to bypass)%s",
-		formatDuration(e.Cooldown), suffix,
+		"no version found that satisfies the release cooldown of %s (use --ignore-cooldown to bypass)",
+		formatDuration(e.Cooldown),
 	)
 }
 
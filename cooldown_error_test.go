@@ -114,50 +114,6 @@ func TestCooldownError_Error(t *testing.T) {
 				"try again in",
 			},
 		},
-		{
-			name: "candidate limit info shown when limit was hit with version details",
-			err: CooldownError{
-				Cooldown:      7 * 24 * time.Hour,
-				LatestVersion: "v3.0.0",
-				LatestDate:    timePtr(now.Add(-1 * 24 * time.Hour)),
-				CheckedCount:  10,
-				TotalCount:    25,
-			},
-			wantContains: []string{
-				`"v3.0.0"`,
-				"only checked 10 of 25 candidates",
-				"--ignore-cooldown",
-			},
-		},
-		{
-			name: "candidate limit info shown when limit was hit without version details",
-			err: CooldownError{
-				Cooldown:     7 * 24 * time.Hour,
-				CheckedCount: 10,
-				TotalCount:   25,
-			},
-			wantContains: []string{
-				"no version found",
-				"only checked 10 of 25 candidates",
-			},
-		},
-		{
-			name: "no candidate limit suffix when all candidates were checked",
-			err: CooldownError{
-				Cooldown:      7 * 24 * time.Hour,
-				LatestVersion: "v2.0.0",
-				LatestDate:    timePtr(now.Add(-1 * 24 * time.Hour)),
-				CheckedCount:  5,
-				TotalCount:    5,
-			},
-			wantContains: []string{
-				`"v2.0.0"`,
-				"--ignore-cooldown",
-			},
-			wantNotContains: []string{
-				"only checked",
-			},
-		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/anchore/binny/internal/log"
+)
+
+type checksumRecordKey struct{}
+
+// ErrChecksumRequired is returned when checksums are required but nothing is available to verify a download with.
+type ErrChecksumRequired struct {
+	Tool string
+	// Source describes what could not be verified (e.g. a release asset or install script).
+	Source string
+}
+
+func (e *ErrChecksumRequired) Error() string {
+	return fmt.Sprintf("checksum required for tool %q but none is available for %s", e.Tool, e.Source)
+}
+
+// ChecksumRecord collects how the downloads of a single tool installation were verified.
+type ChecksumRecord struct {
+	tool       string
+	required   bool
+	lock       sync.Mutex
+	sources    []string
+	moduleHash string
+	sourceURL  string
+}
+
+// WithChecksumRecord returns a context that installers report checksum sources to (see ChecksumRecordFromContext).
+func WithChecksumRecord(ctx context.Context, tool string, required bool) (context.Context, *ChecksumRecord) {
+	r := &ChecksumRecord{tool: tool, required: required}
+	return context.WithValue(ctx, checksumRecordKey{}, r), r
+}
+
+// ChecksumRecordFromContext returns the record for the installation in progress. Without one, checksums are not
+// required and sources are discarded.
+func ChecksumRecordFromContext(ctx context.Context) *ChecksumRecord {
+	if r, ok := ctx.Value(checksumRecordKey{}).(*ChecksumRecord); ok && r != nil {
+		return r
+	}
+	return &ChecksumRecord{}
+}
+
+// Verified records the source of a checksum (or signature) that a download was verified against.
+func (r *ChecksumRecord) Verified(source string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.sources = append(r.sources, source)
+}
+
+// Missing reports that there is nothing to verify the given download with, which is an error when checksums are
+// required.
+func (r *ChecksumRecord) Missing(source string) error {
+	if r.required {
+		return &ErrChecksumRequired{Tool: r.tool, Source: source}
+	}
+	log.WithFields("tool", r.tool, "source", source).Debug("no checksum available")
+	return nil
+}
+
+// Source describes everything that the downloads were verified against (empty when nothing was verified).
+func (r *ChecksumRecord) Source() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return strings.Join(r.sources, ", ")
+}
+
+// SetModuleHash records the hash ("h1:...") of the go module that the tool was built from.
+func (r *ChecksumRecord) SetModuleHash(hash string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.moduleHash = hash
+}
+
+// ModuleHash returns the hash of the go module that the tool was built from (empty when not built from a module).
+func (r *ChecksumRecord) ModuleHash() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.moduleHash
+}
+
+// SetSourceURL records where the tool was downloaded from (or the go module and version it was built from).
+func (r *ChecksumRecord) SetSourceURL(url string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.sourceURL = url
+}
+
+// SourceURL returns where the tool was downloaded from (empty when not reported by the installer).
+func (r *ChecksumRecord) SourceURL() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.sourceURL
+}
@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksumRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		required   bool
+		verified   []string
+		missing    string
+		wantSource string
+		wantErr    require.ErrorAssertionFunc
+	}{
+		{
+			name:       "verified sources are joined",
+			required:   true,
+			verified:   []string{`checksums file "checksums.txt"`, `provenance for "tool.tar.gz"`},
+			wantSource: `checksums file "checksums.txt", provenance for "tool.tar.gz"`,
+		},
+		{
+			name:    "missing checksum is allowed when not required",
+			missing: `install script "https://example.com/install.sh"`,
+		},
+		{
+			name:     "missing checksum names the tool and source when required",
+			required: true,
+			missing:  `install script "https://example.com/install.sh"`,
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				var required *ErrChecksumRequired
+				require.ErrorAs(t, err, &required)
+				require.EqualError(t, err, `checksum required for tool "tool" but none is available for install script "https://example.com/install.sh"`)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			ctx, record := WithChecksumRecord(context.Background(), "tool", tt.required)
+			for _, source := range tt.verified {
+				ChecksumRecordFromContext(ctx).Verified(source)
+			}
+
+			var err error
+			if tt.missing != "" {
+				err = ChecksumRecordFromContext(ctx).Missing(tt.missing)
+			}
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.wantSource, record.Source())
+		})
+	}
+}
+
+func TestChecksumRecordFromContext_withoutRecord(t *testing.T) {
+	record := ChecksumRecordFromContext(context.Background())
+	require.NoError(t, record.Missing("anything"))
+	record.Verified("something")
+	assert.Equal(t, "something", record.Source())
+}
+
+func TestChecksumRecord_ModuleHash(t *testing.T) {
+	ctx, record := WithChecksumRecord(context.Background(), "tool", false)
+	assert.Empty(t, record.ModuleHash())
+
+	ChecksumRecordFromContext(ctx).SetModuleHash("h1:abc=")
+	assert.Equal(t, "h1:abc=", record.ModuleHash())
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Digest hashes the contents with the algorithm of the given checksum, and formats the result the same way (with
+// the same algorithm prefix, if any).
+func Digest(reader io.Reader, like string) (string, error) {
+	h := getHasher(like)
+	if _, err := io.Copy(h, reader); err != nil {
+		return "", err
+	}
+
+	digest := fmt.Sprintf("%x", h.Sum(nil))
+	if prefix, _, ok := strings.Cut(like, ":"); ok {
+		return prefix + ":" + digest, nil
+	}
+	return digest, nil
+}
+
+// VerifyFile checks the file against the checksum (optionally prefixed with the hash algorithm).
+func VerifyFile(path, checksum string) error {
+	fh, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	actual, err := Digest(fh, checksum)
+	if err != nil {
+		return fmt.Errorf("unable to hash %q: %w", path, err)
+	}
+
+	if !strings.EqualFold(cleanChecksum(actual), cleanChecksum(checksum)) {
+		return fmt.Errorf("checksum mismatch for %q: expected %q but got %q", path, checksum, actual)
+	}
+	return nil
+}
+
+// ChecksumsConflict reports whether two checksums are for different contents. Checksums made with hash algorithms of
+// different lengths cannot be compared, so they never conflict.
+func ChecksumsConflict(a, b string) bool {
+	a, b = cleanChecksum(a), cleanChecksum(b)
+	return len(a) == len(b) && !strings.EqualFold(a, b)
+}
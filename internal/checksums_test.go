@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigest(t *testing.T) {
+	contents := "binary contents"
+	tests := []struct {
+		name string
+		like string
+		want string
+	}{
+		{
+			name: "defaults to sha256",
+			like: "",
+			want: fmt.Sprintf("%x", sha256.Sum256([]byte(contents))),
+		},
+		{
+			name: "keeps the algorithm prefix",
+			like: "sha256:stale",
+			want: fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(contents))),
+		},
+		{
+			name: "sha512",
+			like: "sha512:stale",
+			want: fmt.Sprintf("sha512:%x", sha512.Sum512([]byte(contents))),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Digest(strings.NewReader(contents), tt.like)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVerifyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool")
+	require.NoError(t, os.WriteFile(path, []byte("binary contents"), 0o600))
+	digest := fmt.Sprintf("%x", sha256.Sum256([]byte("binary contents")))
+
+	tests := []struct {
+		name     string
+		checksum string
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			name:     "plain",
+			checksum: digest,
+		},
+		{
+			name:     "prefixed and upper case",
+			checksum: "sha256:" + strings.ToUpper(digest),
+		},
+		{
+			name:     "mismatch",
+			checksum: fmt.Sprintf("%x", sha256.Sum256([]byte("other"))),
+			wantErr:  require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			tt.wantErr(t, VerifyFile(path, tt.checksum))
+		})
+	}
+}
+
+func TestChecksumsConflict(t *testing.T) {
+	sha256Digest := fmt.Sprintf("%x", sha256.Sum256([]byte("a")))
+	sha512Digest := fmt.Sprintf("%x", sha512.Sum512([]byte("a")))
+
+	assert.False(t, ChecksumsConflict(sha256Digest, "sha256:"+strings.ToUpper(sha256Digest)))
+	assert.False(t, ChecksumsConflict(sha256Digest, "sha512:"+sha512Digest))
+	assert.True(t, ChecksumsConflict(sha256Digest, fmt.Sprintf("%x", sha256.Sum256([]byte("b")))))
+}
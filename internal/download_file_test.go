@@ -2,14 +2,19 @@ package internal
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	internalhttp "github.com/anchore/binny/internal/http"
 )
 
 func Test_DownloadFile(t *testing.T) {
@@ -59,3 +64,28 @@ func Test_DownloadFile(t *testing.T) {
 		})
 	}
 }
+
+func Test_DownloadFile_neverCached(t *testing.T) {
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// a small, cacheable looking document (e.g. an install script or a checksums file) that changes every time
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, requests.Add(1)))
+		_, _ = fmt.Fprintf(w, "revision %d", requests.Load())
+	}))
+	t.Cleanup(s.Close)
+
+	cache, err := internalhttp.NewCache(internalhttp.CacheConfig{Dir: t.TempDir(), TTL: time.Hour})
+	require.NoError(t, err)
+	ctx := internalhttp.WithCache(context.Background(), cache)
+
+	dlPath := filepath.Join(t.TempDir(), "install.sh")
+	for _, want := range []string{"revision 1", "revision 2"} {
+		require.NoError(t, DownloadFile(ctx, s.URL, dlPath, ""))
+
+		gotContents, err := os.ReadFile(dlPath)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(gotContents))
+	}
+	assert.Equal(t, int32(2), requests.Load())
+}
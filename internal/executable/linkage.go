@@ -0,0 +1,291 @@
+package executable
+
+import (
+	"bufio"
+	"context"
+	"debug/elf"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	LibcGlibc = "glibc"
+	LibcMusl  = "musl"
+)
+
+// LinkageError describes what a dynamically linked ELF executable needs in order to run that the host does not have.
+type LinkageError struct {
+	Path string
+	// Interpreter is the missing dynamic loader (PT_INTERP), empty when it is installed.
+	Interpreter string
+	// Libraries are the missing shared libraries (DT_NEEDED).
+	Libraries []string
+	// Libc is the C library the executable was built against (empty when not known).
+	Libc string
+	// HostLibc is the C library of the host (empty when not known).
+	HostLibc string
+	// Hint suggests an alternative that is more likely to run on the host (e.g. another release asset).
+	Hint string
+}
+
+func (e *LinkageError) Error() string {
+	var missing []string
+	if e.Interpreter != "" {
+		missing = append(missing, fmt.Sprintf("the interpreter %s", e.Interpreter))
+	}
+	if len(e.Libraries) > 0 {
+		missing = append(missing, fmt.Sprintf("the libraries %s", strings.Join(e.Libraries, ", ")))
+	}
+
+	msg := fmt.Sprintf("%q cannot run on this host, missing %s", e.Path, strings.Join(missing, " and "))
+	if e.Libc != "" && e.HostLibc != "" && e.Libc != e.HostLibc {
+		msg += fmt.Sprintf(" (it is built against %s, but the host uses %s)", e.Libc, e.HostLibc)
+	}
+	if e.Hint != "" {
+		msg += ": " + e.Hint
+	}
+	return msg
+}
+
+type linkageHintKey struct{}
+
+// LinkageHint is where an installer leaves a suggestion for when the executable it installed cannot run on the host,
+// which is added to the LinkageError when the linkage is checked.
+type LinkageHint struct {
+	hint string
+}
+
+// WithLinkageHint returns a context that installers leave a hint in (see LinkageHintFromContext).
+func WithLinkageHint(ctx context.Context) (context.Context, *LinkageHint) {
+	h := &LinkageHint{}
+	return context.WithValue(ctx, linkageHintKey{}, h), h
+}
+
+// LinkageHintFromContext returns the hint for the installation in progress. Without one, hints are discarded.
+func LinkageHintFromContext(ctx context.Context) *LinkageHint {
+	if h, ok := ctx.Value(linkageHintKey{}).(*LinkageHint); ok && h != nil {
+		return h
+	}
+	return &LinkageHint{}
+}
+
+// Set records the hint.
+func (h *LinkageHint) Set(hint string) {
+	h.hint = hint
+}
+
+// String returns the hint (empty when there is none).
+func (h *LinkageHint) String() string {
+	if h == nil {
+		return ""
+	}
+	return h.hint
+}
+
+// MissingInterpreter reports whether the executable cannot be started at all (as opposed to libraries that may be
+// found somewhere that is not searched for them).
+func (e *LinkageError) MissingInterpreter() bool {
+	return e.Interpreter != ""
+}
+
+// CheckLinkage checks that the interpreter and shared libraries that a dynamically linked ELF executable needs are
+// installed on the host, returning a LinkageError when they are not. Other files (and other hosts than linux) are
+// not checked.
+func CheckLinkage(path string) error {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	return checkLinkage(path, "/", os.Getenv("LD_LIBRARY_PATH"))
+}
+
+// checkLinkage checks the executable against the filesystem at the root.
+func checkLinkage(path, root, ldLibraryPath string) error {
+	f, err := elf.Open(path)
+	if err != nil {
+		// not an ELF file (scripts are checked by their interpreter instead)
+		return nil
+	}
+	defer f.Close()
+
+	interp, err := interpreter(f)
+	if err != nil {
+		return fmt.Errorf("unable to read the interpreter of %q: %w", path, err)
+	}
+	if interp == "" {
+		// statically linked
+		return nil
+	}
+
+	lerr := &LinkageError{
+		Path:     path,
+		Libc:     interpreterLibc(interp),
+		HostLibc: hostLibc(root),
+	}
+	if !exists(filepath.Join(root, interp)) {
+		lerr.Interpreter = interp
+	}
+
+	needed, err := f.ImportedLibraries()
+	if err != nil {
+		return fmt.Errorf("unable to read the libraries needed by %q: %w", path, err)
+	}
+	dirs := librarySearchPath(f, path, root, ldLibraryPath, lerr.Libc)
+	for _, lib := range needed {
+		if !findLibrary(lib, dirs) {
+			lerr.Libraries = append(lerr.Libraries, lib)
+		}
+	}
+
+	if lerr.Interpreter == "" && len(lerr.Libraries) == 0 {
+		return nil
+	}
+	return lerr
+}
+
+func interpreter(f *elf.File) (string, error) {
+	for _, prog := range f.Progs {
+		if prog.Type != elf.PT_INTERP {
+			continue
+		}
+		contents := make([]byte, prog.Filesz)
+		if _, err := prog.ReadAt(contents, 0); err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(contents), "\x00"), nil
+	}
+	return "", nil
+}
+
+func interpreterLibc(interp string) string {
+	name := filepath.Base(interp)
+	switch {
+	case strings.HasPrefix(name, "ld-musl"):
+		return LibcMusl
+	case strings.HasPrefix(name, "ld-linux"):
+		return LibcGlibc
+	}
+	return ""
+}
+
+// HostLibc returns the C library of the host (LibcGlibc or LibcMusl), or an empty string when it is not known (e.g.
+// when the host is not linux).
+func HostLibc() string {
+	if runtime.GOOS != "linux" {
+		return ""
+	}
+	return hostLibc("/")
+}
+
+func hostLibc(root string) string {
+	if matches, _ := filepath.Glob(filepath.Join(root, "lib", "ld-musl-*.so.1")); len(matches) > 0 {
+		return LibcMusl
+	}
+	for _, pattern := range []string{"lib*/ld-linux*.so.*", "lib/*/ld-linux*.so.*", "usr/lib*/ld-linux*.so.*"} {
+		if matches, _ := filepath.Glob(filepath.Join(root, pattern)); len(matches) > 0 {
+			return LibcGlibc
+		}
+	}
+	return ""
+}
+
+// librarySearchPath returns the directories that the dynamic loader searches for shared libraries. This is an
+// approximation of the loader's rules (e.g. hardware capability subdirectories are not searched), which is why
+// missing libraries are reported separately from a missing interpreter.
+func librarySearchPath(f *elf.File, path, root, ldLibraryPath, libc string) []string {
+	origin := filepath.Dir(path)
+
+	var dirs []string
+	for _, tag := range []elf.DynTag{elf.DT_RPATH, elf.DT_RUNPATH} {
+		values, _ := f.DynString(tag)
+		for _, value := range values {
+			for _, dir := range filepath.SplitList(value) {
+				dir = strings.NewReplacer("${ORIGIN}", origin, "$ORIGIN", origin).Replace(dir)
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	dirs = append(dirs, filepath.SplitList(ldLibraryPath)...)
+
+	var system []string
+	if libc == LibcMusl {
+		system = muslSearchPath(root)
+	} else {
+		system = append(readLdSoConf(root, filepath.Join(root, "etc", "ld.so.conf"), 0), "/lib", "/usr/lib", "/lib64", "/usr/lib64")
+	}
+	for _, dir := range system {
+		dirs = append(dirs, filepath.Join(root, dir))
+	}
+	return dirs
+}
+
+// muslSearchPath returns the system library directories of the musl loader (from /etc/ld-musl-ARCH.path).
+func muslSearchPath(root string) []string {
+	matches, _ := filepath.Glob(filepath.Join(root, "etc", "ld-musl-*.path"))
+	for _, match := range matches {
+		contents, err := os.ReadFile(match)
+		if err != nil {
+			continue
+		}
+		return strings.FieldsFunc(string(contents), func(r rune) bool {
+			return r == ':' || r == '\n' || r == ' ' || r == '\t'
+		})
+	}
+	return []string{"/lib", "/usr/local/lib", "/usr/lib"}
+}
+
+// readLdSoConf returns the library directories listed in the glibc loader configuration (following includes).
+func readLdSoConf(root, path string, depth int) []string {
+	if depth > 8 {
+		return nil
+	}
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer fh.Close()
+
+	var dirs []string
+	scanner := bufio.NewScanner(fh)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case fields[0] == "include":
+			for _, pattern := range fields[1:] {
+				if !filepath.IsAbs(pattern) {
+					pattern = filepath.Join(filepath.Dir(strings.TrimPrefix(path, root)), pattern)
+				}
+				matches, _ := filepath.Glob(filepath.Join(root, pattern))
+				for _, match := range matches {
+					dirs = append(dirs, readLdSoConf(root, match, depth+1)...)
+				}
+			}
+		default:
+			dirs = append(dirs, fields...)
+		}
+	}
+	return dirs
+}
+
+func findLibrary(name string, dirs []string) bool {
+	if strings.Contains(name, "/") {
+		return exists(name)
+	}
+	for _, dir := range dirs {
+		if exists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil || !errors.Is(err, os.ErrNotExist)
+}
@@ -0,0 +1,151 @@
+package executable
+
+import (
+	"debug/elf"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dynamicExecutable returns a dynamically linked executable of the host, with its interpreter and needed libraries.
+func dynamicExecutable(t *testing.T) (string, string, []string) {
+	t.Helper()
+	for _, path := range []string{"/bin/ls", "/usr/bin/ls", "/bin/sh"} {
+		f, err := elf.Open(path)
+		if err != nil {
+			continue
+		}
+		interp, err := interpreter(f)
+		require.NoError(t, err)
+		needed, err := f.ImportedLibraries()
+		require.NoError(t, err)
+		f.Close()
+		if interp != "" && len(needed) > 0 {
+			return path, interp, needed
+		}
+	}
+	t.Skip("no dynamically linked executable found on the host")
+	return "", "", nil
+}
+
+func touch(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+}
+
+func TestCheckLinkage(t *testing.T) {
+	path, interp, needed := dynamicExecutable(t)
+	libc := interpreterLibc(interp)
+
+	// the executable runs on the host, so everything it needs is installed
+	require.NoError(t, CheckLinkage(path))
+
+	tests := []struct {
+		name          string
+		setup         func(t *testing.T, root string)
+		ldLibraryPath func(root string) string
+		want          *LinkageError
+	}{
+		{
+			name: "nothing installed",
+			want: &LinkageError{Path: path, Interpreter: interp, Libraries: needed, Libc: libc},
+		},
+		{
+			name: "libraries found through the loader configuration",
+			setup: func(t *testing.T, root string) {
+				touch(t, filepath.Join(root, interp))
+				require.NoError(t, os.MkdirAll(filepath.Join(root, "etc", "ld.so.conf.d"), 0o755))
+				require.NoError(t, os.WriteFile(filepath.Join(root, "etc", "ld.so.conf"), []byte("include /etc/ld.so.conf.d/*.conf\n"), 0o600))
+				require.NoError(t, os.WriteFile(filepath.Join(root, "etc", "ld.so.conf.d", "libs.conf"), []byte("# comment\n/opt/libs\n"), 0o600))
+				for _, lib := range needed {
+					touch(t, filepath.Join(root, "opt", "libs", lib))
+				}
+			},
+		},
+		{
+			name: "libraries found through LD_LIBRARY_PATH",
+			setup: func(t *testing.T, root string) {
+				touch(t, filepath.Join(root, interp))
+				for _, lib := range needed {
+					touch(t, filepath.Join(root, "elsewhere", lib))
+				}
+			},
+			ldLibraryPath: func(root string) string {
+				return filepath.Join(root, "elsewhere")
+			},
+		},
+		{
+			name: "missing libraries",
+			setup: func(t *testing.T, root string) {
+				touch(t, filepath.Join(root, interp))
+			},
+			want: &LinkageError{Path: path, Libraries: needed, Libc: libc, HostLibc: libc},
+		},
+		{
+			name: "different libc on the host",
+			setup: func(t *testing.T, root string) {
+				touch(t, filepath.Join(root, "lib", "ld-musl-x86_64.so.1"))
+			},
+			want: &LinkageError{Path: path, Interpreter: interp, Libraries: needed, Libc: libc, HostLibc: LibcMusl},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			if tt.setup != nil {
+				tt.setup(t, root)
+			}
+			var ldLibraryPath string
+			if tt.ldLibraryPath != nil {
+				ldLibraryPath = tt.ldLibraryPath(root)
+			}
+
+			err := checkLinkage(path, root, ldLibraryPath)
+			if tt.want == nil {
+				require.NoError(t, err)
+				return
+			}
+			var lerr *LinkageError
+			require.True(t, errors.As(err, &lerr))
+			assert.Equal(t, tt.want, lerr)
+			assert.Equal(t, tt.want.Interpreter != "", lerr.MissingInterpreter())
+		})
+	}
+}
+
+func TestCheckLinkage_notDynamic(t *testing.T) {
+	dir := t.TempDir()
+
+	static := filepath.Join(dir, "static")
+	require.NoError(t, os.WriteFile(static, elfFile(t, elf.EM_X86_64, elf.ET_EXEC, elf.ELFOSABI_NONE), 0o700))
+	require.NoError(t, checkLinkage(static, dir, ""))
+
+	script := filepath.Join(dir, "script")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n"), 0o700))
+	require.NoError(t, checkLinkage(script, dir, ""))
+}
+
+func TestLinkageError_Error(t *testing.T) {
+	err := &LinkageError{
+		Path:        "/bin/tool",
+		Interpreter: "/lib64/ld-linux-x86-64.so.2",
+		Libraries:   []string{"libc.so.6", "libz.so.1"},
+		Libc:        LibcGlibc,
+		HostLibc:    LibcMusl,
+	}
+	assert.Equal(t, `"/bin/tool" cannot run on this host, missing the interpreter /lib64/ld-linux-x86-64.so.2 and the libraries libc.so.6, libz.so.1 (it is built against glibc, but the host uses musl)`, err.Error())
+}
+
+func TestLinkageError_Error_hint(t *testing.T) {
+	err := &LinkageError{
+		Path:      "/bin/tool",
+		Libraries: []string{"libz.so.1"},
+		Hint:      `the release also has the asset "tool_linux_amd64_musl", which can be selected with the 'assets' option`,
+	}
+	assert.Equal(t, `"/bin/tool" cannot run on this host, missing the libraries libz.so.1: the release also has the asset "tool_linux_amd64_musl", which can be selected with the 'assets' option`, err.Error())
+}
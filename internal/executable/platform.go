@@ -0,0 +1,146 @@
+package executable
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"slices"
+	"strings"
+)
+
+// elfOSABI maps the OS ABIs that identify a specific OS to GOOS values. Most binaries (including all linux binaries
+// built by Go) use the generic System V ABI, which does not identify the OS.
+var elfOSABI = map[elf.OSABI]string{
+	elf.ELFOSABI_LINUX:   "linux",
+	elf.ELFOSABI_FREEBSD: "freebsd",
+	elf.ELFOSABI_NETBSD:  "netbsd",
+	elf.ELFOSABI_OPENBSD: "openbsd",
+	elf.ELFOSABI_SOLARIS: "solaris",
+}
+
+func elfOS(f *elf.File) string {
+	return elfOSABI[f.OSABI]
+}
+
+// compatibleArchs lists the architectures (besides its own) of the executables that run on a platform, through
+// emulation or a 32-bit compatibility mode.
+var compatibleArchs = map[string][]string{
+	"darwin/arm64":  {"amd64"},        // Rosetta 2
+	"windows/arm64": {"amd64", "386"}, // x64 and x86 emulation
+	"windows/amd64": {"386"},          // WOW64
+	"linux/amd64":   {"386"},
+	"freebsd/amd64": {"386"},
+}
+
+// runsOn reports whether an executable for the architecture runs on the platform.
+func runsOn(arch, goos, goarch string) bool {
+	return arch == goarch || slices.Contains(compatibleArchs[goos+"/"+goarch], arch)
+}
+
+// isELFPlatform reports whether executables on the OS are ELF files.
+func isELFPlatform(goos string) bool {
+	switch goos {
+	case "darwin", "ios", "windows", "plan9", "js", "wasip1", "aix":
+		return false
+	}
+	return true
+}
+
+func isMachOPlatform(goos string) bool {
+	return goos == "darwin" || goos == "ios"
+}
+
+// elfArch returns the GOARCH of the ELF file (empty when the machine is not supported by Go).
+func elfArch(f *elf.File) string {
+	is64 := f.Class == elf.ELFCLASS64
+	le := f.ByteOrder == binary.LittleEndian
+
+	switch f.Machine {
+	case elf.EM_X86_64:
+		return "amd64"
+	case elf.EM_386:
+		return "386"
+	case elf.EM_AARCH64:
+		return "arm64"
+	case elf.EM_ARM:
+		return "arm"
+	case elf.EM_LOONGARCH:
+		return "loong64"
+	case elf.EM_PPC64:
+		return withEndianSuffix("ppc64", le)
+	case elf.EM_MIPS:
+		if is64 {
+			return withEndianSuffix("mips64", le)
+		}
+		return withEndianSuffix("mips", le)
+	case elf.EM_RISCV:
+		if is64 {
+			return "riscv64"
+		}
+	case elf.EM_S390:
+		if is64 {
+			return "s390x"
+		}
+	}
+	return ""
+}
+
+func withEndianSuffix(arch string, littleEndian bool) string {
+	if littleEndian {
+		return arch + "le"
+	}
+	return arch
+}
+
+func elfTypeName(t elf.Type) string {
+	switch t {
+	case elf.ET_REL:
+		return "relocatable object"
+	case elf.ET_CORE:
+		return "core file"
+	}
+	return strings.ToLower(strings.TrimPrefix(t.String(), "ET_"))
+}
+
+// machoArch returns the GOARCH of the Mach-O CPU type (empty when not supported by Go).
+func machoArch(cpu macho.Cpu) string {
+	switch cpu {
+	case macho.CpuAmd64:
+		return "amd64"
+	case macho.CpuArm64:
+		return "arm64"
+	case macho.Cpu386:
+		return "386"
+	case macho.CpuArm:
+		return "arm"
+	}
+	return ""
+}
+
+func machoTypeName(t macho.Type) string {
+	switch t {
+	case macho.TypeObj:
+		return "object"
+	case macho.TypeDylib:
+		return "dynamic library"
+	case macho.TypeBundle:
+		return "bundle"
+	}
+	return t.String()
+}
+
+// peArch returns the GOARCH of the PE machine type (empty when not supported by Go).
+func peArch(machine uint16) string {
+	switch machine {
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return "amd64"
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return "386"
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return "arm64"
+	case pe.IMAGE_FILE_MACHINE_ARMNT:
+		return "arm"
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package executable
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+var (
+	elfMagic = []byte("\x7fELF")
+	peMagic  = []byte("MZ")
+	shebang  = []byte("#!")
+	// thin (32 and 64 bit, in either byte order) and fat (universal) Mach-O files
+	machoMagics = [][]byte{
+		{0xfe, 0xed, 0xfa, 0xce},
+		{0xfe, 0xed, 0xfa, 0xcf},
+		{0xce, 0xfa, 0xed, 0xfe},
+		{0xcf, 0xfa, 0xed, 0xfe},
+		{0xca, 0xfe, 0xba, 0xbe},
+	}
+)
+
+// Validate checks that the file is an executable that runs on the given platform (or a script with a shebang),
+// returning an error that explains what the file is otherwise. Executables for another architecture that the platform
+// runs (e.g. darwin/amd64 on darwin/arm64 through Rosetta, or 386 on amd64) are accepted.
+func Validate(path, goos, goarch string) error {
+	fh, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("unable to open %q: %w", path, err)
+	}
+	defer fh.Close()
+
+	header := make([]byte, 4)
+	n, err := io.ReadFull(fh, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("unable to read %q: %w", path, err)
+	}
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, elfMagic):
+		err = validateELF(fh, goos, goarch)
+	case isMachO(header):
+		err = validateMachO(fh, header, goos, goarch)
+	case bytes.HasPrefix(header, peMagic):
+		err = validatePE(fh, goos, goarch)
+	case bytes.HasPrefix(header, shebang):
+		if goos == "windows" {
+			err = fmt.Errorf("is a script, which cannot be run directly on windows")
+		}
+	default:
+		err = fmt.Errorf("is not an executable or a script with a shebang (detected %s)", detectType(path))
+	}
+
+	if err != nil {
+		return fmt.Errorf("%q %w", path, err)
+	}
+	return nil
+}
+
+func isMachO(header []byte) bool {
+	return slices.ContainsFunc(machoMagics, func(magic []byte) bool {
+		return bytes.Equal(header, magic)
+	})
+}
+
+func validateELF(r io.ReaderAt, goos, goarch string) error {
+	f, err := elf.NewFile(r)
+	if err != nil {
+		return fmt.Errorf("is not a valid ELF file: %w", err)
+	}
+	defer f.Close()
+
+	if f.Type != elf.ET_EXEC && f.Type != elf.ET_DYN {
+		return fmt.Errorf("is an ELF %s, not an executable", elfTypeName(f.Type))
+	}
+
+	arch := elfArch(f)
+	fileOS := elfOS(f)
+	if !isELFPlatform(goos) || (fileOS != "" && fileOS != goos) || !runsOn(arch, goos, goarch) {
+		return mismatch("an ELF", orDefault(fileOS, "unix"), orDefault(arch, f.Machine.String()), goos, goarch)
+	}
+	return nil
+}
+
+func validateMachO(r io.ReaderAt, header []byte, goos, goarch string) error {
+	if bytes.Equal(header, machoMagics[len(machoMagics)-1]) {
+		return validateFatMachO(r, goos, goarch)
+	}
+
+	f, err := macho.NewFile(r)
+	if err != nil {
+		return fmt.Errorf("is not a valid Mach-O file: %w", err)
+	}
+	defer f.Close()
+
+	if f.Type != macho.TypeExec {
+		return fmt.Errorf("is a Mach-O %s, not an executable", machoTypeName(f.Type))
+	}
+
+	arch := machoArch(f.Cpu)
+	if !isMachOPlatform(goos) || !runsOn(arch, goos, goarch) {
+		return mismatch("a Mach-O", "darwin", orDefault(arch, f.Cpu.String()), goos, goarch)
+	}
+	return nil
+}
+
+func validateFatMachO(r io.ReaderAt, goos, goarch string) error {
+	f, err := macho.NewFatFile(r)
+	if err != nil {
+		return fmt.Errorf("is not a valid universal Mach-O file: %w", err)
+	}
+	defer f.Close()
+
+	// the platform runs the slice for its own architecture when there is one
+	var compatible *macho.FatArch
+	var platforms []string
+	for i, a := range f.Arches {
+		arch := orDefault(machoArch(a.Cpu), a.Cpu.String())
+		if isMachOPlatform(goos) && runsOn(arch, goos, goarch) && (compatible == nil || arch == goarch) {
+			compatible = &f.Arches[i]
+		}
+		platforms = append(platforms, "darwin/"+arch)
+	}
+	if compatible != nil {
+		if compatible.Type != macho.TypeExec {
+			return fmt.Errorf("is a universal Mach-O %s, not an executable", machoTypeName(compatible.Type))
+		}
+		return nil
+	}
+	return fmt.Errorf("is a universal Mach-O executable for %s, but the target platform is %s/%s", strings.Join(platforms, ", "), goos, goarch)
+}
+
+func validatePE(r io.ReaderAt, goos, goarch string) error {
+	f, err := pe.NewFile(r)
+	if err != nil {
+		return fmt.Errorf("is not a valid PE file: %w", err)
+	}
+	defer f.Close()
+
+	if f.Characteristics&pe.IMAGE_FILE_DLL != 0 {
+		return fmt.Errorf("is a PE DLL, not an executable")
+	}
+	if f.Characteristics&pe.IMAGE_FILE_EXECUTABLE_IMAGE == 0 {
+		return fmt.Errorf("is a PE object, not an executable")
+	}
+
+	arch := peArch(f.Machine)
+	if goos != "windows" || !runsOn(arch, goos, goarch) {
+		return mismatch("a PE", "windows", orDefault(arch, fmt.Sprintf("machine 0x%x", f.Machine)), goos, goarch)
+	}
+	return nil
+}
+
+func mismatch(format, fileOS, fileArch, goos, goarch string) error {
+	return fmt.Errorf("is %s executable for %s/%s, but the target platform is %s/%s", format, fileOS, fileArch, goos, goarch)
+}
+
+// detectType describes the content of a file that is not an executable (e.g. an archive that was not extracted).
+func detectType(path string) string {
+	m, err := mimetype.DetectFile(path)
+	if err != nil {
+		return "unknown content"
+	}
+	return m.String()
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
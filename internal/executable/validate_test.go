@@ -0,0 +1,293 @@
+package executable
+
+import (
+	"bytes"
+	"compress/gzip"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func elfFile(t *testing.T, machine elf.Machine, typ elf.Type, osABI elf.OSABI) []byte {
+	t.Helper()
+	hdr := elf.Header64{
+		Type:      uint16(typ),
+		Machine:   uint16(machine),
+		Version:   uint32(elf.EV_CURRENT),
+		Ehsize:    64,
+		Phentsize: 56,
+		Shentsize: 64,
+	}
+	copy(hdr.Ident[:], elf.ELFMAG)
+	hdr.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	hdr.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	hdr.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	hdr.Ident[elf.EI_OSABI] = byte(osABI)
+
+	var buf bytes.Buffer
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, hdr))
+	return buf.Bytes()
+}
+
+func machoFile(t *testing.T, cpu macho.Cpu, typ macho.Type) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, macho.FileHeader{Magic: macho.Magic64, Cpu: cpu, Type: typ}))
+	// reserved field of the 64-bit header
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, uint32(0)))
+	return buf.Bytes()
+}
+
+func fatMachoFile(t *testing.T, cpus ...macho.Cpu) []byte {
+	t.Helper()
+	const slot = 64
+
+	var buf bytes.Buffer
+	require.NoError(t, binary.Write(&buf, binary.BigEndian, []uint32{macho.MagicFat, uint32(len(cpus))}))
+	var thin [][]byte
+	for i, cpu := range cpus {
+		f := machoFile(t, cpu, macho.TypeExec)
+		thin = append(thin, f)
+		require.NoError(t, binary.Write(&buf, binary.BigEndian, macho.FatArchHeader{
+			Cpu:    cpu,
+			Offset: uint32(slot * (i + 1)),
+			Size:   uint32(len(f)),
+		}))
+	}
+	for _, f := range thin {
+		buf.Write(make([]byte, slot-buf.Len()%slot))
+		buf.Write(f)
+	}
+	return buf.Bytes()
+}
+
+func peFile(t *testing.T, machine uint16, characteristics uint16) []byte {
+	t.Helper()
+	const peOffset = 0x40
+
+	dos := make([]byte, peOffset)
+	copy(dos, "MZ")
+	binary.LittleEndian.PutUint32(dos[0x3c:], peOffset)
+
+	var buf bytes.Buffer
+	buf.Write(dos)
+	buf.WriteString("PE\x00\x00")
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, pe.FileHeader{Machine: machine, Characteristics: characteristics}))
+	// padding for reading the (empty) COFF string table
+	buf.Write(make([]byte, 64))
+	return buf.Bytes()
+}
+
+func gzipFile(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(elfFile(t, elf.EM_X86_64, elf.ET_EXEC, elf.ELFOSABI_NONE))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestValidate(t *testing.T) {
+	peExec := uint16(pe.IMAGE_FILE_EXECUTABLE_IMAGE)
+
+	tests := []struct {
+		name     string
+		contents []byte
+		goos     string
+		goarch   string
+		wantErr  string
+	}{
+		{
+			name:     "ELF executable",
+			contents: elfFile(t, elf.EM_X86_64, elf.ET_EXEC, elf.ELFOSABI_NONE),
+			goos:     "linux",
+			goarch:   "amd64",
+		},
+		{
+			name:     "ELF position independent executable",
+			contents: elfFile(t, elf.EM_AARCH64, elf.ET_DYN, elf.ELFOSABI_NONE),
+			goos:     "linux",
+			goarch:   "arm64",
+		},
+		{
+			name:     "ELF for another architecture",
+			contents: elfFile(t, elf.EM_AARCH64, elf.ET_EXEC, elf.ELFOSABI_NONE),
+			goos:     "linux",
+			goarch:   "amd64",
+			wantErr:  "is an ELF executable for unix/arm64, but the target platform is linux/amd64",
+		},
+		{
+			name:     "ELF for 386 on amd64",
+			contents: elfFile(t, elf.EM_386, elf.ET_EXEC, elf.ELFOSABI_NONE),
+			goos:     "linux",
+			goarch:   "amd64",
+		},
+		{
+			name:     "ELF for amd64 on 386",
+			contents: elfFile(t, elf.EM_X86_64, elf.ET_EXEC, elf.ELFOSABI_NONE),
+			goos:     "linux",
+			goarch:   "386",
+			wantErr:  "is an ELF executable for unix/amd64, but the target platform is linux/386",
+		},
+		{
+			name:     "ELF for another OS",
+			contents: elfFile(t, elf.EM_X86_64, elf.ET_EXEC, elf.ELFOSABI_FREEBSD),
+			goos:     "linux",
+			goarch:   "amd64",
+			wantErr:  "is an ELF executable for freebsd/amd64, but the target platform is linux/amd64",
+		},
+		{
+			name:     "ELF on darwin",
+			contents: elfFile(t, elf.EM_AARCH64, elf.ET_EXEC, elf.ELFOSABI_NONE),
+			goos:     "darwin",
+			goarch:   "arm64",
+			wantErr:  "is an ELF executable for unix/arm64, but the target platform is darwin/arm64",
+		},
+		{
+			name:     "ELF object",
+			contents: elfFile(t, elf.EM_X86_64, elf.ET_REL, elf.ELFOSABI_NONE),
+			goos:     "linux",
+			goarch:   "amd64",
+			wantErr:  "is an ELF relocatable object, not an executable",
+		},
+		{
+			name:     "Mach-O executable",
+			contents: machoFile(t, macho.CpuArm64, macho.TypeExec),
+			goos:     "darwin",
+			goarch:   "arm64",
+		},
+		{
+			name:     "Mach-O for another architecture",
+			contents: machoFile(t, macho.CpuArm64, macho.TypeExec),
+			goos:     "darwin",
+			goarch:   "amd64",
+			wantErr:  "is a Mach-O executable for darwin/arm64, but the target platform is darwin/amd64",
+		},
+		{
+			name:     "Mach-O for amd64 on arm64 (Rosetta)",
+			contents: machoFile(t, macho.CpuAmd64, macho.TypeExec),
+			goos:     "darwin",
+			goarch:   "arm64",
+		},
+		{
+			name:     "Mach-O dynamic library",
+			contents: machoFile(t, macho.CpuArm64, macho.TypeDylib),
+			goos:     "darwin",
+			goarch:   "arm64",
+			wantErr:  "is a Mach-O dynamic library, not an executable",
+		},
+		{
+			name:     "universal Mach-O",
+			contents: fatMachoFile(t, macho.CpuAmd64, macho.CpuArm64),
+			goos:     "darwin",
+			goarch:   "arm64",
+		},
+		{
+			name:     "universal Mach-O without the architecture",
+			contents: fatMachoFile(t, macho.CpuArm64, macho.Cpu386),
+			goos:     "darwin",
+			goarch:   "amd64",
+			wantErr:  "is a universal Mach-O executable for darwin/arm64, darwin/386, but the target platform is darwin/amd64",
+		},
+		{
+			name:     "universal Mach-O with a compatible architecture",
+			contents: fatMachoFile(t, macho.CpuAmd64, macho.Cpu386),
+			goos:     "darwin",
+			goarch:   "arm64",
+		},
+		{
+			name:     "PE executable",
+			contents: peFile(t, pe.IMAGE_FILE_MACHINE_AMD64, peExec),
+			goos:     "windows",
+			goarch:   "amd64",
+		},
+		{
+			name:     "PE for amd64 on arm64",
+			contents: peFile(t, pe.IMAGE_FILE_MACHINE_AMD64, peExec),
+			goos:     "windows",
+			goarch:   "arm64",
+		},
+		{
+			name:     "PE for 386 on amd64",
+			contents: peFile(t, pe.IMAGE_FILE_MACHINE_I386, peExec),
+			goos:     "windows",
+			goarch:   "amd64",
+		},
+		{
+			name:     "PE for arm64 on amd64",
+			contents: peFile(t, pe.IMAGE_FILE_MACHINE_ARM64, peExec),
+			goos:     "windows",
+			goarch:   "amd64",
+			wantErr:  "is a PE executable for windows/arm64, but the target platform is windows/amd64",
+		},
+		{
+			name:     "PE on linux",
+			contents: peFile(t, pe.IMAGE_FILE_MACHINE_AMD64, peExec),
+			goos:     "linux",
+			goarch:   "amd64",
+			wantErr:  "is a PE executable for windows/amd64, but the target platform is linux/amd64",
+		},
+		{
+			name:     "PE DLL",
+			contents: peFile(t, pe.IMAGE_FILE_MACHINE_AMD64, peExec|pe.IMAGE_FILE_DLL),
+			goos:     "windows",
+			goarch:   "amd64",
+			wantErr:  "is a PE DLL, not an executable",
+		},
+		{
+			name:     "script with a shebang",
+			contents: []byte("#!/bin/sh\necho hello\n"),
+			goos:     "linux",
+			goarch:   "amd64",
+		},
+		{
+			name:     "script on windows",
+			contents: []byte("#!/bin/sh\necho hello\n"),
+			goos:     "windows",
+			goarch:   "amd64",
+			wantErr:  "is a script, which cannot be run directly on windows",
+		},
+		{
+			name:     "archive",
+			contents: gzipFile(t),
+			goos:     "linux",
+			goarch:   "amd64",
+			wantErr:  "is not an executable or a script with a shebang (detected application/gzip)",
+		},
+		{
+			name:    "empty file",
+			goos:    "linux",
+			goarch:  "amd64",
+			wantErr: "is not an executable or a script with a shebang",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tool")
+			require.NoError(t, os.WriteFile(path, tt.contents, 0o700))
+
+			err := Validate(path, tt.goos, tt.goarch)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidate_hostExecutable(t *testing.T) {
+	path, err := os.Executable()
+	require.NoError(t, err)
+	require.NoError(t, Validate(path, runtime.GOOS, runtime.GOARCH))
+}
@@ -0,0 +1,80 @@
+package gosumdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/mod/sumdb"
+
+	"github.com/anchore/binny/internal"
+	"github.com/anchore/binny/internal/log"
+)
+
+var _ sumdb.ClientOps = (*clientOps)(nil)
+
+// clientOps fetches from the checksum database with the shared HTTP client. Nothing is cached between runs, so the
+// latest signed tree is only checked for consistency within a single lookup.
+type clientOps struct {
+	ctx    context.Context
+	key    string
+	base   string
+	lock   sync.Mutex
+	config map[string][]byte
+}
+
+func newClientOps(ctx context.Context, key, base string) *clientOps {
+	return &clientOps{
+		ctx:    ctx,
+		key:    key,
+		base:   base,
+		config: map[string][]byte{},
+	}
+}
+
+func (o *clientOps) ReadRemote(path string) ([]byte, error) {
+	reader, err := internal.DownloadURL(o.ctx, o.base+path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func (o *clientOps) ReadConfig(file string) ([]byte, error) {
+	if file == "key" {
+		return []byte(o.key), nil
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	// a missing latest tree is empty, which starts from an empty tree
+	return o.config[file], nil
+}
+
+func (o *clientOps) WriteConfig(file string, old, new []byte) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if !bytes.Equal(o.config[file], old) {
+		return sumdb.ErrWriteConflict
+	}
+	o.config[file] = new
+	return nil
+}
+
+func (o *clientOps) ReadCache(file string) ([]byte, error) {
+	return nil, fmt.Errorf("no cache for %q", file)
+}
+
+func (o *clientOps) WriteCache(string, []byte) {}
+
+func (o *clientOps) Log(msg string) {
+	log.Trace(msg)
+}
+
+func (o *clientOps) SecurityError(msg string) {
+	log.Error(msg)
+}
@@ -0,0 +1,151 @@
+package gosumdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/note"
+)
+
+const (
+	defaultGoSumDB = "sum.golang.org"
+	goSumDBOff     = "off"
+)
+
+// knownGoSumDB holds the verifier keys of well-known checksum databases, so they can be referred to by name alone
+// (the same as the go command).
+var knownGoSumDB = map[string]string{
+	"sum.golang.org": "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8",
+}
+
+// Verifier checks module hashes against a checksum database.
+type Verifier interface {
+	// Enabled reports whether the module is expected to be in the checksum database.
+	Enabled(modulePath string) bool
+	// Name is the name of the checksum database (e.g. "sum.golang.org").
+	Name() string
+	// Verify checks that the database lists the given hash ("h1:...") for the module version.
+	Verify(ctx context.Context, modulePath, version, hash string) error
+}
+
+var _ Verifier = (*Config)(nil)
+
+// Config determines which checksum database modules are verified against, following the go command's GOSUMDB,
+// GONOSUMDB, and GOPRIVATE settings.
+type Config struct {
+	// GoSumDB is the checksum database, as either a name ("sum.golang.org"), a verifier key, or a key followed by the
+	// URL to reach it at. "off" disables verification.
+	GoSumDB string
+	// NoSumDB is a comma-separated list of module path prefix patterns that are not verified.
+	NoSumDB string
+}
+
+// ConfigFromEnv returns the checksum database configuration from the environment.
+func ConfigFromEnv() Config {
+	noSumDB, ok := os.LookupEnv("GONOSUMDB")
+	if !ok {
+		noSumDB = os.Getenv("GOPRIVATE")
+	}
+	return Config{
+		GoSumDB: os.Getenv("GOSUMDB"),
+		NoSumDB: noSumDB,
+	}
+}
+
+func (c Config) goSumDB() string {
+	if c.GoSumDB == "" {
+		return defaultGoSumDB
+	}
+	return c.GoSumDB
+}
+
+func (c Config) Enabled(modulePath string) bool {
+	return c.goSumDB() != goSumDBOff && !module.MatchPrefixPatterns(c.NoSumDB, modulePath)
+}
+
+// Unverifiable describes why the module version cannot be verified against the checksum database (empty when it can).
+// The database only lists module versions (not branches or commits) that are not excluded by GOSUMDB or GONOSUMDB.
+func Unverifiable(v Verifier, modulePath, version string) string {
+	switch {
+	case !semver.IsValid(version):
+		return fmt.Sprintf("%q is not a module version (e.g. a branch or commit)", version)
+	case !v.Enabled(modulePath):
+		return "the module is excluded from the checksum database (GOSUMDB, GONOSUMDB, or GOPRIVATE)"
+	}
+	return ""
+}
+
+func (c Config) Name() string {
+	fields := strings.Fields(c.goSumDB())
+	if len(fields) == 0 {
+		return ""
+	}
+	name, _, _ := strings.Cut(fields[0], "+")
+	return name
+}
+
+func (c Config) Verify(ctx context.Context, modulePath, version, hash string) error {
+	if !c.Enabled(modulePath) {
+		return fmt.Errorf("checksum database is disabled for module %q", modulePath)
+	}
+
+	client, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	lines, err := client.Lookup(modulePath, version)
+	if err != nil {
+		return fmt.Errorf("unable to look up %s@%s in checksum database %s: %w", modulePath, version, c.Name(), err)
+	}
+
+	prefix := modulePath + " " + version + " "
+	for _, line := range lines {
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		if want := strings.TrimPrefix(line, prefix); want != hash {
+			return fmt.Errorf("module %s@%s does not match checksum database %s: got %s but expected %s", modulePath, version, c.Name(), hash, want)
+		}
+		return nil
+	}
+	return fmt.Errorf("module %s@%s is not listed in checksum database %s", modulePath, version, c.Name())
+}
+
+// client connects to the database the same way as the go command, except that it does not go through a proxy.
+func (c Config) client(ctx context.Context) (*sumdb.Client, error) {
+	value := c.goSumDB()
+	if value == "sum.golang.google.cn" {
+		// an alias for sum.golang.org that is reachable inside mainland China
+		value = "sum.golang.org https://sum.golang.google.cn"
+	}
+
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid GOSUMDB %q", value)
+	}
+	if key := knownGoSumDB[fields[0]]; key != "" {
+		fields[0] = key
+	}
+
+	verifier, err := note.NewVerifier(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid GOSUMDB: %w", err)
+	}
+
+	base := "https://" + verifier.Name()
+	if len(fields) == 2 {
+		if _, err := url.Parse(fields[1]); err != nil {
+			return nil, fmt.Errorf("invalid GOSUMDB URL: %w", err)
+		}
+		base = fields[1]
+	}
+
+	return sumdb.NewClient(newClientOps(ctx, fields[0], strings.TrimSuffix(base, "/"))), nil
+}
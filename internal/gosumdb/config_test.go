@@ -0,0 +1,162 @@
+package gosumdb
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/note"
+)
+
+const (
+	testModule  = "example.com/tool"
+	testVersion = "v1.2.3"
+	testHash    = "h1:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+)
+
+// newTestDatabase serves a checksum database listing testHash for testModule@testVersion, returning its GOSUMDB value.
+func newTestDatabase(t *testing.T) string {
+	t.Helper()
+
+	signer, verifier, err := note.GenerateKey(rand.Reader, "sum.example.com")
+	require.NoError(t, err)
+
+	ops := sumdb.NewTestServer(signer, func(path, vers string) ([]byte, error) {
+		if path != testModule || vers != testVersion {
+			return nil, os.ErrNotExist
+		}
+		return []byte(fmt.Sprintf("%s %s %s\n%s %s/go.mod %s\n", path, vers, testHash, path, vers, testHash)), nil
+	})
+	s := httptest.NewServer(sumdb.NewServer(ops))
+	t.Cleanup(s.Close)
+
+	return verifier + " " + s.URL
+}
+
+func TestConfig_Verify(t *testing.T) {
+	goSumDB := newTestDatabase(t)
+
+	tests := []struct {
+		name    string
+		config  Config
+		module  string
+		version string
+		hash    string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:    "hash matches",
+			config:  Config{GoSumDB: goSumDB},
+			module:  testModule,
+			version: testVersion,
+			hash:    testHash,
+		},
+		{
+			name:    "hash does not match",
+			config:  Config{GoSumDB: goSumDB},
+			module:  testModule,
+			version: testVersion,
+			hash:    "h1:BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB=",
+			wantErr: require.Error,
+		},
+		{
+			name:    "version not in the database",
+			config:  Config{GoSumDB: goSumDB},
+			module:  testModule,
+			version: "v9.9.9",
+			hash:    testHash,
+			wantErr: require.Error,
+		},
+		{
+			name:    "disabled",
+			config:  Config{GoSumDB: "off"},
+			module:  testModule,
+			version: testVersion,
+			hash:    testHash,
+			wantErr: require.Error,
+		},
+		{
+			name:    "invalid key",
+			config:  Config{GoSumDB: "sum.example.com+bogus"},
+			module:  testModule,
+			version: testVersion,
+			hash:    testHash,
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			tt.wantErr(t, tt.config.Verify(context.Background(), tt.module, tt.version, tt.hash))
+		})
+	}
+}
+
+func TestConfig_Enabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		module string
+		want   bool
+	}{
+		{name: "default database", module: "github.com/anchore/syft", want: true},
+		{name: "off", config: Config{GoSumDB: "off"}, module: "github.com/anchore/syft", want: false},
+		{name: "excluded by pattern", config: Config{NoSumDB: "example.com,github.com/anchore/*"}, module: "github.com/anchore/syft", want: false},
+		{name: "not excluded by pattern", config: Config{NoSumDB: "example.com"}, module: "github.com/anchore/syft", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.config.Enabled(tt.module))
+		})
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("GOSUMDB", "sum.golang.google.cn")
+	t.Setenv("GOPRIVATE", "example.com/private")
+	// GOPRIVATE only applies when GONOSUMDB is not set (Setenv restores the original value after the test)
+	t.Setenv("GONOSUMDB", "")
+	require.NoError(t, os.Unsetenv("GONOSUMDB"))
+
+	cfg := ConfigFromEnv()
+	assert.Equal(t, "sum.golang.google.cn", cfg.Name())
+	assert.False(t, cfg.Enabled("example.com/private/tool"))
+
+	t.Setenv("GONOSUMDB", "example.com/other")
+	cfg = ConfigFromEnv()
+	assert.True(t, cfg.Enabled("example.com/private/tool"))
+	assert.False(t, cfg.Enabled("example.com/other/tool"))
+}
+
+func TestHashDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+testModule+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/main\n"), 0o600))
+
+	got, err := HashDir(dir, testModule, testVersion)
+	require.NoError(t, err)
+	assert.Regexp(t, `^h1:[A-Za-z0-9+/]{43}=$`, got)
+
+	// VCS metadata is not part of the module
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/other\n"), 0o600))
+	again, err := HashDir(dir, testModule, testVersion)
+	require.NoError(t, err)
+	assert.Equal(t, got, again)
+
+	// but any change to the source is
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // tampered\n"), 0o600))
+	tampered, err := HashDir(dir, testModule, testVersion)
+	require.NoError(t, err)
+	assert.NotEqual(t, got, tampered)
+}
@@ -0,0 +1,39 @@
+package gosumdb
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb/dirhash"
+	"golang.org/x/mod/zip"
+)
+
+// HashDir returns the hash ("h1:...") of the module zip that the go command would create from the module source in
+// the directory, which is what the checksum database lists for the module version.
+func HashDir(dir, modulePath, version string) (string, error) {
+	fh, err := os.CreateTemp("", "binny-module-*.zip")
+	if err != nil {
+		return "", fmt.Errorf("unable to create module zip: %w", err)
+	}
+	defer os.Remove(fh.Name())
+	defer fh.Close()
+
+	if err := zip.CreateFromDir(fh, module.Version{Path: modulePath, Version: version}, dir); err != nil {
+		return "", fmt.Errorf("unable to create module zip for %s@%s: %w", modulePath, version, err)
+	}
+	if err := fh.Close(); err != nil {
+		return "", err
+	}
+
+	return HashZip(fh.Name())
+}
+
+// HashZip returns the hash ("h1:...") of a module zip.
+func HashZip(path string) (string, error) {
+	hash, err := dirhash.HashZip(path, dirhash.Hash1)
+	if err != nil {
+		return "", fmt.Errorf("unable to hash module zip %q: %w", path, err)
+	}
+	return hash, nil
+}
@@ -0,0 +1,340 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// maxCachedBodySize bounds the size of a response that will be stored in the cache. Version resolution responses
+// are small documents, anything larger (e.g. a release asset) is passed through untouched.
+const maxCachedBodySize = 4 * 1024 * 1024
+
+// CacheStatusHeader is set on every response that passed through the cache, indicating how it was served.
+const CacheStatusHeader = "X-Binny-Cache"
+
+const (
+	cacheHit         = "hit"
+	cacheRevalidated = "revalidated"
+	cacheMiss        = "miss"
+)
+
+type cacheCtxKey struct{}
+
+type resolutionClientCtxKey struct{}
+
+// CacheConfig describes where cached responses are stored and for how long they are used without revalidation.
+type CacheConfig struct {
+	// Dir is the directory where cached responses are stored.
+	Dir string
+	// TTL is how long a cached response is used without contacting the server. Once expired the response is
+	// revalidated with the server (using If-None-Match / If-Modified-Since) before being used again.
+	TTL time.Duration
+	// Refresh causes every cached response to be revalidated, regardless of the TTL.
+	Refresh bool
+}
+
+// Cache is an on-disk HTTP response cache for version resolution requests (API and proxy documents).
+type Cache struct {
+	config CacheConfig
+	now    func() time.Time
+}
+
+// NewCache creates a cache that stores responses in the configured directory.
+func NewCache(cfg CacheConfig) (*Cache, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("no HTTP cache directory configured")
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create HTTP cache directory %q: %w", cfg.Dir, err)
+	}
+	return &Cache{
+		config: cfg,
+		now:    time.Now,
+	}, nil
+}
+
+// WithCache returns a new context where the version resolution client (see ResolutionClientFromContext) is layered on
+// top of the given cache. The client from ClientFromContext, which downloads release assets, install scripts,
+// checksums, and signatures, is left uncached. The cache is also attached to the context so that purpose-built
+// clients (e.g. the authenticated GitHub client) can make use of it. This should be the last layer added to the
+// client in the context.
+func WithCache(ctx context.Context, cache *Cache) context.Context {
+	if cache == nil {
+		return ctx
+	}
+
+	client := withTransport(ClientFromContext(ctx), cache.Transport)
+
+	ctx = context.WithValue(ctx, cacheCtxKey{}, cache)
+	return context.WithValue(ctx, resolutionClientCtxKey{}, client)
+}
+
+// ResolutionClientFromContext retrieves the HTTP client for version resolution requests (API and proxy documents),
+// which serves responses from the cache when it is enabled. Falls back to ClientFromContext otherwise.
+func ResolutionClientFromContext(ctx context.Context) *retryablehttp.Client {
+	if client, ok := ctx.Value(resolutionClientCtxKey{}).(*retryablehttp.Client); ok && client != nil {
+		return client
+	}
+	return ClientFromContext(ctx)
+}
+
+// CacheFromContext retrieves the HTTP cache from context (nil if caching is not enabled).
+func CacheFromContext(ctx context.Context) *Cache {
+	cache, _ := ctx.Value(cacheCtxKey{}).(*Cache)
+	return cache
+}
+
+// Transport returns a round tripper that serves GET requests from the cache. A nil cache returns the given
+// transport unchanged.
+func (c *Cache) Transport(base http.RoundTripper) http.RoundTripper {
+	if c == nil {
+		return base
+	}
+	return &cachingTransport{cache: c, base: transportOrDefault(base)}
+}
+
+// QueryTransport returns a round tripper that, in addition to GET requests, caches POST requests that carry a
+// GraphQL query (mutations are never cached). A nil cache returns the given transport unchanged.
+func (c *Cache) QueryTransport(base http.RoundTripper) http.RoundTripper {
+	if c == nil {
+		return base
+	}
+	return &cachingTransport{cache: c, base: transportOrDefault(base), graphql: true}
+}
+
+func transportOrDefault(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		return http.DefaultTransport
+	}
+	return base
+}
+
+// cacheEntry is the on-disk representation of a cached response.
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	StoredAt     time.Time   `json:"storedAt"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"lastModified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+type cachingTransport struct {
+	cache   *Cache
+	base    http.RoundTripper
+	graphql bool
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, ok, err := t.key(req)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	entry := t.cache.read(key)
+	if entry != nil && !t.cache.config.Refresh && t.cache.now().Sub(entry.StoredAt) < t.cache.config.TTL {
+		return entry.response(req, cacheHit), nil
+	}
+
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		entry.StoredAt = t.cache.now()
+		t.cache.write(key, entry)
+		return entry.response(req, cacheRevalidated), nil
+	}
+
+	return t.store(key, req, resp)
+}
+
+// key returns the cache key for the request, or false if the request should not be cached. Credentials are part
+// of the key so that responses are never shared between identities.
+func (t *cachingTransport) key(req *http.Request) (string, bool, error) {
+	var body []byte
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !t.graphql || req.Body == nil {
+			return "", false, nil
+		}
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", false, err
+		}
+		// the body has been consumed, replace it so the request can still be sent
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if !isGraphQLQuery(body) {
+			return "", false, nil
+		}
+	default:
+		return "", false, nil
+	}
+
+	h := sha256.New()
+	for _, part := range []string{req.Method, req.URL.String(), req.Header.Get("Accept"), req.Header.Get("Authorization")} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), true, nil
+}
+
+// isGraphQLQuery reports whether the request body is a GraphQL query (as opposed to a mutation or subscription).
+func isGraphQLQuery(body []byte) bool {
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return false
+	}
+	query := strings.TrimSpace(payload.Query)
+	return strings.HasPrefix(query, "{") || strings.HasPrefix(query, "query")
+}
+
+// store caches a successful response (when it is small enough and looks like a document) and returns an equivalent
+// response to the caller.
+func (t *cachingTransport) store(key string, req *http.Request, resp *http.Response) (*http.Response, error) {
+	if !isCacheable(resp) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBodySize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedBodySize {
+		// too large to cache, pass the (partially read) body through
+		resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	entry := &cacheEntry{
+		URL:          req.URL.String(),
+		StoredAt:     t.cache.now(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Header:       resp.Header.Clone(),
+		Body:         body,
+	}
+	t.cache.write(key, entry)
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.Header.Set(CacheStatusHeader, cacheMiss)
+	return resp, nil
+}
+
+func isCacheable(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	if strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store") {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.config.Dir, key+".json")
+}
+
+// read returns the cached entry for the key, treating any unreadable entry as a miss.
+func (c *Cache) read(key string) *cacheEntry {
+	contents, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(contents, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// write stores the entry for the key. Failing to write to the cache is not fatal (the response is still served).
+func (c *Cache) write(key string, entry *cacheEntry) {
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	// write to a temp file and rename so that concurrent readers never observe a partial entry
+	f, err := os.CreateTemp(c.config.Dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(contents)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return
+	}
+	if err := os.Rename(f.Name(), c.path(key)); err != nil {
+		_ = os.Remove(f.Name())
+	}
+}
+
+func (e cacheEntry) response(req *http.Request, status string) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(CacheStatusHeader, status)
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
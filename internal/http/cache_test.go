@@ -0,0 +1,188 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingServer serves a fixed document with an ETag, counting full responses and revalidations separately.
+type countingServer struct {
+	full        atomic.Int32
+	notModified atomic.Int32
+	contentType string
+	body        string
+}
+
+func (s *countingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const etag = `"v1"`
+	if r.Header.Get("If-None-Match") == etag {
+		s.notModified.Add(1)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.full.Add(1)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", s.contentType)
+	_, _ = io.WriteString(w, s.body)
+}
+
+func newTestCache(t *testing.T, ttl time.Duration, refresh bool) (*Cache, *time.Time) {
+	t.Helper()
+	cache, err := NewCache(CacheConfig{Dir: t.TempDir(), TTL: ttl, Refresh: refresh})
+	require.NoError(t, err)
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	return cache, &now
+}
+
+func get(t *testing.T, ctx context.Context, url string) (string, string) {
+	t.Helper()
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	resp, err := ResolutionClientFromContext(ctx).Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body), resp.Header.Get(CacheStatusHeader)
+}
+
+func TestCache_TTLAndRevalidation(t *testing.T) {
+	server := &countingServer{contentType: "application/json", body: `{"Version":"v1.0.0"}`}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	cache, now := newTestCache(t, time.Hour, false)
+	ctx := WithCache(context.Background(), cache)
+
+	body, status := get(t, ctx, srv.URL)
+	assert.Equal(t, server.body, body)
+	assert.Equal(t, cacheMiss, status)
+
+	// within the TTL the server is not contacted
+	body, status = get(t, ctx, srv.URL)
+	assert.Equal(t, server.body, body)
+	assert.Equal(t, cacheHit, status)
+	assert.Equal(t, int32(1), server.full.Load())
+	assert.Equal(t, int32(0), server.notModified.Load())
+
+	// once expired the entry is revalidated with the etag
+	*now = now.Add(2 * time.Hour)
+	body, status = get(t, ctx, srv.URL)
+	assert.Equal(t, server.body, body)
+	assert.Equal(t, cacheRevalidated, status)
+	assert.Equal(t, int32(1), server.full.Load())
+	assert.Equal(t, int32(1), server.notModified.Load())
+
+	// revalidation restarts the TTL
+	_, status = get(t, ctx, srv.URL)
+	assert.Equal(t, cacheHit, status)
+}
+
+func TestCache_Refresh(t *testing.T) {
+	server := &countingServer{contentType: "text/plain; charset=utf-8", body: "v1.0.0\nv1.1.0\n"}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	dir := t.TempDir()
+
+	cache, err := NewCache(CacheConfig{Dir: dir, TTL: time.Hour})
+	require.NoError(t, err)
+	get(t, WithCache(context.Background(), cache), srv.URL)
+
+	// a refreshing cache over the same directory always revalidates
+	refreshing, err := NewCache(CacheConfig{Dir: dir, TTL: time.Hour, Refresh: true})
+	require.NoError(t, err)
+	ctx := WithCache(context.Background(), refreshing)
+
+	body, status := get(t, ctx, srv.URL)
+	assert.Equal(t, server.body, body)
+	assert.Equal(t, cacheRevalidated, status)
+	assert.Equal(t, int32(1), server.full.Load())
+	assert.Equal(t, int32(1), server.notModified.Load())
+}
+
+func TestCache_SkipsNonDocuments(t *testing.T) {
+	server := &countingServer{contentType: "application/octet-stream", body: "binary"}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	cache, _ := newTestCache(t, time.Hour, false)
+	ctx := WithCache(context.Background(), cache)
+
+	for range 2 {
+		body, status := get(t, ctx, srv.URL)
+		assert.Equal(t, server.body, body)
+		assert.Empty(t, status)
+	}
+	assert.Equal(t, int32(2), server.full.Load())
+}
+
+func TestCache_GraphQLQueries(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	cache, _ := newTestCache(t, time.Hour, false)
+	client := &http.Client{Transport: cache.QueryTransport(nil)}
+
+	post := func(payload, token string) string {
+		req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(payload))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "bearer "+token)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	query := `{"query":"query{repository{name}}"}`
+	assert.Equal(t, query, post(query, "a"))
+	assert.Equal(t, query, post(query, "a"))
+	assert.Equal(t, int32(1), requests.Load())
+
+	// responses are never shared between credentials
+	post(query, "b")
+	assert.Equal(t, int32(2), requests.Load())
+
+	// a different query is a different entry
+	other := `{"query":"query{viewer{login}}"}`
+	assert.Equal(t, other, post(other, "a"))
+	assert.Equal(t, int32(3), requests.Load())
+
+	// mutations are never cached
+	mutation := `{"query":"mutation{addStar{clientMutationId}}"}`
+	post(mutation, "a")
+	post(mutation, "a")
+	assert.Equal(t, int32(5), requests.Load())
+}
+
+func TestCache_NilCache(t *testing.T) {
+	var cache *Cache
+	ctx := context.Background()
+
+	assert.Equal(t, ctx, WithCache(ctx, cache))
+	assert.Nil(t, CacheFromContext(ctx))
+	assert.Nil(t, cache.QueryTransport(nil))
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/hashicorp/go-retryablehttp"
 )
@@ -28,3 +29,18 @@ func ClientFromContext(ctx context.Context) *retryablehttp.Client {
 	}
 	return defaultClient
 }
+
+// withTransport returns a copy of the client (with the same retry behavior) where the transport is wrapped.
+func withTransport(base *retryablehttp.Client, wrap func(http.RoundTripper) http.RoundTripper) *retryablehttp.Client {
+	client := retryablehttp.NewClient()
+	client.Logger = base.Logger
+	client.RetryWaitMin = base.RetryWaitMin
+	client.RetryWaitMax = base.RetryWaitMax
+	client.RetryMax = base.RetryMax
+	client.CheckRetry = base.CheckRetry
+	client.Backoff = base.Backoff
+	client.ErrorHandler = base.ErrorHandler
+	client.HTTPClient.Timeout = base.HTTPClient.Timeout
+	client.HTTPClient.Transport = wrap(base.HTTPClient.Transport)
+	return client
+}
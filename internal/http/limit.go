@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type limiterCtxKey struct{}
+
+// HostLimit bounds the requests made to a single host. Zero values are not limited.
+type HostLimit struct {
+	// MaxConcurrent is the number of requests to the host that can be in flight at once (a request is in flight until
+	// its response body is closed).
+	MaxConcurrent int
+	// RequestsPerSecond is the rate that requests to the host are started at.
+	RequestsPerSecond float64
+}
+
+// Limiter enforces per-host request limits across every client that shares it.
+type Limiter struct {
+	limits map[string]HostLimit
+	lock   sync.Mutex
+	hosts  map[string]*hostLimiter
+	now    func() time.Time
+}
+
+type hostLimiter struct {
+	slots    chan struct{}
+	interval time.Duration
+	lock     sync.Mutex
+	next     time.Time
+}
+
+// NewLimiter creates a limiter for the given hosts (e.g. "api.github.com"). Requests to other hosts are not limited.
+func NewLimiter(limits map[string]HostLimit) *Limiter {
+	normalized := make(map[string]HostLimit, len(limits))
+	for host, limit := range limits {
+		normalized[strings.ToLower(host)] = limit
+	}
+	return &Limiter{
+		limits: normalized,
+		hosts:  make(map[string]*hostLimiter),
+		now:    time.Now,
+	}
+}
+
+// WithLimiter returns a new context where the HTTP client enforces the limits of the given limiter. The limiter is also
+// attached to the context so that purpose-built clients (e.g. the authenticated GitHub client) enforce the same limits.
+func WithLimiter(ctx context.Context, limiter *Limiter) context.Context {
+	if limiter == nil {
+		return ctx
+	}
+
+	client := withTransport(ClientFromContext(ctx), limiter.Transport)
+
+	ctx = context.WithValue(ctx, limiterCtxKey{}, limiter)
+	return WithHTTPClient(ctx, client)
+}
+
+// LimiterFromContext retrieves the limiter from context (nil if requests are not limited).
+func LimiterFromContext(ctx context.Context) *Limiter {
+	limiter, _ := ctx.Value(limiterCtxKey{}).(*Limiter)
+	return limiter
+}
+
+// Transport returns a round tripper that waits for the limits of the request host before sending the request. A nil
+// limiter returns the given transport unchanged.
+func (l *Limiter) Transport(base http.RoundTripper) http.RoundTripper {
+	if l == nil {
+		return base
+	}
+	return &limitingTransport{limiter: l, base: transportOrDefault(base)}
+}
+
+// forHost returns the limiter for the host, or nil when requests to the host are not limited.
+func (l *Limiter) forHost(host string) *hostLimiter {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	limit, ok := l.limits[host]
+	if !ok || (limit.MaxConcurrent <= 0 && limit.RequestsPerSecond <= 0) {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if h, ok := l.hosts[host]; ok {
+		return h
+	}
+	h := &hostLimiter{}
+	if limit.MaxConcurrent > 0 {
+		h.slots = make(chan struct{}, limit.MaxConcurrent)
+	}
+	if limit.RequestsPerSecond > 0 {
+		h.interval = time.Duration(float64(time.Second) / limit.RequestsPerSecond)
+	}
+	l.hosts[host] = h
+	return h
+}
+
+// acquire waits until a request can be started, returning a function that marks the request as done.
+func (h *hostLimiter) acquire(ctx context.Context, now func() time.Time) (func(), error) {
+	if h.slots != nil {
+		select {
+		case h.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := func() {
+		if h.slots != nil {
+			<-h.slots
+		}
+	}
+
+	if wait := h.reserve(now()); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	return release, nil
+}
+
+// reserve claims the next start time for a request, returning how long to wait until then.
+func (h *hostLimiter) reserve(now time.Time) time.Duration {
+	if h.interval <= 0 {
+		return 0
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	start := h.next
+	if start.Before(now) {
+		start = now
+	}
+	h.next = start.Add(h.interval)
+	return start.Sub(now)
+}
+
+type limitingTransport struct {
+	limiter *Limiter
+	base    http.RoundTripper
+}
+
+func (t *limitingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := t.limiter.forHost(req.URL.Host)
+	if host == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	release, err := host.acquire(req.Context(), t.limiter.now)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		release()
+		return resp, err
+	}
+
+	// the request is in flight until the body is closed (e.g. a release asset is still being downloaded)
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: sync.OnceFunc(release)}
+	return resp, nil
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_maxConcurrent(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			previous := maxInFlight.Load()
+			if current <= previous || maxInFlight.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	host := mustParseURL(t, server.URL).Hostname()
+	ctx := WithLimiter(context.Background(), NewLimiter(map[string]HostLimit{host: {MaxConcurrent: 2}}))
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			get(t, ctx, server.URL)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), maxInFlight.Load())
+}
+
+func TestLimiter_unlimitedHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limiter := NewLimiter(map[string]HostLimit{"api.github.com": {MaxConcurrent: 1}})
+	assert.Nil(t, limiter.forHost(mustParseURL(t, server.URL).Host))
+	assert.NotNil(t, limiter.forHost("API.github.com:443"))
+
+	// requests to other hosts are not held up by a slot that is never released
+	ctx := WithLimiter(context.Background(), limiter)
+	get(t, ctx, server.URL)
+	get(t, ctx, server.URL)
+}
+
+func TestLimiter_releasesOnBodyClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	host := mustParseURL(t, server.URL).Hostname()
+	client := &http.Client{Transport: NewLimiter(map[string]HostLimit{host: {MaxConcurrent: 1}}).Transport(nil)}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	// the first response is still in flight, so the second request waits (until the context is done)
+	_, err = client.Do(req) //nolint:bodyclose
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, resp.Body.Close())
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+}
+
+func TestHostLimiter_reserve(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	h := &hostLimiter{interval: 100 * time.Millisecond}
+
+	// requests are spaced by the interval
+	assert.Equal(t, time.Duration(0), h.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, h.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, h.reserve(now))
+
+	// after a quiet period there is no wait
+	assert.Equal(t, time.Duration(0), h.reserve(now.Add(time.Second)))
+	assert.Equal(t, 50*time.Millisecond, h.reserve(now.Add(time.Second+50*time.Millisecond)))
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	return u
+}
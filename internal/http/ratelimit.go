@@ -0,0 +1,388 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/anchore/binny/event"
+	"github.com/anchore/binny/internal/bus"
+	"github.com/anchore/binny/internal/log"
+)
+
+// rateLimitResetSlack is added to the reported reset time before requests are resumed, since the reset is only given
+// to the second (and clocks drift).
+const rateLimitResetSlack = time.Second
+
+// lowRateLimitRatio is the fraction of a rate limit that, once fewer requests remain, is reported in the logs.
+const lowRateLimitRatio = 0.1
+
+type rateLimitsCtxKey struct{}
+
+// RateLimit is the state of a rate limit reported by a host, such as the GitHub "core" (REST) and "graphql" limits.
+type RateLimit struct {
+	Host      string    `json:"host"`
+	Resource  string    `json:"resource"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// Exhausted reports whether there are no requests left until the limit resets.
+func (l RateLimit) Exhausted(now time.Time) bool {
+	return l.Remaining <= 0 && l.Reset.After(now)
+}
+
+// Low reports whether fewer than a tenth of the requests remain.
+func (l RateLimit) Low() bool {
+	return float64(l.Remaining) < float64(l.Limit)*lowRateLimitRatio
+}
+
+func (l RateLimit) key() string {
+	return l.Host + "/" + l.Resource
+}
+
+// RateLimitError is returned instead of sending a request while a rate limit is exhausted and waiting for it to reset
+// would take longer than allowed.
+type RateLimitError struct {
+	RateLimit RateLimit
+	// Wait is how long until the rate limit resets.
+	Wait time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s %s rate limit exhausted (limit %d), resets at %s (in %s): not waiting longer than the rate limit wait allows",
+		e.RateLimit.Host, e.RateLimit.Resource, e.RateLimit.Limit, e.RateLimit.Reset.Local().Format(time.TimeOnly), e.Wait.Round(time.Second))
+}
+
+// RateLimits tracks the rate limits reported by hosts (with the X-RateLimit-* response headers, or as reported by
+// Observe) and holds back requests while a limit is exhausted, waiting up to a maximum for the limit to reset before
+// failing the request with a RateLimitError.
+type RateLimits struct {
+	maxWait time.Duration
+	lock    sync.Mutex
+	limits  map[string]RateLimit
+	// waiting is the reset time of the limits that a wait is already being reported for (by key)
+	waiting map[string]time.Time
+	now     func() time.Time
+}
+
+// NewRateLimits creates a rate limit tracker that waits up to maxWait for an exhausted limit to reset (zero fails
+// right away).
+func NewRateLimits(maxWait time.Duration) *RateLimits {
+	return &RateLimits{
+		maxWait: maxWait,
+		limits:  make(map[string]RateLimit),
+		waiting: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// WithRateLimits returns a new context where the HTTP client tracks (and waits for) rate limits with the given tracker.
+// The tracker is also attached to the context so that purpose-built clients (e.g. the authenticated GitHub client)
+// share the same state.
+func WithRateLimits(ctx context.Context, limits *RateLimits) context.Context {
+	if limits == nil {
+		return ctx
+	}
+
+	client := withTransport(ClientFromContext(ctx), limits.Transport)
+	client.CheckRetry = withoutRateLimitRetries(client.CheckRetry)
+
+	ctx = context.WithValue(ctx, rateLimitsCtxKey{}, limits)
+	return WithHTTPClient(ctx, client)
+}
+
+// RateLimitsFromContext retrieves the rate limit tracker from context (nil if rate limits are not tracked).
+func RateLimitsFromContext(ctx context.Context) *RateLimits {
+	limits, _ := ctx.Value(rateLimitsCtxKey{}).(*RateLimits)
+	return limits
+}
+
+// withoutRateLimitRetries wraps the retry policy (the default policy when nil) so that requests failing with a
+// RateLimitError are not retried, since whether to wait for the rate limit has already been decided.
+func withoutRateLimitRetries(policy retryablehttp.CheckRetry) retryablehttp.CheckRetry {
+	if policy == nil {
+		policy = retryablehttp.DefaultRetryPolicy
+	}
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if rlErr := (&RateLimitError{}); errors.As(err, &rlErr) {
+			return false, nil
+		}
+		return policy(ctx, resp, err)
+	}
+}
+
+// Transport returns a round tripper that waits for exhausted rate limits before sending requests and records the rate
+// limits reported in responses. A nil tracker returns the given transport unchanged.
+func (r *RateLimits) Transport(base http.RoundTripper) http.RoundTripper {
+	if r == nil {
+		return base
+	}
+	return &rateLimitTransport{limits: r, base: transportOrDefault(base)}
+}
+
+// Observe records the state of a rate limit (e.g. as reported in the body of a GraphQL response).
+func (r *RateLimits) Observe(ctx context.Context, limit RateLimit) {
+	if r == nil {
+		return
+	}
+	limit.Host = strings.ToLower(limit.Host)
+
+	r.lock.Lock()
+	previous, seen := r.limits[limit.key()]
+	if seen && (limit.Reset.Before(previous.Reset) || (limit.Reset.Equal(previous.Reset) && limit.Remaining > previous.Remaining)) {
+		// the remaining requests only go down until the limit resets, so this is stale (e.g. from a cached response or
+		// a response that was overtaken by another)
+		r.lock.Unlock()
+		return
+	}
+	r.limits[limit.key()] = limit
+	r.lock.Unlock()
+
+	lgr := log.FromContext(ctx).WithFields("host", limit.Host, "resource", limit.Resource, "remaining", limit.Remaining, "limit", limit.Limit, "reset", limit.Reset.Local().Format(time.TimeOnly))
+	switch {
+	case limit.Low() && (!seen || !previous.Low()):
+		lgr.Info("rate limit running low")
+	case !seen:
+		lgr.Info("rate limit")
+	default:
+		lgr.Debug("rate limit")
+	}
+}
+
+// Limits returns the last known state of every rate limit, ordered by host and resource.
+func (r *RateLimits) Limits() []RateLimit {
+	if r == nil {
+		return nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var limits []RateLimit
+	for _, limit := range r.limits {
+		limits = append(limits, limit)
+	}
+	slices.SortFunc(limits, func(a, b RateLimit) int {
+		return strings.Compare(a.key(), b.key())
+	})
+	return limits
+}
+
+// wait blocks until the rate limit for the key resets, failing when the reset is further away than the remaining
+// budget. It returns how long it waited.
+func (r *RateLimits) wait(ctx context.Context, key string, budget time.Duration) (time.Duration, error) {
+	r.lock.Lock()
+	limit, ok := r.limits[key]
+	now := r.now()
+	if !ok || !limit.Exhausted(now) {
+		r.lock.Unlock()
+		return 0, nil
+	}
+
+	wait := limit.Reset.Sub(now) + rateLimitResetSlack
+	if wait > budget {
+		r.lock.Unlock()
+		return 0, &RateLimitError{RateLimit: limit, Wait: wait}
+	}
+
+	// concurrent requests held back by the same limit report a single wait
+	report := !r.waiting[key].Equal(limit.Reset)
+	if report {
+		r.waiting[key] = limit.Reset
+	}
+	r.lock.Unlock()
+
+	var prog *event.ManualStagedProgress
+	if report {
+		log.FromContext(ctx).WithFields("host", limit.Host, "resource", limit.Resource, "reset", limit.Reset.Local().Format(time.TimeOnly)).
+			Infof("rate limit exhausted, waiting %s for it to reset", wait.Round(time.Second))
+		prog = bus.PublishTask(
+			event.Title{
+				Default:      "Rate limit",
+				WhileRunning: "Waiting for rate limit",
+				OnSuccess:    "Rate limit reset",
+				OnFail:       "Rate limit wait cancelled",
+			},
+			fmt.Sprintf("%s %s", limit.Host, limit.Resource),
+			int(wait/time.Second),
+		)
+	}
+
+	err := sleep(ctx, wait, func(remaining time.Duration) {
+		if prog != nil {
+			prog.Manual.Set(int64((wait - remaining) / time.Second))
+			prog.AtomicStage.Set(fmt.Sprintf("resets in %s", remaining.Round(time.Second)))
+		}
+	})
+	if prog != nil {
+		if err != nil {
+			prog.SetError(err)
+		} else {
+			prog.SetCompleted()
+		}
+	}
+	return wait, err
+}
+
+// sleep waits for the duration (or until the context is done), reporting the time remaining every second.
+func sleep(ctx context.Context, d time.Duration, tick func(remaining time.Duration)) error {
+	deadline := time.Now().Add(d)
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	tick(d)
+	for {
+		select {
+		case <-timer.C:
+			return nil
+		case <-ticker.C:
+			tick(time.Until(deadline))
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// block marks the rate limit for the key as exhausted until the given time (e.g. from a Retry-After header).
+func (r *RateLimits) block(host, resource string, until time.Time) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	limit := RateLimit{Host: host, Resource: resource}
+	if existing, ok := r.limits[limit.key()]; ok {
+		limit = existing
+	}
+	// a reset that has already passed still holds requests back briefly, so that a host that keeps rejecting requests
+	// eventually exhausts the wait budget
+	if now := r.now(); !until.After(now) {
+		until = now.Add(time.Second)
+	}
+	limit.Remaining = 0
+	limit.Reset = until
+	r.limits[limit.key()] = limit
+}
+
+type rateLimitTransport struct {
+	limits *RateLimits
+	base   http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := strings.ToLower(req.URL.Hostname())
+	resource := requestResource(req)
+	budget := t.limits.maxWait
+
+	for {
+		waited, err := t.limits.wait(req.Context(), RateLimit{Host: host, Resource: resource}.key(), budget)
+		if err != nil {
+			return nil, err
+		}
+		budget -= waited
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+
+		limit, ok := parseRateLimitHeaders(host, resp.Header)
+		if ok {
+			resource = limit.Resource
+			t.limits.Observe(req.Context(), limit)
+		}
+
+		until, limited := rateLimitedUntil(resp, t.limits.now())
+		if !limited {
+			return resp, nil
+		}
+		t.limits.block(host, resource, until)
+
+		// the request can only be sent again if its body can be replayed
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+		_ = resp.Body.Close()
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// requestResource is the rate limit a request counts against before the response says otherwise (GitHub reports the
+// resource in the X-RateLimit-Resource header).
+func requestResource(req *http.Request) string {
+	if strings.HasSuffix(req.URL.Path, "/graphql") {
+		return "graphql"
+	}
+	return "core"
+}
+
+// parseRateLimitHeaders reads the X-RateLimit-* headers of a response (as sent by GitHub).
+func parseRateLimitHeaders(host string, header http.Header) (RateLimit, bool) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	var reset time.Time
+	if epoch, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(epoch, 0)
+	}
+	resource := header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = "core"
+	}
+
+	return RateLimit{
+		Host:      host,
+		Resource:  resource,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     reset,
+	}, true
+}
+
+// rateLimitedUntil reports whether the response rejected the request for exceeding a rate limit, and when to try
+// again. GitHub rejects requests with 403 or 429 for both the primary limits (with no requests remaining) and the
+// secondary limits (with a Retry-After header).
+func rateLimitedUntil(resp *http.Response, now time.Time) (time.Time, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}, false
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return now.Add(time.Duration(seconds) * time.Second), true
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return at, true
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if epoch, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return time.Unix(epoch, 0), true
+		}
+	}
+
+	return time.Time{}, false
+}
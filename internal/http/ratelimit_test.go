@@ -0,0 +1,192 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimits_tracksHeaders(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if r.URL.Path == "/graphql" {
+			w.Header().Set("X-RateLimit-Resource", "graphql")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limits := NewRateLimits(0)
+	ctx := WithRateLimits(context.Background(), limits)
+	get(t, ctx, server.URL)
+	get(t, ctx, server.URL+"/graphql")
+
+	host := mustParseURL(t, server.URL).Hostname()
+	assert.Equal(t, []RateLimit{
+		{Host: host, Resource: "core", Limit: 5000, Remaining: 4321, Reset: reset},
+		{Host: host, Resource: "graphql", Limit: 5000, Remaining: 4321, Reset: reset},
+	}, limits.Limits())
+	assert.Same(t, limits, RateLimitsFromContext(ctx))
+}
+
+func TestRateLimits_Observe_ignoresStaleState(t *testing.T) {
+	reset := time.Date(2024, 6, 1, 1, 0, 0, 0, time.UTC)
+	current := RateLimit{Host: "api.github.com", Resource: "graphql", Limit: 5000, Remaining: 100, Reset: reset}
+
+	limits := NewRateLimits(0)
+	limits.Observe(context.Background(), current)
+
+	// more requests remaining within the same window (e.g. a cached response)
+	limits.Observe(context.Background(), RateLimit{Host: "api.github.com", Resource: "graphql", Limit: 5000, Remaining: 200, Reset: reset})
+	// an earlier window
+	limits.Observe(context.Background(), RateLimit{Host: "api.github.com", Resource: "graphql", Limit: 5000, Remaining: 0, Reset: reset.Add(-time.Hour)})
+	assert.Equal(t, []RateLimit{current}, limits.Limits())
+
+	// a new window
+	next := RateLimit{Host: "api.github.com", Resource: "graphql", Limit: 5000, Remaining: 4999, Reset: reset.Add(time.Hour)}
+	limits.Observe(context.Background(), next)
+	assert.Equal(t, []RateLimit{next}, limits.Limits())
+}
+
+func TestRateLimits_failsFast(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRateLimits(time.Minute).Transport(nil)}
+
+	for range 2 {
+		_, err := client.Get(server.URL) //nolint:bodyclose,noctx
+		var rlErr *RateLimitError
+		require.ErrorAs(t, err, &rlErr)
+		assert.Equal(t, "core", rlErr.RateLimit.Resource)
+		assert.Greater(t, rlErr.Wait, time.Minute)
+	}
+
+	// once the limit is known to be exhausted, requests are not sent at all
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestRateLimits_waitsForReset(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "query", string(body))
+
+		if requests.Add(1) == 1 {
+			// a secondary rate limit
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRateLimits(10 * time.Second).Transport(nil)}
+
+	start := time.Now()
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("query")) //nolint:noctx
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestWithRateLimits_doesNotRetry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx := WithRateLimits(context.Background(), NewRateLimits(0))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	_, err = ClientFromContext(ctx).StandardClient().Do(req) //nolint:bodyclose
+	var rlErr *RateLimitError
+	require.ErrorAs(t, err, &rlErr)
+
+	// a 429 would otherwise be retried
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func Test_rateLimitedUntil(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		status      int
+		header      map[string]string
+		want        time.Time
+		wantLimited bool
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			header: map[string]string{"X-RateLimit-Remaining": "0"},
+		},
+		{
+			name:   "forbidden for another reason",
+			status: http.StatusForbidden,
+			header: map[string]string{"X-RateLimit-Remaining": "10"},
+		},
+		{
+			name:        "primary limit exhausted",
+			status:      http.StatusForbidden,
+			header:      map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(now.Add(time.Hour).Unix(), 10)},
+			want:        now.Add(time.Hour),
+			wantLimited: true,
+		},
+		{
+			name:        "secondary limit in seconds",
+			status:      http.StatusTooManyRequests,
+			header:      map[string]string{"Retry-After": "60"},
+			want:        now.Add(time.Minute),
+			wantLimited: true,
+		},
+		{
+			name:        "secondary limit as a date",
+			status:      http.StatusForbidden,
+			header:      map[string]string{"Retry-After": now.Add(2 * time.Minute).Format(http.TimeFormat)},
+			want:        now.Add(2 * time.Minute),
+			wantLimited: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.header {
+				resp.Header.Set(k, v)
+			}
+
+			got, limited := rateLimitedUntil(resp, now)
+			assert.Equal(t, tt.wantLimited, limited)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package pgp
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+const armorPrefix = "-----BEGIN PGP"
+
+// Config describes which OpenPGP keys signatures are trusted from. Values that refer to keys are either a path to a
+// key file or inline armored contents.
+type Config struct {
+	// Keys are the pinned public keys of the signers (e.g. a maintainer's exported release key).
+	Keys []string `json:"keys" yaml:"keys,omitempty" mapstructure:"keys"`
+	// Keyring is a keyring file (armored or binary, e.g. from "gpg --export") holding the trusted public keys.
+	Keyring string `json:"keyring" yaml:"keyring,omitempty" mapstructure:"keyring"`
+}
+
+// IsSet reports whether any keys are configured.
+func (c Config) IsSet() bool {
+	return len(c.Keys) > 0 || c.Keyring != ""
+}
+
+// KeyRing loads all configured keys.
+func (c Config) KeyRing() (*KeyRing, error) {
+	var entities openpgp.EntityList
+	for _, key := range c.Keys {
+		el, err := readKeys(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gpg key: %w", err)
+		}
+		entities = append(entities, el...)
+	}
+
+	if c.Keyring != "" {
+		contents, err := os.ReadFile(c.Keyring)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read gpg keyring: %w", err)
+		}
+		el, err := parseKeys(contents)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gpg keyring %q: %w", c.Keyring, err)
+		}
+		entities = append(entities, el...)
+	}
+
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("no gpg keys found")
+	}
+	return &KeyRing{entities: entities}, nil
+}
+
+// KeyRing holds the public keys that signatures are trusted from.
+type KeyRing struct {
+	entities openpgp.EntityList
+}
+
+// Verify checks that the detached signature (armored or binary) was made over the message by one of the keys, returning
+// the fingerprint of the signing key.
+func (k KeyRing) Verify(message, signature []byte) (string, error) {
+	var signer *openpgp.Entity
+	var err error
+	if isArmored(signature) {
+		signer, err = openpgp.CheckArmoredDetachedSignature(k.entities, bytes.NewReader(message), bytes.NewReader(signature), nil)
+	} else {
+		signer, err = openpgp.CheckDetachedSignature(k.entities, bytes.NewReader(message), bytes.NewReader(signature), nil)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid gpg signature: %w", err)
+	}
+	return fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint), nil
+}
+
+// readKeys reads inline armored keys as-is, otherwise the value is read as a path.
+func readKeys(value string) (openpgp.EntityList, error) {
+	contents := []byte(value)
+	if !isArmored(contents) {
+		var err error
+		if contents, err = os.ReadFile(value); err != nil {
+			return nil, err
+		}
+	}
+	return parseKeys(contents)
+}
+
+func parseKeys(contents []byte) (openpgp.EntityList, error) {
+	if !isArmored(contents) {
+		return openpgp.ReadKeyRing(bytes.NewReader(contents))
+	}
+
+	// there may be several armored blocks (e.g. keys exported one at a time and concatenated)
+	var entities openpgp.EntityList
+	rest := contents
+	for {
+		start := bytes.Index(rest, []byte(armorPrefix))
+		if start < 0 {
+			break
+		}
+		block, err := armor.Decode(bytes.NewReader(rest[start:]))
+		if err != nil {
+			return nil, err
+		}
+		el, err := openpgp.ReadKeyRing(block.Body)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, el...)
+
+		end := bytes.Index(rest[start:], []byte("-----END PGP"))
+		if end < 0 {
+			break
+		}
+		rest = rest[start+end+len("-----END PGP"):]
+	}
+	return entities, nil
+}
+
+func isArmored(contents []byte) bool {
+	return strings.HasPrefix(strings.TrimSpace(string(contents)), armorPrefix)
+}
@@ -0,0 +1,161 @@
+package pgp
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEntity(t *testing.T, name string) *openpgp.Entity {
+	t.Helper()
+	e, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	require.NoError(t, err)
+	return e
+}
+
+func armoredPublicKey(t *testing.T, e *openpgp.Entity) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, e.Serialize(w))
+	require.NoError(t, w.Close())
+	return buf.String()
+}
+
+func detachSign(t *testing.T, e *openpgp.Entity, message []byte, armored bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if armored {
+		require.NoError(t, openpgp.ArmoredDetachSign(&buf, e, bytes.NewReader(message), nil))
+	} else {
+		require.NoError(t, openpgp.DetachSign(&buf, e, bytes.NewReader(message), nil))
+	}
+	return buf.Bytes()
+}
+
+func TestKeyRing_Verify(t *testing.T) {
+	maintainer := newEntity(t, "maintainer")
+	other := newEntity(t, "other")
+	message := []byte("abc123  tool_1.0.0_linux_amd64.tar.gz\n")
+
+	keyPath := filepath.Join(t.TempDir(), "maintainer.asc")
+	require.NoError(t, os.WriteFile(keyPath, []byte(armoredPublicKey(t, maintainer)), 0o600))
+
+	var binaryKeyring bytes.Buffer
+	require.NoError(t, other.Serialize(&binaryKeyring))
+	require.NoError(t, maintainer.Serialize(&binaryKeyring))
+	keyringPath := filepath.Join(t.TempDir(), "keyring.gpg")
+	require.NoError(t, os.WriteFile(keyringPath, binaryKeyring.Bytes(), 0o600))
+
+	tests := []struct {
+		name      string
+		config    Config
+		message   []byte
+		signature []byte
+		wantErr   require.ErrorAssertionFunc
+	}{
+		{
+			name:      "armored signature with an inline key",
+			config:    Config{Keys: []string{armoredPublicKey(t, maintainer)}},
+			signature: detachSign(t, maintainer, message, true),
+		},
+		{
+			name:      "binary signature with a key file",
+			config:    Config{Keys: []string{keyPath}},
+			signature: detachSign(t, maintainer, message, false),
+		},
+		{
+			name:      "binary keyring",
+			config:    Config{Keyring: keyringPath},
+			signature: detachSign(t, maintainer, message, true),
+		},
+		{
+			name:      "concatenated armored keys",
+			config:    Config{Keys: []string{armoredPublicKey(t, other) + armoredPublicKey(t, maintainer)}},
+			signature: detachSign(t, maintainer, message, true),
+		},
+		{
+			name:      "signed by an unknown key",
+			config:    Config{Keys: []string{keyPath}},
+			signature: detachSign(t, other, message, true),
+			wantErr:   require.Error,
+		},
+		{
+			name:      "signature over other contents",
+			config:    Config{Keys: []string{keyPath}},
+			message:   []byte("tampered"),
+			signature: detachSign(t, maintainer, message, true),
+			wantErr:   require.Error,
+		},
+		{
+			name:      "not a signature",
+			config:    Config{Keys: []string{keyPath}},
+			signature: []byte("MEUCIQDcosignsignature"),
+			wantErr:   require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			if tt.message == nil {
+				tt.message = message
+			}
+
+			keyring, err := tt.config.KeyRing()
+			require.NoError(t, err)
+
+			fingerprint, err := keyring.Verify(tt.message, tt.signature)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Len(t, fingerprint, 40)
+		})
+	}
+}
+
+func TestConfig_KeyRing(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:   "inline key",
+			config: Config{Keys: []string{armoredPublicKey(t, newEntity(t, "maintainer"))}},
+		},
+		{
+			name:    "missing key file",
+			config:  Config{Keys: []string{filepath.Join(t.TempDir(), "missing.asc")}},
+			wantErr: require.Error,
+		},
+		{
+			name:    "missing keyring",
+			config:  Config{Keyring: filepath.Join(t.TempDir(), "missing.gpg")},
+			wantErr: require.Error,
+		},
+		{
+			name:    "no keys",
+			config:  Config{},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			_, err := tt.config.KeyRing()
+			tt.wantErr(t, err)
+		})
+	}
+}
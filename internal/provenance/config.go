@@ -0,0 +1,136 @@
+package provenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/anchore/binny/internal/sigstore"
+)
+
+// Config describes the provenance that a release asset must have. The signature of the attestation is verified with
+// the same settings as cosign signatures.
+type Config struct {
+	// BuilderID is the expected builder (e.g. "https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml").
+	// Unless it includes a ref ("@..."), any ref of the builder is accepted.
+	BuilderID string `json:"builder-id" yaml:"builder-id,omitempty" mapstructure:"builder-id"`
+	// SourceRepo is the repository the asset must have been built from (e.g. "anchore/syft" or "github.com/anchore/syft").
+	SourceRepo string `json:"source-repo" yaml:"source-repo,omitempty" mapstructure:"source-repo"`
+	// SourceRef is a glob matching the git ref the asset must have been built from (defaults to the tag of the release).
+	SourceRef string `json:"source-ref" yaml:"source-ref,omitempty" mapstructure:"source-ref"`
+
+	sigstore.Config `json:"" yaml:",inline" mapstructure:",squash"`
+}
+
+// IsSet reports whether provenance verification is configured.
+func (c Config) IsSet() bool {
+	return c.BuilderID != "" || c.SourceRepo != "" || c.SourceRef != "" || c.Config.IsSet()
+}
+
+// Verifier checks provenance attestations against the configuration.
+type Verifier struct {
+	config Config
+	policy *sigstore.Policy
+}
+
+// Verifier validates the configuration and loads the keys and certificates for verifying attestation signatures.
+// Unless an identity is configured, keyless attestations must be signed by the builder (as is the case for the SLSA
+// GitHub generator).
+func (c Config) Verifier() (*Verifier, error) {
+	switch {
+	case c.BuilderID == "":
+		return nil, fmt.Errorf("provenance verification requires a builder-id")
+	case c.SourceRepo == "":
+		return nil, fmt.Errorf("provenance verification requires a source-repo")
+	case !c.Config.IsSet():
+		return nil, fmt.Errorf("provenance verification requires a key or trusted-root to verify attestations with")
+	}
+
+	if _, err := path.Match(c.SourceRef, ""); err != nil {
+		return nil, fmt.Errorf("invalid provenance source-ref: %w", err)
+	}
+
+	trust := c.Config
+	if trust.TrustedRoot != "" && trust.Identity == "" && trust.IdentityRegexp == "" {
+		builder, _, _ := strings.Cut(c.BuilderID, "@")
+		trust.IdentityRegexp = "^" + regexp.QuoteMeta(builder) + "(@|$)"
+	}
+
+	policy, err := trust.Policy()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Verifier{config: c, policy: policy}, nil
+}
+
+// Verify checks that one of the attestations is trusted provenance for the artifact, built by the expected builder from
+// the expected source. The tag is the release the artifact belongs to, which is the expected ref unless a source-ref
+// is configured.
+func (v Verifier) Verify(artifact []byte, attestations []sigstore.Attestation, tag string) error {
+	sum := sha256.Sum256(artifact)
+	digest := hex.EncodeToString(sum[:])
+
+	var errs []string
+	for _, a := range attestations {
+		payload, err := v.policy.VerifyAttestation(a)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		p, err := parseStatement(a.Envelope.PayloadType, payload)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		if !slices.Contains(p.Digests, digest) {
+			// the attestation may be for other artifacts (e.g. one attestation per asset)
+			errs = append(errs, "artifact digest is not a subject of the attestation")
+			continue
+		}
+
+		return v.check(*p, tag)
+	}
+
+	if len(errs) == 0 {
+		return fmt.Errorf("no attestations found")
+	}
+	return fmt.Errorf("no attestation is valid provenance for the artifact: %s", strings.Join(errs, "; "))
+}
+
+// check compares the builder and source claimed by the provenance against the configuration.
+func (v Verifier) check(p Provenance, tag string) error {
+	if !matchesBuilder(v.config.BuilderID, p.BuilderID) {
+		return fmt.Errorf("provenance builder %q does not match the expected builder %q", p.BuilderID, v.config.BuilderID)
+	}
+
+	if want := NormalizeRepo(v.config.SourceRepo); p.SourceRepo != want {
+		return fmt.Errorf("provenance source repo %q does not match the expected repo %q", p.SourceRepo, want)
+	}
+
+	if v.config.SourceRef != "" {
+		if ok, _ := path.Match(v.config.SourceRef, p.SourceRef); !ok {
+			return fmt.Errorf("provenance source ref %q does not match %q", p.SourceRef, v.config.SourceRef)
+		}
+		return nil
+	}
+
+	if want := "refs/tags/" + tag; p.SourceRef != want {
+		return fmt.Errorf("provenance source ref %q does not match the release tag %q", p.SourceRef, want)
+	}
+	return nil
+}
+
+func matchesBuilder(want, got string) bool {
+	if strings.Contains(want, "@") {
+		return got == want
+	}
+	builder, _, _ := strings.Cut(got, "@")
+	return builder == want
+}
@@ -0,0 +1,223 @@
+package provenance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/binny/internal/sigstore"
+)
+
+const (
+	// the fixtures in testdata are signed by a locally generated certificate authority and transparency log
+	testIssuer          = "https://token.actions.githubusercontent.com"
+	slsaGeneratorID     = "https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator_generic_slsa3.yml"
+	githubHostedBuilder = "https://github.com/actions/runner/github-hosted"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	artifact := readFixture(t, "artifact")
+	trust := sigstore.Config{
+		TrustedRoot: filepath.Join("testdata", "trusted-root.pem"),
+		Issuer:      testIssuer,
+	}
+
+	tests := []struct {
+		name         string
+		config       Config
+		attestations string
+		artifact     []byte
+		tag          string
+		wantErr      require.ErrorAssertionFunc
+	}{
+		{
+			name:         "slsa generator provenance",
+			config:       Config{BuilderID: slsaGeneratorID, SourceRepo: "anchore/syft", Config: trust},
+			attestations: "slsa-v0.2.intoto.jsonl",
+		},
+		{
+			name:         "builder with the exact ref",
+			config:       Config{BuilderID: slsaGeneratorID + "@refs/tags/v1.9.0", SourceRepo: "anchore/syft", Config: trust},
+			attestations: "slsa-v0.2.intoto.jsonl",
+		},
+		{
+			name:         "github attestation with a transparency log entry",
+			attestations: "github-v1.attestation.json",
+			config: Config{
+				BuilderID:  githubHostedBuilder,
+				SourceRepo: "https://github.com/anchore/syft",
+				Config: sigstore.Config{
+					TrustedRoot:        trust.TrustedRoot,
+					Issuer:             testIssuer,
+					IdentityRegexp:     "^https://github.com/anchore/syft/",
+					TransparencyLogKey: filepath.Join("testdata", "tlog.pub"),
+				},
+			},
+		},
+		{
+			name:         "github attestation signed by an unexpected identity",
+			attestations: "github-v1.attestation.json",
+			config: Config{
+				BuilderID:  githubHostedBuilder,
+				SourceRepo: "anchore/syft",
+				Config: sigstore.Config{
+					TrustedRoot:    trust.TrustedRoot,
+					Issuer:         testIssuer,
+					IdentityRegexp: "^https://github.com/anchore/grype/",
+				},
+			},
+			wantErr: require.Error,
+		},
+		{
+			name:         "source ref glob",
+			config:       Config{BuilderID: slsaGeneratorID, SourceRepo: "anchore/syft", SourceRef: "refs/tags/v1.*", Config: trust},
+			attestations: "slsa-v0.2.intoto.jsonl",
+			tag:          "latest",
+		},
+		{
+			name:         "source ref defaults to the release tag",
+			config:       Config{BuilderID: slsaGeneratorID, SourceRepo: "anchore/syft", Config: trust},
+			attestations: "slsa-v0.2.intoto.jsonl",
+			tag:          "v1.0.1",
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, `does not match the release tag "refs/tags/v1.0.1"`)
+			},
+		},
+		{
+			name:         "wrong builder",
+			config:       Config{BuilderID: githubHostedBuilder, SourceRepo: "anchore/syft", Config: trust},
+			attestations: "github-v1.attestation.json",
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				// without an identity, the attestation must be signed by the builder
+				require.ErrorContains(t, err, "does not match the expected identity")
+			},
+		},
+		{
+			name:         "wrong source repo",
+			config:       Config{BuilderID: slsaGeneratorID, SourceRepo: "anchore/grype", Config: trust},
+			attestations: "slsa-v0.2.intoto.jsonl",
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, `source repo "github.com/anchore/syft" does not match`)
+			},
+		},
+		{
+			name:         "artifact is not a subject",
+			config:       Config{BuilderID: slsaGeneratorID, SourceRepo: "anchore/syft", Config: trust},
+			attestations: "slsa-v0.2.intoto.jsonl",
+			artifact:     []byte("a different artifact"),
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, "not a subject of the attestation")
+			},
+		},
+		{
+			name:         "signed by someone other than the builder",
+			config:       Config{BuilderID: slsaGeneratorID, SourceRepo: "anchore/syft", Config: trust},
+			attestations: "other-signer.intoto.jsonl",
+			wantErr:      require.Error,
+		},
+		{
+			name:         "tampered payload",
+			config:       Config{BuilderID: slsaGeneratorID, SourceRepo: "anchore/syft", Config: trust},
+			attestations: "tampered.intoto.jsonl",
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, "signature does not match")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			if tt.artifact == nil {
+				tt.artifact = artifact
+			}
+			if tt.tag == "" {
+				tt.tag = "v1.0.0"
+			}
+
+			attestations, err := sigstore.ParseAttestations(readFixture(t, tt.attestations))
+			require.NoError(t, err)
+
+			v, err := tt.config.Verifier()
+			require.NoError(t, err)
+
+			tt.wantErr(t, v.Verify(tt.artifact, attestations, tt.tag))
+		})
+	}
+}
+
+func TestConfig_Verifier(t *testing.T) {
+	trust := sigstore.Config{
+		TrustedRoot: filepath.Join("testdata", "trusted-root.pem"),
+		Issuer:      testIssuer,
+	}
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:   "builder identity is derived",
+			config: Config{BuilderID: slsaGeneratorID, SourceRepo: "anchore/syft", Config: trust},
+		},
+		{
+			name:    "missing builder",
+			config:  Config{SourceRepo: "anchore/syft", Config: trust},
+			wantErr: require.Error,
+		},
+		{
+			name:    "missing source repo",
+			config:  Config{BuilderID: slsaGeneratorID, Config: trust},
+			wantErr: require.Error,
+		},
+		{
+			name:    "missing trust",
+			config:  Config{BuilderID: slsaGeneratorID, SourceRepo: "anchore/syft"},
+			wantErr: require.Error,
+		},
+		{
+			name:    "invalid source ref",
+			config:  Config{BuilderID: slsaGeneratorID, SourceRepo: "anchore/syft", SourceRef: "refs/tags/[", Config: trust},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			_, err := tt.config.Verifier()
+			tt.wantErr(t, err)
+		})
+	}
+}
+
+func TestNormalizeRepo(t *testing.T) {
+	tests := []struct {
+		repo string
+		want string
+	}{
+		{repo: "anchore/syft", want: "github.com/anchore/syft"},
+		{repo: "github.com/anchore/syft", want: "github.com/anchore/syft"},
+		{repo: "https://github.com/Anchore/Syft", want: "github.com/anchore/syft"},
+		{repo: "git+https://github.com/anchore/syft.git", want: "github.com/anchore/syft"},
+		{repo: "https://gitlab.com/group/sub/project/", want: "gitlab.com/group/sub/project"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeRepo(tt.repo))
+		})
+	}
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	contents, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return contents
+}
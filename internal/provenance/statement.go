@@ -0,0 +1,164 @@
+package provenance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	inTotoPayloadType = "application/vnd.in-toto+json"
+
+	slsaV02PredicateType = "https://slsa.dev/provenance/v0.2"
+	slsaV1PredicateType  = "https://slsa.dev/provenance/v1"
+)
+
+// statement is the subset of an in-toto statement holding SLSA provenance that is needed for verification.
+type statement struct {
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Subject       []subject       `json:"subject"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+type subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// slsaV02Predicate is the subset of a SLSA v0.2 provenance predicate describing the builder and source.
+type slsaV02Predicate struct {
+	Builder struct {
+		ID string `json:"id"`
+	} `json:"builder"`
+	Invocation struct {
+		ConfigSource struct {
+			URI string `json:"uri"`
+		} `json:"configSource"`
+	} `json:"invocation"`
+}
+
+// slsaV1Predicate is the subset of a SLSA v1 provenance predicate describing the builder and source, as written by
+// both the SLSA GitHub generator and GitHub artifact attestations.
+type slsaV1Predicate struct {
+	BuildDefinition struct {
+		ExternalParameters struct {
+			Workflow struct {
+				Repository string `json:"repository"`
+				Ref        string `json:"ref"`
+			} `json:"workflow"`
+		} `json:"externalParameters"`
+		ResolvedDependencies []struct {
+			URI string `json:"uri"`
+		} `json:"resolvedDependencies"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+	} `json:"runDetails"`
+}
+
+// Provenance is what a provenance statement claims about how its subjects were built.
+type Provenance struct {
+	// BuilderID identifies the build platform (e.g. a reusable workflow of the SLSA GitHub generator).
+	BuilderID string
+	// SourceRepo is the normalized repository the subjects were built from (e.g. "github.com/anchore/syft").
+	SourceRepo string
+	// SourceRef is the git ref the subjects were built from (e.g. "refs/tags/v1.0.0").
+	SourceRef string
+	// Digests are the SHA-256 digests (hex encoded) of the subjects.
+	Digests []string
+}
+
+func parseStatement(payloadType string, payload []byte) (*Provenance, error) {
+	if payloadType != inTotoPayloadType {
+		return nil, fmt.Errorf("unsupported payload type %q", payloadType)
+	}
+
+	var s statement
+	if err := json.Unmarshal(payload, &s); err != nil {
+		return nil, fmt.Errorf("unable to decode in-toto statement: %w", err)
+	}
+
+	var p *Provenance
+	var err error
+	switch s.PredicateType {
+	case slsaV02PredicateType:
+		p, err = parseSLSAV02(s.Predicate)
+	case slsaV1PredicateType:
+		p, err = parseSLSAV1(s.Predicate)
+	default:
+		return nil, fmt.Errorf("unsupported predicate type %q", s.PredicateType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range s.Subject {
+		if d, ok := sub.Digest["sha256"]; ok {
+			p.Digests = append(p.Digests, strings.ToLower(d))
+		}
+	}
+	return p, nil
+}
+
+func parseSLSAV02(raw json.RawMessage) (*Provenance, error) {
+	var pred slsaV02Predicate
+	if err := json.Unmarshal(raw, &pred); err != nil {
+		return nil, fmt.Errorf("unable to decode SLSA v0.2 predicate: %w", err)
+	}
+
+	repo, ref := splitSourceURI(pred.Invocation.ConfigSource.URI)
+	return &Provenance{
+		BuilderID:  pred.Builder.ID,
+		SourceRepo: repo,
+		SourceRef:  ref,
+	}, nil
+}
+
+func parseSLSAV1(raw json.RawMessage) (*Provenance, error) {
+	var pred slsaV1Predicate
+	if err := json.Unmarshal(raw, &pred); err != nil {
+		return nil, fmt.Errorf("unable to decode SLSA v1 predicate: %w", err)
+	}
+
+	p := &Provenance{BuilderID: pred.RunDetails.Builder.ID}
+
+	workflow := pred.BuildDefinition.ExternalParameters.Workflow
+	if workflow.Repository != "" {
+		p.SourceRepo = NormalizeRepo(workflow.Repository)
+		p.SourceRef = workflow.Ref
+		return p, nil
+	}
+
+	// fall back to the first resolved dependency, which is the source for the SLSA GitHub generator
+	for _, dep := range pred.BuildDefinition.ResolvedDependencies {
+		if dep.URI != "" {
+			p.SourceRepo, p.SourceRef = splitSourceURI(dep.URI)
+			break
+		}
+	}
+	return p, nil
+}
+
+// splitSourceURI splits a source URI (e.g. "git+https://github.com/anchore/syft@refs/tags/v1.0.0") into the
+// normalized repository and the ref.
+func splitSourceURI(uri string) (string, string) {
+	repo, ref, _ := strings.Cut(uri, "@")
+	return NormalizeRepo(repo), ref
+}
+
+// NormalizeRepo returns the repository as host and path (e.g. "github.com/anchore/syft"). A bare "owner/repo" is taken
+// to be on github.com.
+func NormalizeRepo(repo string) string {
+	repo = strings.TrimPrefix(strings.TrimSpace(repo), "git+")
+	if _, rest, ok := strings.Cut(repo, "://"); ok {
+		repo = rest
+	}
+	repo = strings.TrimSuffix(strings.TrimSuffix(repo, "/"), ".git")
+	if strings.Count(repo, "/") == 1 {
+		repo = "github.com/" + repo
+	}
+	return strings.ToLower(repo)
+}
@@ -0,0 +1,40 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Config describes the restrictions of a sandboxed process. Reading files is never restricted.
+type Config struct {
+	// Writable are the paths (directories include everything beneath them) that the process can write to.
+	Writable []string
+	// DenyNetwork prevents the process from using the network (only TCP when the kernel cannot isolate the network).
+	DenyNetwork bool
+}
+
+// DefaultWritable returns the paths that sandboxed processes can always write to: the working tree (the root of
+// the git repository containing the working directory, or the working directory itself), temp directories, and
+// devices (e.g. /dev/null and terminals).
+func DefaultWritable() ([]string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return []string{workTree(wd), os.TempDir(), "/tmp", "/var/tmp", "/dev"}, nil
+}
+
+// workTree returns the root of the git repository that contains the directory, or the directory when it is not
+// within a repository.
+func workTree(dir string) string {
+	for current := dir; ; {
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return current
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return dir
+		}
+		current = parent
+	}
+}
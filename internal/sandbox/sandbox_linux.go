@@ -0,0 +1,180 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/anchore/binny/internal/log"
+)
+
+// landlock write access rights by the ABI version that introduced them (reading and executing are not restricted)
+const (
+	landlockWriteV1 = unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+	landlockWriteV2 = landlockWriteV1 | unix.LANDLOCK_ACCESS_FS_REFER
+	landlockWriteV3 = landlockWriteV2 | unix.LANDLOCK_ACCESS_FS_TRUNCATE
+
+	// the access rights that apply to files (as opposed to directories)
+	landlockFileAccess = unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE
+
+	landlockNetABI = 4
+)
+
+// Start calls start (which must start cmd on the calling goroutine, such as exec.Cmd.Start) with the restrictions
+// applied to the started process. Restrictions that the kernel does not support are skipped with a warning. The
+// filesystem restrictions are applied with Landlock to a dedicated OS thread that the process is forked from, so binny
+// itself is not restricted. Denying the network starts the process in its own network namespace (within a user
+// namespace, so no privileges are needed), which has no interfaces to reach anything with.
+func Start(cfg Config, cmd *exec.Cmd, start func() error) error {
+	isolated := false
+	if cfg.DenyNetwork {
+		if err := isolateNetwork(cmd); err != nil {
+			log.WithFields("error", err).Warn("unable to isolate the network, only TCP connections are denied")
+		} else {
+			isolated = true
+		}
+	}
+	return startRestricted(cfg, isolated, start)
+}
+
+// startRestricted calls start on a dedicated OS thread with the Landlock restrictions applied.
+func startRestricted(cfg Config, networkIsolated bool, start func() error) error {
+	errs := make(chan error, 1)
+	go func() {
+		// the thread is never unlocked, so it exits (along with its restrictions) when this goroutine returns
+		runtime.LockOSThread()
+
+		if err := restrictThread(cfg, networkIsolated); err != nil {
+			errs <- err
+			return
+		}
+		errs <- start()
+	}()
+	return <-errs
+}
+
+// networkProbePath never exists, so starting it fails only once the namespaces were created.
+const networkProbePath = "/proc/self/binny-sandbox-probe"
+
+// isolateNetwork configures the command to start in new user and network namespaces, after checking that the kernel
+// allows creating them (e.g. unprivileged user namespaces may be disabled). The user namespace has no ID mappings
+// (which the restricted thread could not write), so the process sees itself as the overflow user (nobody), while
+// files are still accessed and created as the user running binny.
+func isolateNetwork(cmd *exec.Cmd) error {
+	const flags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET
+
+	_, err := os.StartProcess(networkProbePath, []string{networkProbePath}, &os.ProcAttr{Sys: &syscall.SysProcAttr{Cloneflags: flags}})
+	if !errors.Is(err, unix.ENOENT) {
+		return fmt.Errorf("unable to create a network namespace: %w", err)
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Cloneflags |= flags
+	return nil
+}
+
+// restrictThread applies the Landlock restrictions to the calling thread. Without an isolated network, denying the
+// network falls back to denying TCP connections with Landlock.
+func restrictThread(cfg Config, networkIsolated bool) error {
+	abi, err := landlockABI()
+	if err != nil {
+		log.WithFields("error", err).Warn("the kernel does not support Landlock, running without the sandbox")
+		return nil
+	}
+
+	attr := unix.LandlockRulesetAttr{Access_fs: landlockWriteAccess(abi)}
+	if cfg.DenyNetwork && !networkIsolated {
+		if abi >= landlockNetABI {
+			attr.Access_net = unix.LANDLOCK_ACCESS_NET_BIND_TCP | unix.LANDLOCK_ACCESS_NET_CONNECT_TCP
+		} else {
+			log.WithFields("abi", abi).Warn("the kernel does not support restricting the network with Landlock, running with network access")
+		}
+	}
+
+	ruleset, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("unable to create sandbox ruleset: %w", errno)
+	}
+	defer unix.Close(int(ruleset))
+
+	for _, path := range cfg.Writable {
+		if err := allowWrite(int(ruleset), path, attr.Access_fs); err != nil {
+			return err
+		}
+	}
+
+	// required to restrict an unprivileged thread (and means the process cannot gain privileges, e.g. with setuid)
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("unable to set no_new_privs for the sandbox: %w", err)
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, ruleset, 0, 0); errno != 0 {
+		return fmt.Errorf("unable to apply the sandbox: %w", errno)
+	}
+	return nil
+}
+
+// landlockABI returns the Landlock ABI version that the kernel supports.
+func landlockABI() (int, error) {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(abi), nil
+}
+
+func landlockWriteAccess(abi int) uint64 {
+	switch {
+	case abi >= 3:
+		return landlockWriteV3
+	case abi == 2:
+		return landlockWriteV2
+	}
+	return landlockWriteV1
+}
+
+// allowWrite adds a rule that allows writing to the path (beneath it for directories). Paths that do not exist are
+// skipped.
+func allowWrite(ruleset int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ENOENT) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to open %q for the sandbox: %w", path, err)
+	}
+	defer unix.Close(fd)
+
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return fmt.Errorf("unable to stat %q for the sandbox: %w", path, err)
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= landlockFileAccess
+	}
+
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	if _, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset), unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&rule)), 0, 0, 0); errno != 0 {
+		return fmt.Errorf("unable to allow writing to %q in the sandbox: %w", path, os.NewSyscallError("landlock_add_rule", errno))
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requireLandlock(t *testing.T, minABI int) {
+	t.Helper()
+	abi, err := landlockABI()
+	if err != nil || abi < minABI {
+		t.Skipf("the kernel does not support Landlock ABI %d", minABI)
+	}
+}
+
+func runSandboxed(t *testing.T, cfg Config, script string) error {
+	t.Helper()
+	c := exec.Command("/bin/sh", "-c", script)
+	require.NoError(t, Start(cfg, c, c.Start))
+	return c.Wait()
+}
+
+func TestStart_filesystem(t *testing.T) {
+	requireLandlock(t, 1)
+
+	writable := t.TempDir()
+	readOnly := t.TempDir()
+	existing := filepath.Join(readOnly, "existing")
+	require.NoError(t, os.WriteFile(existing, []byte("original"), 0o600))
+	devNull := "/dev/null"
+
+	cfg := Config{Writable: []string{writable, devNull, filepath.Join(readOnly, "missing")}}
+
+	require.NoError(t, runSandboxed(t, cfg, "echo ok > "+filepath.Join(writable, "file")+" && echo ok > /dev/null"))
+	assert.FileExists(t, filepath.Join(writable, "file"))
+
+	assert.Error(t, runSandboxed(t, cfg, "echo bad > "+filepath.Join(readOnly, "file")))
+	assert.NoFileExists(t, filepath.Join(readOnly, "file"))
+
+	assert.Error(t, runSandboxed(t, cfg, "echo bad > "+existing))
+	assert.Error(t, runSandboxed(t, cfg, "rm "+existing))
+	contents, err := os.ReadFile(existing)
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(contents))
+
+	// reading is not restricted
+	require.NoError(t, runSandboxed(t, cfg, "cat "+existing+" > /dev/null"))
+
+	// binny itself is not restricted
+	require.NoError(t, os.WriteFile(filepath.Join(readOnly, "file"), []byte("ok"), 0o600))
+}
+
+func requireNetworkNamespace(t *testing.T) {
+	t.Helper()
+	if err := isolateNetwork(&exec.Cmd{}); err != nil {
+		t.Skipf("the kernel does not allow isolating the network: %v", err)
+	}
+}
+
+// dialer starts the test binary to dial the address (see TestHelperDial) with the given way of starting it.
+func dialer(network, addr string) func(start func(c *exec.Cmd) error) error {
+	return func(start func(c *exec.Cmd) error) error {
+		c := exec.Command(os.Args[0], "-test.run=^TestHelperDial$")
+		c.Env = append(os.Environ(), "BINNY_TEST_DIAL_NETWORK="+network, "BINNY_TEST_DIAL="+addr)
+		if err := start(c); err != nil {
+			return err
+		}
+		return c.Wait()
+	}
+}
+
+func listenTCP(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestStart_network(t *testing.T) {
+	requireNetworkNamespace(t)
+
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer udp.Close()
+
+	// the output of the process is discarded to /dev/null
+	writable := []string{"/dev/null"}
+	require.NoError(t, runSandboxed(t, Config{Writable: writable, DenyNetwork: true}, "echo ok > /dev/null"))
+
+	for _, d := range []func(func(*exec.Cmd) error) error{dialer("tcp", listenTCP(t)), dialer("udp", udp.LocalAddr().String())} {
+		require.NoError(t, d(func(c *exec.Cmd) error { return Start(Config{Writable: writable}, c, c.Start) }))
+		assert.Error(t, d(func(c *exec.Cmd) error { return Start(Config{Writable: writable, DenyNetwork: true}, c, c.Start) }))
+	}
+}
+
+func TestStart_networkLandlockFallback(t *testing.T) {
+	requireLandlock(t, landlockNetABI)
+
+	dial := dialer("tcp", listenTCP(t))
+	cfg := Config{Writable: []string{"/dev/null"}, DenyNetwork: true}
+
+	// without a network namespace, TCP connections are still denied
+	assert.Error(t, dial(func(c *exec.Cmd) error { return startRestricted(cfg, false, c.Start) }))
+
+	// but Landlock does not restrict UDP
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer udp.Close()
+	require.NoError(t, dialer("udp", udp.LocalAddr().String())(func(c *exec.Cmd) error { return startRestricted(cfg, false, c.Start) }))
+}
+
+func TestHelperDial(t *testing.T) {
+	addr := os.Getenv("BINNY_TEST_DIAL")
+	if addr == "" {
+		t.Skip("only run as a sandboxed process")
+	}
+	conn, err := net.Dial(os.Getenv("BINNY_TEST_DIAL_NETWORK"), addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+}
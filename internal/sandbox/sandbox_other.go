@@ -0,0 +1,16 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"os/exec"
+	"runtime"
+
+	"github.com/anchore/binny/internal/log"
+)
+
+// Start calls start (which starts cmd) without any restrictions, since sandboxing is only supported on linux.
+func Start(_ Config, _ *exec.Cmd, start func() error) error {
+	log.WithFields("os", runtime.GOOS).Warn("sandboxing is only supported on linux, running without the sandbox")
+	return start()
+}
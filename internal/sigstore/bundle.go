@@ -0,0 +1,192 @@
+package sigstore
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const bundleMediaTypePrefix = "application/vnd.dev.sigstore.bundle"
+
+// ParseBundle decodes either a sigstore bundle (e.g. "*.sigstore.json") or the legacy bundle written by
+// "cosign sign-blob --bundle".
+func ParseBundle(contents []byte) (*Material, error) {
+	var probe struct {
+		MediaType       string `json:"mediaType"`
+		Base64Signature string `json:"base64Signature"`
+	}
+	if err := json.Unmarshal(contents, &probe); err != nil {
+		return nil, fmt.Errorf("unable to decode bundle: %w", err)
+	}
+
+	switch {
+	case strings.HasPrefix(probe.MediaType, bundleMediaTypePrefix):
+		return parseSigstoreBundle(contents)
+	case probe.Base64Signature != "":
+		return parseCosignBundle(contents)
+	}
+	return nil, fmt.Errorf("unknown bundle format")
+}
+
+// cosignBundle is the bundle format written by "cosign sign-blob --bundle".
+type cosignBundle struct {
+	Base64Signature string `json:"base64Signature"`
+	Cert            string `json:"cert"`
+	RekorBundle     *struct {
+		SignedEntryTimestamp []byte `json:"SignedEntryTimestamp"`
+		Payload              struct {
+			Body           string `json:"body"`
+			IntegratedTime int64  `json:"integratedTime"`
+			LogIndex       int64  `json:"logIndex"`
+			LogID          string `json:"logID"`
+		} `json:"Payload"`
+	} `json:"rekorBundle"`
+}
+
+func parseCosignBundle(contents []byte) (*Material, error) {
+	var b cosignBundle
+	if err := json.Unmarshal(contents, &b); err != nil {
+		return nil, fmt.Errorf("unable to decode cosign bundle: %w", err)
+	}
+
+	sig, err := ParseSignature([]byte(b.Base64Signature))
+	if err != nil {
+		return nil, err
+	}
+	m := &Material{Signature: sig}
+
+	if b.Cert != "" {
+		m.Certificates, err = ParseCertificates([]byte(b.Cert))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if b.RekorBundle != nil {
+		body, err := base64.StdEncoding.DecodeString(b.RekorBundle.Payload.Body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode transparency log entry body: %w", err)
+		}
+		m.Entries = append(m.Entries, LogEntry{
+			Body:                 body,
+			IntegratedTime:       b.RekorBundle.Payload.IntegratedTime,
+			LogIndex:             b.RekorBundle.Payload.LogIndex,
+			LogID:                b.RekorBundle.Payload.LogID,
+			SignedEntryTimestamp: b.RekorBundle.SignedEntryTimestamp,
+		})
+	}
+
+	return m, nil
+}
+
+// sigstoreBundle is the subset of the sigstore bundle format (v0.1 through v0.3) needed for offline verification.
+type sigstoreBundle struct {
+	VerificationMaterial struct {
+		Certificate *struct {
+			RawBytes []byte `json:"rawBytes"`
+		} `json:"certificate"`
+		X509CertificateChain *struct {
+			Certificates []struct {
+				RawBytes []byte `json:"rawBytes"`
+			} `json:"certificates"`
+		} `json:"x509CertificateChain"`
+		TlogEntries []struct {
+			LogIndex string `json:"logIndex"`
+			LogID    struct {
+				KeyID []byte `json:"keyId"`
+			} `json:"logId"`
+			IntegratedTime   string `json:"integratedTime"`
+			InclusionPromise *struct {
+				SignedEntryTimestamp []byte `json:"signedEntryTimestamp"`
+			} `json:"inclusionPromise"`
+			CanonicalizedBody []byte `json:"canonicalizedBody"`
+		} `json:"tlogEntries"`
+	} `json:"verificationMaterial"`
+	MessageSignature *struct {
+		MessageDigest struct {
+			Algorithm string `json:"algorithm"`
+			Digest    []byte `json:"digest"`
+		} `json:"messageDigest"`
+		Signature []byte `json:"signature"`
+	} `json:"messageSignature"`
+	DSSEEnvelope *Envelope `json:"dsseEnvelope"`
+}
+
+func parseSigstoreBundle(contents []byte) (*Material, error) {
+	var b sigstoreBundle
+	if err := json.Unmarshal(contents, &b); err != nil {
+		return nil, fmt.Errorf("unable to decode sigstore bundle: %w", err)
+	}
+
+	if b.MessageSignature == nil {
+		return nil, fmt.Errorf("sigstore bundle does not contain a message signature")
+	}
+	if alg := b.MessageSignature.MessageDigest.Algorithm; alg != "" && alg != "SHA2_256" {
+		return nil, fmt.Errorf("unsupported message digest algorithm %q", alg)
+	}
+
+	m := &Material{
+		Signature: b.MessageSignature.Signature,
+		Digest:    b.MessageSignature.MessageDigest.Digest,
+	}
+
+	certs, err := bundleCertificates(b)
+	if err != nil {
+		return nil, err
+	}
+	m.Certificates = certs
+
+	if m.Entries, err = bundleEntries(b); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func bundleEntries(b sigstoreBundle) ([]LogEntry, error) {
+	var entries []LogEntry
+	for _, e := range b.VerificationMaterial.TlogEntries {
+		entry := LogEntry{
+			Body:  e.CanonicalizedBody,
+			LogID: hex.EncodeToString(e.LogID.KeyID),
+		}
+		var err error
+		if entry.IntegratedTime, err = strconv.ParseInt(e.IntegratedTime, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid transparency log integrated time %q: %w", e.IntegratedTime, err)
+		}
+		if entry.LogIndex, err = strconv.ParseInt(e.LogIndex, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid transparency log index %q: %w", e.LogIndex, err)
+		}
+		if e.InclusionPromise != nil {
+			entry.SignedEntryTimestamp = e.InclusionPromise.SignedEntryTimestamp
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func bundleCertificates(b sigstoreBundle) ([]*x509.Certificate, error) {
+	var raw [][]byte
+	switch vm := b.VerificationMaterial; {
+	case vm.Certificate != nil:
+		raw = append(raw, vm.Certificate.RawBytes)
+	case vm.X509CertificateChain != nil:
+		for _, c := range vm.X509CertificateChain.Certificates {
+			raw = append(raw, c.RawBytes)
+		}
+	}
+
+	var certs []*x509.Certificate
+	for _, r := range raw {
+		cert, err := x509.ParseCertificate(r)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse bundle certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
@@ -0,0 +1,148 @@
+package sigstore
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Config describes how cosign signatures are verified. Values that refer to keys or certificates are either a path to
+// a PEM file or inline PEM contents.
+type Config struct {
+	// Key is the public key for key-based signatures (e.g. "cosign.pub").
+	Key string `json:"key" yaml:"key,omitempty" mapstructure:"key"`
+	// Identity is the exact certificate identity for keyless signatures (e.g. a GitHub workflow URI).
+	Identity string `json:"identity" yaml:"identity,omitempty" mapstructure:"identity"`
+	// IdentityRegexp matches the certificate identity for keyless signatures.
+	IdentityRegexp string `json:"identity-regexp" yaml:"identity-regexp,omitempty" mapstructure:"identity-regexp"`
+	// Issuer is the OIDC issuer for keyless signatures (e.g. "https://token.actions.githubusercontent.com").
+	Issuer string `json:"issuer" yaml:"issuer,omitempty" mapstructure:"issuer"`
+	// TrustedRoot holds the certificate authorities that keyless signing certificates must chain to (e.g. the fulcio
+	// root and intermediate certificates).
+	TrustedRoot string `json:"trusted-root" yaml:"trusted-root,omitempty" mapstructure:"trusted-root"`
+	// TransparencyLogKey is the public key of the transparency log (e.g. the rekor public key), which is used to
+	// verify when a keyless signature was made.
+	TransparencyLogKey string `json:"tlog-key" yaml:"tlog-key,omitempty" mapstructure:"tlog-key"`
+}
+
+// IsSet reports whether any verification is configured. An incomplete configuration (e.g. an identity without a
+// trusted-root) is still set, so that Policy reports what is missing rather than verification being skipped.
+func (c Config) IsSet() bool {
+	return c.Key != "" || c.Identity != "" || c.IdentityRegexp != "" || c.Issuer != "" || c.TrustedRoot != "" || c.TransparencyLogKey != ""
+}
+
+// Policy loads the configured keys and certificates.
+func (c Config) Policy() (*Policy, error) {
+	p := &Policy{
+		Identity: c.Identity,
+		Issuer:   c.Issuer,
+	}
+
+	if c.Key != "" {
+		keys, err := loadPublicKeys(c.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cosign key: %w", err)
+		}
+		p.PublicKeys = keys
+	}
+
+	if c.IdentityRegexp != "" {
+		re, err := regexp.Compile(c.IdentityRegexp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cosign identity-regexp: %w", err)
+		}
+		p.IdentityRegexp = re
+	}
+
+	keyless := c.TrustedRoot != "" || c.Identity != "" || c.IdentityRegexp != "" || c.Issuer != "" || c.TransparencyLogKey != ""
+	if !keyless {
+		return p, nil
+	}
+
+	switch {
+	case c.TrustedRoot == "":
+		return nil, fmt.Errorf("cosign identity, issuer, and tlog-key require a trusted-root to verify certificates with")
+	case c.Identity == "" && c.IdentityRegexp == "":
+		return nil, fmt.Errorf("cosign trusted-root requires an identity or identity-regexp")
+	case c.Issuer == "":
+		return nil, fmt.Errorf("cosign trusted-root requires an issuer")
+	}
+
+	if err := p.loadTrustedRoot(c.TrustedRoot); err != nil {
+		return nil, fmt.Errorf("invalid cosign trusted-root: %w", err)
+	}
+
+	if c.TransparencyLogKey != "" {
+		keys, err := loadPublicKeys(c.TransparencyLogKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cosign tlog-key: %w", err)
+		}
+		p.LogKeys = keys
+	}
+
+	return p, nil
+}
+
+// loadTrustedRoot adds self-signed certificates as roots and all others as intermediates.
+func (p *Policy) loadTrustedRoot(value string) error {
+	contents, err := readPEM(value)
+	if err != nil {
+		return err
+	}
+	certs, err := ParseCertificates(contents)
+	if err != nil {
+		return err
+	}
+
+	p.Roots = x509.NewCertPool()
+	p.Intermediates = x509.NewCertPool()
+	for _, cert := range certs {
+		if cert.CheckSignatureFrom(cert) == nil {
+			p.Roots.AddCert(cert)
+		} else {
+			p.Intermediates.AddCert(cert)
+		}
+	}
+	return nil
+}
+
+func loadPublicKeys(value string) ([]crypto.PublicKey, error) {
+	contents, err := readPEM(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, contents = pem.Decode(contents)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse public key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public keys found")
+	}
+	return keys, nil
+}
+
+// readPEM returns inline PEM contents as-is, otherwise the value is read as a path.
+func readPEM(value string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
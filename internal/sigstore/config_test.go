@@ -0,0 +1,143 @@
+package sigstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Policy(t *testing.T) {
+	authority := newTestAuthority(t)
+	key := newKey(t)
+
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "cosign.pub")
+	require.NoError(t, os.WriteFile(keyPath, []byte(publicKeyPEM(t, &key.PublicKey)), 0o600))
+	rootPath := filepath.Join(dir, "root.pem")
+	require.NoError(t, os.WriteFile(rootPath, []byte(certificatePEM(authority.root)), 0o600))
+
+	tests := []struct {
+		name    string
+		config  Config
+		assert  func(t *testing.T, p *Policy)
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:   "key from a path",
+			config: Config{Key: keyPath},
+			assert: func(t *testing.T, p *Policy) {
+				assert.Len(t, p.PublicKeys, 1)
+				assert.Nil(t, p.Roots)
+			},
+		},
+		{
+			name:   "inline key",
+			config: Config{Key: publicKeyPEM(t, &key.PublicKey)},
+			assert: func(t *testing.T, p *Policy) {
+				assert.Len(t, p.PublicKeys, 1)
+			},
+		},
+		{
+			name: "keyless",
+			config: Config{
+				TrustedRoot:        rootPath,
+				IdentityRegexp:     `^https://github\.com/anchore/`,
+				Issuer:             testIssuer,
+				TransparencyLogKey: publicKeyPEM(t, &authority.logKey.PublicKey),
+			},
+			assert: func(t *testing.T, p *Policy) {
+				assert.NotNil(t, p.Roots)
+				assert.NotNil(t, p.IdentityRegexp)
+				assert.Len(t, p.LogKeys, 1)
+			},
+		},
+		{
+			name:    "missing key file",
+			config:  Config{Key: filepath.Join(dir, "missing.pub")},
+			wantErr: require.Error,
+		},
+		{
+			name:    "identity without a trusted root",
+			config:  Config{Identity: testIdentity, Issuer: testIssuer},
+			wantErr: require.Error,
+		},
+		{
+			name:    "transparency log key without a trusted root",
+			config:  Config{Key: keyPath, TransparencyLogKey: publicKeyPEM(t, &authority.logKey.PublicKey)},
+			wantErr: require.Error,
+		},
+		{
+			name:    "trusted root without an identity",
+			config:  Config{TrustedRoot: rootPath, Issuer: testIssuer},
+			wantErr: require.Error,
+		},
+		{
+			name:    "trusted root without an issuer",
+			config:  Config{TrustedRoot: rootPath, Identity: testIdentity},
+			wantErr: require.Error,
+		},
+		{
+			name:    "invalid identity regexp",
+			config:  Config{TrustedRoot: rootPath, IdentityRegexp: `(`, Issuer: testIssuer},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			p, err := tt.config.Policy()
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			tt.assert(t, p)
+		})
+	}
+}
+
+func TestConfig_IsSet(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "key",
+			config: Config{Key: "cosign.pub"},
+			want:   true,
+		},
+		{
+			name:   "identity without a trusted root",
+			config: Config{Identity: testIdentity},
+			want:   true,
+		},
+		{
+			name:   "identity regexp without a trusted root",
+			config: Config{IdentityRegexp: "^https://github.com/anchore/"},
+			want:   true,
+		},
+		{
+			name:   "issuer without a trusted root",
+			config: Config{Issuer: testIssuer},
+			want:   true,
+		},
+		{
+			name:   "transparency log key without a trusted root",
+			config: Config{TransparencyLogKey: "rekor.pub"},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.config.IsSet())
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package sigstore
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Envelope is a DSSE envelope, which is how in-toto attestations (e.g. SLSA provenance) are signed.
+type Envelope struct {
+	PayloadType string              `json:"payloadType"`
+	Payload     []byte              `json:"payload"`
+	Signatures  []EnvelopeSignature `json:"signatures"`
+}
+
+// EnvelopeSignature is one signature over an envelope. Some producers (e.g. the SLSA GitHub generator) embed the
+// signing certificate next to the signature.
+type EnvelopeSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   []byte `json:"sig"`
+	Cert  string `json:"cert,omitempty"`
+}
+
+// Attestation is a signed envelope along with the material needed to verify it.
+type Attestation struct {
+	Envelope Envelope
+	// Certificates is the signing certificate followed by any intermediates (empty for key-based signatures).
+	Certificates []*x509.Certificate
+	// Entries are the transparency log entries for the envelope (only known for bundles).
+	Entries []LogEntry
+}
+
+// ParseAttestations decodes attestations from either a single document or JSON lines (e.g. "*.intoto.jsonl"), where
+// each document is a sigstore bundle holding a DSSE envelope or a bare DSSE envelope.
+func ParseAttestations(contents []byte) ([]Attestation, error) {
+	var attestations []Attestation
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, len(contents)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		a, err := parseAttestation(line)
+		if err != nil {
+			// not JSON lines, so try the whole document (e.g. a pretty-printed bundle)
+			if a, err := parseAttestation(contents); err == nil {
+				return []Attestation{*a}, nil
+			}
+			return nil, err
+		}
+		attestations = append(attestations, *a)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(attestations) == 0 {
+		return nil, fmt.Errorf("no attestations found")
+	}
+	return attestations, nil
+}
+
+func parseAttestation(contents []byte) (*Attestation, error) {
+	var probe struct {
+		MediaType string `json:"mediaType"`
+	}
+	if err := json.Unmarshal(contents, &probe); err != nil {
+		return nil, fmt.Errorf("unable to decode attestation: %w", err)
+	}
+
+	if strings.HasPrefix(probe.MediaType, bundleMediaTypePrefix) {
+		return parseAttestationBundle(contents)
+	}
+
+	var env Envelope
+	if err := json.Unmarshal(contents, &env); err != nil {
+		return nil, fmt.Errorf("unable to decode envelope: %w", err)
+	}
+	if env.PayloadType == "" || len(env.Signatures) == 0 {
+		return nil, fmt.Errorf("attestation is neither a sigstore bundle nor a signed DSSE envelope")
+	}
+
+	a := &Attestation{Envelope: env}
+	for _, sig := range env.Signatures {
+		if sig.Cert == "" {
+			continue
+		}
+		certs, err := ParseCertificates([]byte(sig.Cert))
+		if err != nil {
+			return nil, err
+		}
+		a.Certificates = certs
+		break
+	}
+	return a, nil
+}
+
+func parseAttestationBundle(contents []byte) (*Attestation, error) {
+	var b sigstoreBundle
+	if err := json.Unmarshal(contents, &b); err != nil {
+		return nil, fmt.Errorf("unable to decode sigstore bundle: %w", err)
+	}
+	if b.DSSEEnvelope == nil {
+		return nil, fmt.Errorf("sigstore bundle does not contain a DSSE envelope")
+	}
+
+	certs, err := bundleCertificates(b)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := bundleEntries(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Attestation{
+		Envelope:     *b.DSSEEnvelope,
+		Certificates: certs,
+		Entries:      entries,
+	}, nil
+}
+
+// VerifyAttestation checks that the envelope has a trusted signature and returns its payload.
+func (p Policy) VerifyAttestation(a Attestation) ([]byte, error) {
+	if len(a.Envelope.Signatures) == 0 {
+		return nil, fmt.Errorf("envelope has no signatures")
+	}
+
+	keys, err := p.verificationKeys(a.Certificates, a.Entries, a.Envelope.verifyBinding)
+	if err != nil {
+		return nil, err
+	}
+
+	message := a.Envelope.pae()
+	for _, sig := range a.Envelope.Signatures {
+		for _, key := range keys {
+			if verifySignature(key, message, sig.Sig) == nil {
+				return a.Envelope.Payload, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("envelope signature does not match the payload")
+}
+
+// pae is the DSSE pre-authentication encoding of the payload, which is what is signed.
+func (e Envelope) pae() []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(e.PayloadType), e.PayloadType, len(e.Payload), e.Payload))
+}
+
+// dsseBody is the subset of a "dsse" or "intoto" (v0.0.2) entry that binds the entry to an envelope.
+type dsseBody struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		// dsse entries
+		PayloadHash *entryHash `json:"payloadHash"`
+		Signatures  []struct {
+			Signature []byte `json:"signature"`
+		} `json:"signatures"`
+		// intoto entries
+		Content *struct {
+			PayloadHash *entryHash `json:"payloadHash"`
+			Envelope    struct {
+				Signatures []struct {
+					// the base64 encoded signature, base64 encoded again
+					Sig []byte `json:"sig"`
+				} `json:"signatures"`
+			} `json:"envelope"`
+		} `json:"content"`
+	} `json:"spec"`
+}
+
+type entryHash struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+}
+
+// verifyBinding checks that the logged entry describes a signature of the envelope.
+func (e Envelope) verifyBinding(entry LogEntry) error {
+	var body dsseBody
+	if err := json.Unmarshal(entry.Body, &body); err != nil {
+		return fmt.Errorf("unable to decode entry body: %w", err)
+	}
+
+	var hash *entryHash
+	var logged [][]byte
+	switch body.Kind {
+	case "dsse":
+		hash = body.Spec.PayloadHash
+		for _, s := range body.Spec.Signatures {
+			logged = append(logged, s.Signature)
+		}
+	case "intoto":
+		if body.Spec.Content != nil {
+			hash = body.Spec.Content.PayloadHash
+			for _, s := range body.Spec.Content.Envelope.Signatures {
+				sig, err := base64.StdEncoding.DecodeString(string(s.Sig))
+				if err != nil {
+					return fmt.Errorf("unable to decode entry signature: %w", err)
+				}
+				logged = append(logged, sig)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported entry kind %q", body.Kind)
+	}
+
+	digest := sha256.Sum256(e.Payload)
+	if hash == nil || hash.Algorithm != "sha256" || hash.Value != hex.EncodeToString(digest[:]) {
+		return fmt.Errorf("entry is for a different payload")
+	}
+	for _, sig := range e.Signatures {
+		for _, l := range logged {
+			if bytes.Equal(l, sig.Sig) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("entry is for a different signature")
+}
@@ -0,0 +1,70 @@
+package sigstore
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// Material is the signature material published alongside an artifact (e.g. by "cosign sign-blob").
+type Material struct {
+	// Signature is the raw signature over the artifact.
+	Signature []byte
+	// Certificates is the signing certificate followed by any intermediates (empty for key-based signatures).
+	Certificates []*x509.Certificate
+	// Digest is the SHA-256 digest of the artifact that was signed (only known for sigstore bundles).
+	Digest []byte
+	// Entries are the transparency log entries for the signature (only known for bundles).
+	Entries []LogEntry
+}
+
+// ParseSignature decodes a signature file, which cosign writes base64 encoded.
+func ParseSignature(contents []byte) ([]byte, error) {
+	trimmed := strings.TrimSpace(string(contents))
+	if trimmed == "" {
+		return nil, fmt.Errorf("empty signature")
+	}
+	sig, err := base64.StdEncoding.DecodeString(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode signature: %w", err)
+	}
+	return sig, nil
+}
+
+// ParseCertificates decodes a PEM certificate chain (leaf first). Cosign writes certificates as base64 encoded PEM,
+// which is accepted as well.
+func ParseCertificates(contents []byte) ([]*x509.Certificate, error) {
+	contents = bytes.TrimSpace(contents)
+	if !bytes.HasPrefix(contents, []byte("-----BEGIN")) {
+		decoded, err := base64.StdEncoding.DecodeString(string(contents))
+		if err != nil {
+			return nil, fmt.Errorf("certificate is neither PEM nor base64 encoded PEM: %w", err)
+		}
+		contents = decoded
+	}
+
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, contents = pem.Decode(contents)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates found")
+	}
+	return certs, nil
+}
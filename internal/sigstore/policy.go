@@ -0,0 +1,215 @@
+package sigstore
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+)
+
+var (
+	// oidIssuerV2 is the fulcio certificate extension holding the OIDC issuer as a DER encoded string.
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+	// oidIssuerV1 is the deprecated fulcio certificate extension holding the OIDC issuer as a raw string.
+	oidIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+)
+
+// Policy describes what signature material must satisfy to be trusted. Key-based signatures are verified with the
+// public keys, and certificate-based (keyless) signatures must chain to the roots and carry the expected identity.
+type Policy struct {
+	// PublicKeys verify key-based signatures (e.g. "cosign sign-blob --key").
+	PublicKeys []crypto.PublicKey
+	// Roots are the certificate authorities that signing certificates must chain to (e.g. the fulcio root).
+	Roots *x509.CertPool
+	// Intermediates are additional certificates that may be used to build a chain to the roots.
+	Intermediates *x509.CertPool
+	// Identity is the exact subject alternative name (URI or email) expected on signing certificates.
+	Identity string
+	// IdentityRegexp matches the subject alternative name expected on signing certificates.
+	IdentityRegexp *regexp.Regexp
+	// Issuer is the OIDC issuer expected on signing certificates (e.g. "https://token.actions.githubusercontent.com").
+	Issuer string
+	// LogKeys verify the signed entry timestamps of transparency log entries. When given, certificate-based signatures
+	// must have a logged entry, and the certificate must have been valid at the time it was logged.
+	LogKeys []crypto.PublicKey
+}
+
+// Verify checks that the signature material is a trusted signature over the artifact.
+func (p Policy) Verify(artifact []byte, m Material) error {
+	if len(m.Signature) == 0 {
+		return fmt.Errorf("no signature found")
+	}
+
+	digest := sha256.Sum256(artifact)
+	if m.Digest != nil && !bytes.Equal(m.Digest, digest[:]) {
+		return fmt.Errorf("artifact digest does not match the signed digest")
+	}
+
+	keys, err := p.verificationKeys(m.Certificates, m.Entries, func(e LogEntry) error {
+		return e.verifyBinding(m.Signature, digest[:])
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if verifySignature(key, artifact, m.Signature) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature does not match the artifact")
+}
+
+// verificationKeys returns the keys that the signature may be verified with: the key of a trusted signing certificate,
+// or the configured public keys for key-based signatures. The binding checks that a transparency log entry is for the
+// signature being verified.
+func (p Policy) verificationKeys(certs []*x509.Certificate, entries []LogEntry, binding func(LogEntry) error) ([]crypto.PublicKey, error) {
+	if len(certs) == 0 {
+		if len(p.PublicKeys) == 0 {
+			return nil, fmt.Errorf("signature has no certificate and no public key is configured")
+		}
+		return p.PublicKeys, nil
+	}
+
+	if p.Roots == nil {
+		return nil, fmt.Errorf("signature has a certificate but no trusted root is configured")
+	}
+
+	leaf := certs[0]
+	signedAt, err := p.signingTime(leaf, entries, binding)
+	if err != nil {
+		return nil, err
+	}
+
+	intermediates := x509.NewCertPool()
+	if p.Intermediates != nil {
+		intermediates = p.Intermediates.Clone()
+	}
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         p.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   signedAt,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return nil, fmt.Errorf("untrusted signing certificate: %w", err)
+	}
+
+	if err := p.verifyIdentity(leaf); err != nil {
+		return nil, err
+	}
+
+	return []crypto.PublicKey{leaf.PublicKey}, nil
+}
+
+// signingTime determines when the signature was made, which is when the (short-lived) signing certificate must have
+// been valid. Without transparency log keys there is no trusted time, so the certificate is checked as of when it was
+// issued (which still requires that it chains to a trusted root).
+func (p Policy) signingTime(leaf *x509.Certificate, entries []LogEntry, binding func(LogEntry) error) (time.Time, error) {
+	if len(p.LogKeys) == 0 {
+		return leaf.NotBefore, nil
+	}
+
+	var errs []error
+	for _, e := range entries {
+		if err := e.verifyTimestamp(p.LogKeys); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := binding(e); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return e.Time(), nil
+	}
+
+	if len(errs) == 0 {
+		return time.Time{}, fmt.Errorf("signature has no transparency log entry")
+	}
+	return time.Time{}, fmt.Errorf("no trusted transparency log entry for the signature: %v", errs)
+}
+
+func (p Policy) verifyIdentity(cert *x509.Certificate) error {
+	identities := slices.Clone(cert.EmailAddresses)
+	for _, u := range cert.URIs {
+		identities = append(identities, u.String())
+	}
+
+	if !slices.ContainsFunc(identities, p.matchesIdentity) {
+		return fmt.Errorf("signing certificate identity %v does not match the expected identity", identities)
+	}
+
+	issuer, err := certificateIssuer(cert)
+	if err != nil {
+		return err
+	}
+	if p.Issuer != "" && issuer != p.Issuer {
+		return fmt.Errorf("signing certificate issuer %q does not match the expected issuer %q", issuer, p.Issuer)
+	}
+	return nil
+}
+
+func (p Policy) matchesIdentity(identity string) bool {
+	if p.Identity != "" && identity != p.Identity {
+		return false
+	}
+	if p.IdentityRegexp != nil && !p.IdentityRegexp.MatchString(identity) {
+		return false
+	}
+	return p.Identity != "" || p.IdentityRegexp != nil
+}
+
+// certificateIssuer returns the OIDC issuer recorded in a fulcio signing certificate.
+func certificateIssuer(cert *x509.Certificate) (string, error) {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidIssuerV2) {
+			var issuer string
+			if _, err := asn1.UnmarshalWithParams(ext.Value, &issuer, "utf8"); err != nil {
+				return "", fmt.Errorf("invalid issuer extension: %w", err)
+			}
+			return issuer, nil
+		}
+	}
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidIssuerV1) {
+			return string(ext.Value), nil
+		}
+	}
+	return "", fmt.Errorf("signing certificate has no issuer extension")
+}
+
+// verifySignature checks a signature over the message, hashing with SHA-256 for key types that sign digests.
+func verifySignature(key crypto.PublicKey, message, signature []byte) error {
+	digest := sha256.Sum256(message)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if ecdsa.VerifyASN1(k, digest[:], signature) {
+			return nil
+		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil {
+			return nil
+		}
+		if rsa.VerifyPSS(k, crypto.SHA256, digest[:], signature, nil) == nil {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if ed25519.Verify(k, message, signature) {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+	return fmt.Errorf("invalid signature")
+}
@@ -0,0 +1,426 @@
+package sigstore
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIdentity = "https://github.com/anchore/binny/.github/workflows/release.yaml@refs/heads/main"
+	testIssuer   = "https://token.actions.githubusercontent.com"
+)
+
+// testAuthority is a locally generated stand-in for fulcio (the certificate authority) and rekor (the transparency
+// log).
+type testAuthority struct {
+	t       *testing.T
+	rootKey *ecdsa.PrivateKey
+	root    *x509.Certificate
+	logKey  *ecdsa.PrivateKey
+}
+
+func newTestAuthority(t *testing.T) *testAuthority {
+	t.Helper()
+	rootKey := newKey(t)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test root"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &rootKey.PublicKey, rootKey)
+	require.NoError(t, err)
+	root, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testAuthority{t: t, rootKey: rootKey, root: root, logKey: newKey(t)}
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+// issue creates a short-lived signing certificate for the identity and issuer, valid from the given time.
+func (a *testAuthority) issue(key *ecdsa.PrivateKey, identity, issuer string, notBefore time.Time) *x509.Certificate {
+	a.t.Helper()
+	uri, err := url.Parse(identity)
+	require.NoError(a.t, err)
+	issuerExt, err := asn1.MarshalWithParams(issuer, "utf8")
+	require.NoError(a.t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       notBefore,
+		NotAfter:        notBefore.Add(10 * time.Minute),
+		URIs:            []*url.URL{uri},
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		ExtraExtensions: []pkix.Extension{{Id: oidIssuerV2, Value: issuerExt}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, a.root, &key.PublicKey, a.rootKey)
+	require.NoError(a.t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(a.t, err)
+	return cert
+}
+
+// logEntry creates a transparency log entry for the signature over the artifact, integrated at the given time.
+func (a *testAuthority) logEntry(artifact, signature []byte, integratedAt time.Time) LogEntry {
+	a.t.Helper()
+	digest := sha256.Sum256(artifact)
+	body, err := json.Marshal(map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]any{
+			"data":      map[string]any{"hash": map[string]any{"algorithm": "sha256", "value": hex.EncodeToString(digest[:])}},
+			"signature": map[string]any{"content": base64.StdEncoding.EncodeToString(signature)},
+		},
+	})
+	require.NoError(a.t, err)
+
+	logID := sha256.Sum256([]byte("test log"))
+	entry := LogEntry{
+		Body:           body,
+		IntegratedTime: integratedAt.Unix(),
+		LogIndex:       42,
+		LogID:          hex.EncodeToString(logID[:]),
+	}
+	payload, err := json.Marshal(map[string]any{
+		"body":           base64.StdEncoding.EncodeToString(entry.Body),
+		"integratedTime": entry.IntegratedTime,
+		"logID":          entry.LogID,
+		"logIndex":       entry.LogIndex,
+	})
+	require.NoError(a.t, err)
+	entry.SignedEntryTimestamp = sign(a.t, a.logKey, payload)
+	return entry
+}
+
+func (a *testAuthority) roots() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(a.root)
+	return pool
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, message []byte) []byte {
+	t.Helper()
+	digest := sha256.Sum256(message)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	return sig
+}
+
+func publicKeyPEM(t *testing.T, key crypto.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func certificatePEM(cert *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+func TestPolicy_Verify(t *testing.T) {
+	artifact := []byte("checksums for the release")
+	now := time.Now()
+
+	authority := newTestAuthority(t)
+	signingKey := newKey(t)
+	otherKey := newKey(t)
+
+	keySig := sign(t, signingKey, artifact)
+
+	cert := authority.issue(signingKey, testIdentity, testIssuer, now.Add(-time.Hour))
+	certSig := sign(t, signingKey, artifact)
+	keylessPolicy := Policy{Roots: authority.roots(), Identity: testIdentity, Issuer: testIssuer}
+	loggedPolicy := keylessPolicy
+	loggedPolicy.LogKeys = []crypto.PublicKey{&authority.logKey.PublicKey}
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		material Material
+		artifact []byte
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			name:     "key-based signature",
+			policy:   Policy{PublicKeys: []crypto.PublicKey{&signingKey.PublicKey}},
+			material: Material{Signature: keySig},
+		},
+		{
+			name:     "key-based signature with any of the keys",
+			policy:   Policy{PublicKeys: []crypto.PublicKey{&otherKey.PublicKey, &signingKey.PublicKey}},
+			material: Material{Signature: keySig},
+		},
+		{
+			name:     "key-based signature with the wrong key",
+			policy:   Policy{PublicKeys: []crypto.PublicKey{&otherKey.PublicKey}},
+			material: Material{Signature: keySig},
+			wantErr:  require.Error,
+		},
+		{
+			name:     "tampered artifact",
+			policy:   Policy{PublicKeys: []crypto.PublicKey{&signingKey.PublicKey}},
+			material: Material{Signature: keySig},
+			artifact: []byte("something else"),
+			wantErr:  require.Error,
+		},
+		{
+			name:     "signed digest does not match the artifact",
+			policy:   Policy{PublicKeys: []crypto.PublicKey{&signingKey.PublicKey}},
+			material: Material{Signature: keySig, Digest: []byte("nope")},
+			wantErr:  require.Error,
+		},
+		{
+			name:     "key-based signature without a configured key",
+			policy:   keylessPolicy,
+			material: Material{Signature: keySig},
+			wantErr:  require.Error,
+		},
+		{
+			name:     "no signature",
+			policy:   Policy{PublicKeys: []crypto.PublicKey{&signingKey.PublicKey}},
+			material: Material{},
+			wantErr:  require.Error,
+		},
+		{
+			name:     "keyless signature",
+			policy:   keylessPolicy,
+			material: Material{Signature: certSig, Certificates: []*x509.Certificate{cert}},
+		},
+		{
+			name: "keyless signature matching an identity regexp",
+			policy: Policy{
+				Roots:          authority.roots(),
+				IdentityRegexp: regexp.MustCompile(`^https://github\.com/anchore/`),
+				Issuer:         testIssuer,
+			},
+			material: Material{Signature: certSig, Certificates: []*x509.Certificate{cert}},
+		},
+		{
+			name:     "keyless signature with the wrong identity",
+			policy:   Policy{Roots: authority.roots(), Identity: "https://github.com/someone/else", Issuer: testIssuer},
+			material: Material{Signature: certSig, Certificates: []*x509.Certificate{cert}},
+			wantErr:  require.Error,
+		},
+		{
+			name:     "keyless signature with the wrong issuer",
+			policy:   Policy{Roots: authority.roots(), Identity: testIdentity, Issuer: "https://accounts.example.com"},
+			material: Material{Signature: certSig, Certificates: []*x509.Certificate{cert}},
+			wantErr:  require.Error,
+		},
+		{
+			name:     "keyless signature from an untrusted authority",
+			policy:   Policy{Roots: newTestAuthority(t).roots(), Identity: testIdentity, Issuer: testIssuer},
+			material: Material{Signature: certSig, Certificates: []*x509.Certificate{cert}},
+			wantErr:  require.Error,
+		},
+		{
+			name:     "keyless signature without a trusted root",
+			policy:   Policy{PublicKeys: []crypto.PublicKey{&signingKey.PublicKey}},
+			material: Material{Signature: certSig, Certificates: []*x509.Certificate{cert}},
+			wantErr:  require.Error,
+		},
+		{
+			name:   "keyless signature logged while the certificate was valid",
+			policy: loggedPolicy,
+			material: Material{
+				Signature:    certSig,
+				Certificates: []*x509.Certificate{cert},
+				Entries:      []LogEntry{authority.logEntry(artifact, certSig, now.Add(-55*time.Minute))},
+			},
+		},
+		{
+			name:   "keyless signature logged after the certificate expired",
+			policy: loggedPolicy,
+			material: Material{
+				Signature:    certSig,
+				Certificates: []*x509.Certificate{cert},
+				Entries:      []LogEntry{authority.logEntry(artifact, certSig, now)},
+			},
+			wantErr: require.Error,
+		},
+		{
+			name:     "keyless signature without a log entry",
+			policy:   loggedPolicy,
+			material: Material{Signature: certSig, Certificates: []*x509.Certificate{cert}},
+			wantErr:  require.Error,
+		},
+		{
+			name:   "keyless signature with a log entry for another signature",
+			policy: loggedPolicy,
+			material: Material{
+				Signature:    certSig,
+				Certificates: []*x509.Certificate{cert},
+				Entries:      []LogEntry{authority.logEntry(artifact, keySig, now.Add(-55*time.Minute))},
+			},
+			wantErr: require.Error,
+		},
+		{
+			name:   "keyless signature with a log entry signed by another log",
+			policy: loggedPolicy,
+			material: Material{
+				Signature:    certSig,
+				Certificates: []*x509.Certificate{cert},
+				Entries:      []LogEntry{newTestAuthority(t).logEntry(artifact, certSig, now.Add(-55*time.Minute))},
+			},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			if tt.artifact == nil {
+				tt.artifact = artifact
+			}
+			tt.wantErr(t, tt.policy.Verify(tt.artifact, tt.material))
+		})
+	}
+}
+
+func TestParseBundle(t *testing.T) {
+	artifact := []byte("checksums for the release")
+	digest := sha256.Sum256(artifact)
+	now := time.Now()
+
+	authority := newTestAuthority(t)
+	signingKey := newKey(t)
+	cert := authority.issue(signingKey, testIdentity, testIssuer, now.Add(-time.Hour))
+	sig := sign(t, signingKey, artifact)
+	entry := authority.logEntry(artifact, sig, now.Add(-55*time.Minute))
+
+	policy := Policy{
+		Roots:    authority.roots(),
+		Identity: testIdentity,
+		Issuer:   testIssuer,
+		LogKeys:  []crypto.PublicKey{&authority.logKey.PublicKey},
+	}
+
+	cosignBundle, err := json.Marshal(map[string]any{
+		"base64Signature": base64.StdEncoding.EncodeToString(sig),
+		"cert":            base64.StdEncoding.EncodeToString([]byte(certificatePEM(cert))),
+		"rekorBundle": map[string]any{
+			"SignedEntryTimestamp": entry.SignedEntryTimestamp,
+			"Payload": map[string]any{
+				"body":           base64.StdEncoding.EncodeToString(entry.Body),
+				"integratedTime": entry.IntegratedTime,
+				"logIndex":       entry.LogIndex,
+				"logID":          entry.LogID,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	logID, err := hex.DecodeString(entry.LogID)
+	require.NoError(t, err)
+	sigstoreBundle, err := json.Marshal(map[string]any{
+		"mediaType": "application/vnd.dev.sigstore.bundle.v0.3+json",
+		"verificationMaterial": map[string]any{
+			"certificate": map[string]any{"rawBytes": cert.Raw},
+			"tlogEntries": []any{
+				map[string]any{
+					"logIndex":          "42",
+					"logId":             map[string]any{"keyId": logID},
+					"integratedTime":    strconv.FormatInt(entry.IntegratedTime, 10),
+					"inclusionPromise":  map[string]any{"signedEntryTimestamp": entry.SignedEntryTimestamp},
+					"canonicalizedBody": entry.Body,
+				},
+			},
+		},
+		"messageSignature": map[string]any{
+			"messageDigest": map[string]any{"algorithm": "SHA2_256", "digest": digest[:]},
+			"signature":     sig,
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		contents []byte
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			name:     "cosign bundle",
+			contents: cosignBundle,
+		},
+		{
+			name:     "sigstore bundle",
+			contents: sigstoreBundle,
+		},
+		{
+			name:     "unknown format",
+			contents: []byte(`{"signature": "abc"}`),
+			wantErr:  require.Error,
+		},
+		{
+			name:     "sigstore bundle without a message signature",
+			contents: []byte(`{"mediaType": "application/vnd.dev.sigstore.bundle.v0.3+json", "dsseEnvelope": {}}`),
+			wantErr:  require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			m, err := ParseBundle(tt.contents)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			require.Len(t, m.Certificates, 1)
+			require.Len(t, m.Entries, 1)
+			assert.Equal(t, entry.IntegratedTime, m.Entries[0].IntegratedTime)
+			assert.NoError(t, policy.Verify(artifact, *m))
+		})
+	}
+}
+
+func TestParseCertificates(t *testing.T) {
+	authority := newTestAuthority(t)
+	encoded := certificatePEM(authority.root)
+
+	for name, contents := range map[string]string{
+		"pem":        encoded,
+		"base64 pem": base64.StdEncoding.EncodeToString([]byte(encoded)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			certs, err := ParseCertificates([]byte(contents))
+			require.NoError(t, err)
+			require.Len(t, certs, 1)
+			assert.Equal(t, authority.root.Raw, certs[0].Raw)
+		})
+	}
+
+	_, err := ParseCertificates([]byte("not a certificate"))
+	require.Error(t, err)
+}
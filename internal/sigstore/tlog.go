@@ -0,0 +1,93 @@
+package sigstore
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// LogEntry is a transparency log (Rekor) entry for a signature, as included in a bundle.
+type LogEntry struct {
+	// Body is the canonicalized entry that was logged (e.g. a "hashedrekord" describing the signature).
+	Body           []byte
+	IntegratedTime int64
+	LogIndex       int64
+	// LogID is the hex encoded SHA-256 digest of the log's public key.
+	LogID string
+	// SignedEntryTimestamp is the log's signature over the entry, which promises that it was logged at IntegratedTime.
+	SignedEntryTimestamp []byte
+}
+
+// Time is when the entry was integrated into the log.
+func (e LogEntry) Time() time.Time {
+	return time.Unix(e.IntegratedTime, 0)
+}
+
+// verifyTimestamp checks the signed entry timestamp against any of the given log keys.
+func (e LogEntry) verifyTimestamp(keys []crypto.PublicKey) error {
+	if len(e.SignedEntryTimestamp) == 0 {
+		return fmt.Errorf("entry has no signed entry timestamp")
+	}
+
+	// the signed payload is the canonical JSON encoding of the entry (keys in lexical order)
+	payload, err := json.Marshal(struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogID          string `json:"logID"`
+		LogIndex       int64  `json:"logIndex"`
+	}{
+		Body:           base64.StdEncoding.EncodeToString(e.Body),
+		IntegratedTime: e.IntegratedTime,
+		LogID:          e.LogID,
+		LogIndex:       e.LogIndex,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if verifySignature(key, payload, e.SignedEntryTimestamp) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("signed entry timestamp does not match any transparency log key")
+}
+
+// hashedRekordBody is the subset of a "hashedrekord" entry that binds the entry to a signature and artifact digest.
+type hashedRekordBody struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content []byte `json:"content"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// verifyBinding checks that the logged entry describes the given signature over the artifact digest (otherwise the
+// timestamp says nothing about this signature).
+func (e LogEntry) verifyBinding(signature, digest []byte) error {
+	var body hashedRekordBody
+	if err := json.Unmarshal(e.Body, &body); err != nil {
+		return fmt.Errorf("unable to decode entry body: %w", err)
+	}
+	if body.Kind != "hashedrekord" {
+		return fmt.Errorf("unsupported entry kind %q", body.Kind)
+	}
+	if body.Spec.Data.Hash.Algorithm != "sha256" || body.Spec.Data.Hash.Value != hex.EncodeToString(digest) {
+		return fmt.Errorf("entry is for a different artifact")
+	}
+	if !bytes.Equal(body.Spec.Signature.Content, signature) {
+		return fmt.Errorf("entry is for a different signature")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package binny
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LatestStrategy determines how the latest version is chosen from the releases of a tool.
+type LatestStrategy string
+
+const (
+	// LatestGitHub uses the release that the maintainers marked as "latest" on GitHub, falling back to the highest
+	// version when there is no such release or it is not a candidate (this is the default).
+	LatestGitHub LatestStrategy = "github-latest"
+	// LatestHighestSemver uses the highest version according to the version scheme, regardless of how the release is
+	// marked upstream.
+	LatestHighestSemver LatestStrategy = "highest-semver"
+	// LatestNewestDate uses the most recently published release (e.g. for tools that publish backports to older lines
+	// that should not be picked up).
+	LatestNewestDate LatestStrategy = "newest-date"
+)
+
+// ParseLatestStrategy validates and normalizes a latest strategy value. An empty value is returned as-is so that
+// callers can distinguish "not configured" from an explicit strategy.
+func ParseLatestStrategy(value string) (LatestStrategy, error) {
+	s := LatestStrategy(strings.ToLower(strings.TrimSpace(value)))
+	switch s {
+	case "", LatestGitHub, LatestHighestSemver, LatestNewestDate:
+		return s, nil
+	}
+	return "", fmt.Errorf("invalid latest strategy %q (allowed: %s, %s, %s)", value, LatestGitHub, LatestHighestSemver, LatestNewestDate)
+}
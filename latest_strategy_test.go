@@ -0,0 +1,32 @@
+package binny
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLatestStrategy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    LatestStrategy
+		wantErr require.ErrorAssertionFunc
+	}{
+		{value: "", want: ""},
+		{value: "github-latest", want: LatestGitHub},
+		{value: " Highest-Semver ", want: LatestHighestSemver},
+		{value: "NEWEST-DATE", want: LatestNewestDate},
+		{value: "newest", wantErr: require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := ParseLatestStrategy(tt.value)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package binny
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PinnedChecksums maps platforms ("os/arch", e.g. "linux/amd64") to the expected digest of what is installed for that
+// platform. Digests may be prefixed with the hash algorithm (e.g. "sha512:..."), otherwise they are sha256.
+type PinnedChecksums map[string]string
+
+// Platform returns the key for the given OS and architecture.
+func Platform(goos, goarch string) string {
+	return goos + "/" + goarch
+}
+
+// SplitPlatform returns the OS and architecture of a platform key.
+func SplitPlatform(platform string) (string, string, error) {
+	goos, goarch, ok := strings.Cut(platform, "/")
+	if !ok || goos == "" || goarch == "" {
+		return "", "", fmt.Errorf("invalid platform %q (expected os/arch, e.g. linux/amd64)", platform)
+	}
+	return goos, goarch, nil
+}
+
+// ForPlatform returns the pinned digest for the given OS and architecture.
+func (p PinnedChecksums) ForPlatform(goos, goarch string) (string, bool) {
+	checksum, ok := p[Platform(goos, goarch)]
+	return checksum, ok && checksum != ""
+}
+
+// Platforms returns the pinned platforms in sorted order.
+func (p PinnedChecksums) Platforms() []string {
+	platforms := make([]string, 0, len(p))
+	for platform := range p {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+// Validate checks that every key is a platform.
+func (p PinnedChecksums) Validate() error {
+	for _, platform := range p.Platforms() {
+		if _, _, err := SplitPlatform(platform); err != nil {
+			return err
+		}
+	}
+	return nil
+}
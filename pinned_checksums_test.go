@@ -0,0 +1,41 @@
+package binny
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinnedChecksums_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pinned  PinnedChecksums
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:   "platforms",
+			pinned: PinnedChecksums{"linux/amd64": "abc", "darwin/arm64": ""},
+		},
+		{
+			name: "empty",
+		},
+		{
+			name:    "missing arch",
+			pinned:  PinnedChecksums{"linux": "abc"},
+			wantErr: require.Error,
+		},
+		{
+			name:    "missing os",
+			pinned:  PinnedChecksums{"/amd64": "abc"},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			tt.wantErr(t, tt.pinned.Validate())
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package binny
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrereleasePolicy controls whether prerelease versions are candidates during version resolution.
+type PrereleasePolicy string
+
+const (
+	// PrereleaseExclude only considers release versions (this is the default).
+	PrereleaseExclude PrereleasePolicy = "exclude"
+	// PrereleaseInclude considers both release and prerelease versions.
+	PrereleaseInclude PrereleasePolicy = "include"
+	// PrereleaseOnly only considers prerelease versions (e.g. for tools that deliberately track release candidates).
+	PrereleaseOnly PrereleasePolicy = "only"
+)
+
+// ParsePrereleasePolicy validates and normalizes a prerelease policy value. An empty value is returned as-is so that
+// callers can distinguish "not configured" from an explicit policy.
+func ParsePrereleasePolicy(value string) (PrereleasePolicy, error) {
+	p := PrereleasePolicy(strings.ToLower(strings.TrimSpace(value)))
+	switch p {
+	case "", PrereleaseExclude, PrereleaseInclude, PrereleaseOnly:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid prerelease policy %q (allowed: %s, %s, %s)", value, PrereleaseExclude, PrereleaseInclude, PrereleaseOnly)
+}
+
+// Allows reports whether a version with the given prerelease status is a candidate under this policy.
+func (p PrereleasePolicy) Allows(isPrerelease bool) bool {
+	switch p {
+	case PrereleaseInclude:
+		return true
+	case PrereleaseOnly:
+		return isPrerelease
+	default:
+		return !isPrerelease
+	}
+}
@@ -0,0 +1,51 @@
+package binny
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrereleasePolicy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    PrereleasePolicy
+		wantErr require.ErrorAssertionFunc
+	}{
+		{value: "", want: ""},
+		{value: "exclude", want: PrereleaseExclude},
+		{value: " Include ", want: PrereleaseInclude},
+		{value: "ONLY", want: PrereleaseOnly},
+		{value: "sometimes", wantErr: require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := ParsePrereleasePolicy(tt.value)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPrereleasePolicy_Allows(t *testing.T) {
+	tests := []struct {
+		policy     PrereleasePolicy
+		release    bool
+		prerelease bool
+	}{
+		{policy: "", release: true, prerelease: false},
+		{policy: PrereleaseExclude, release: true, prerelease: false},
+		{policy: PrereleaseInclude, release: true, prerelease: true},
+		{policy: PrereleaseOnly, release: false, prerelease: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			assert.Equal(t, tt.release, tt.policy.Allows(false))
+			assert.Equal(t, tt.prerelease, tt.policy.Allows(true))
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package binny
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// VersionScheme determines how versions are parsed, ordered, and matched against constraints.
+type VersionScheme string
+
+const (
+	// SchemeSemver orders versions according to semantic versioning (this is the default).
+	SchemeSemver VersionScheme = "semver"
+	// SchemeCalver orders versions made of dot, dash, or underscore separated numbers (e.g. "2024.06.01").
+	SchemeCalver VersionScheme = "calver"
+	// SchemeNumeric orders versions that are a single number (e.g. build numbers like "4567").
+	SchemeNumeric VersionScheme = "numeric"
+	// SchemeLexical orders versions by plain string comparison.
+	SchemeLexical VersionScheme = "lexical"
+)
+
+// ParseVersionScheme validates and normalizes a version scheme value. An empty value is returned as-is so that
+// callers can distinguish "not configured" from an explicit scheme.
+func ParseVersionScheme(value string) (VersionScheme, error) {
+	s := VersionScheme(strings.ToLower(strings.TrimSpace(value)))
+	switch s {
+	case "", SchemeSemver, SchemeCalver, SchemeNumeric, SchemeLexical:
+		return s, nil
+	}
+	return "", fmt.Errorf("invalid version scheme %q (allowed: %s, %s, %s, %s)", value, SchemeSemver, SchemeCalver, SchemeNumeric, SchemeLexical)
+}
+
+// Version is a version parsed according to a VersionScheme. Versions may only be compared to other versions parsed
+// with the same scheme.
+type Version interface {
+	// Original returns the version string as it was given.
+	Original() string
+	// Compare returns -1, 0, or 1 when the version is less than, equal to, or greater than the other version.
+	Compare(other Version) int
+	// IsPrerelease reports whether the version string indicates a prerelease.
+	IsPrerelease() bool
+}
+
+// VersionConstraint checks whether a version satisfies a constraint expression (e.g. ">= 2024.01, < 2025").
+type VersionConstraint interface {
+	Check(v Version) bool
+}
+
+// ParseVersion parses a version according to the scheme.
+func (s VersionScheme) ParseVersion(value string) (Version, error) {
+	switch s {
+	case SchemeCalver:
+		v, err := parseCalver(value)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	case SchemeNumeric:
+		v, err := parseCalver(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(v.parts) != 1 {
+			return nil, fmt.Errorf("invalid numeric version %q", value)
+		}
+		return v, nil
+	case SchemeLexical:
+		if value == "" {
+			return nil, fmt.Errorf("empty version")
+		}
+		return lexicalVersion(value), nil
+	default:
+		v, err := semver.NewVersion(value)
+		if err != nil {
+			return nil, err
+		}
+		return semverVersion{v: v}, nil
+	}
+}
+
+// ParseConstraint parses a constraint expression according to the scheme. Semver constraints support the full
+// Masterminds syntax (e.g. "~1.2", "^1"), all other schemes support comparisons (=, !=, >, >=, <, <=) joined with
+// "," (and) or "||" (or). The includePrerelease flag only applies to semver constraints.
+func (s VersionScheme) ParseConstraint(value string, includePrerelease bool) (VersionConstraint, error) {
+	switch s {
+	case SchemeCalver, SchemeNumeric, SchemeLexical:
+		return parseComparisonConstraint(value, s.ParseVersion)
+	default:
+		c, err := semver.NewConstraint(value)
+		if err != nil {
+			return nil, err
+		}
+		c.IncludePrerelease = includePrerelease
+		return semverConstraint{c: c}, nil
+	}
+}
+
+type semverVersion struct {
+	v *semver.Version
+}
+
+func (s semverVersion) Original() string {
+	return s.v.Original()
+}
+
+func (s semverVersion) Compare(other Version) int {
+	return s.v.Compare(other.(semverVersion).v)
+}
+
+func (s semverVersion) IsPrerelease() bool {
+	return s.v.Prerelease() != ""
+}
+
+type semverConstraint struct {
+	c *semver.Constraints
+}
+
+func (s semverConstraint) Check(v Version) bool {
+	sv, ok := v.(semverVersion)
+	return ok && s.c.Check(sv.v)
+}
+
+// calverPattern matches separated numbers with an optional suffix, which is considered a prerelease marker
+// (e.g. "2024.06.01", "v2024-06-01", "2024.06.01-rc1", "4567").
+var calverPattern = regexp.MustCompile(`^v?(\d+(?:[._-]\d+)*)(?:[-.~]?([0-9A-Za-z][0-9A-Za-z._-]*))?$`)
+
+type calverVersion struct {
+	original string
+	parts    []uint64
+	suffix   string
+}
+
+func parseCalver(value string) (calverVersion, error) {
+	match := calverPattern.FindStringSubmatch(value)
+	if match == nil {
+		return calverVersion{}, fmt.Errorf("invalid version %q", value)
+	}
+
+	fields := strings.FieldsFunc(match[1], func(r rune) bool {
+		return r == '.' || r == '-' || r == '_'
+	})
+
+	parts := make([]uint64, len(fields))
+	for i, f := range fields {
+		n, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return calverVersion{}, fmt.Errorf("invalid version %q: %w", value, err)
+		}
+		parts[i] = n
+	}
+
+	return calverVersion{original: value, parts: parts, suffix: match[2]}, nil
+}
+
+func (c calverVersion) Original() string {
+	return c.original
+}
+
+// Compare orders versions by their numeric parts (missing parts are treated as zero), then orders a prerelease
+// before the release it precedes.
+func (c calverVersion) Compare(other Version) int {
+	o := other.(calverVersion)
+	for i := 0; i < max(len(c.parts), len(o.parts)); i++ {
+		var a, b uint64
+		if i < len(c.parts) {
+			a = c.parts[i]
+		}
+		if i < len(o.parts) {
+			b = o.parts[i]
+		}
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	}
+
+	switch {
+	case c.suffix == o.suffix:
+		return 0
+	case c.suffix == "":
+		return 1
+	case o.suffix == "":
+		return -1
+	}
+	return strings.Compare(c.suffix, o.suffix)
+}
+
+func (c calverVersion) IsPrerelease() bool {
+	return c.suffix != ""
+}
+
+type lexicalVersion string
+
+func (l lexicalVersion) Original() string {
+	return string(l)
+}
+
+func (l lexicalVersion) Compare(other Version) int {
+	return strings.Compare(string(l), string(other.(lexicalVersion)))
+}
+
+func (l lexicalVersion) IsPrerelease() bool {
+	return false
+}
+
+// comparisonConstraint is a set of alternatives ("||"), each of which is a set of comparisons that must all hold (",").
+type comparisonConstraint [][]comparison
+
+type comparison struct {
+	op      string
+	operand Version
+}
+
+// operators are ordered such that longer operators are matched first.
+var operators = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+func parseComparisonConstraint(value string, parse func(string) (Version, error)) (comparisonConstraint, error) {
+	var constraint comparisonConstraint
+	for _, alternative := range strings.Split(value, "||") {
+		var comparisons []comparison
+		for _, clause := range strings.Split(alternative, ",") {
+			clause = strings.TrimSpace(clause)
+			if clause == "" {
+				return nil, fmt.Errorf("empty comparison in constraint %q", value)
+			}
+
+			op := "="
+			for _, candidate := range operators {
+				if strings.HasPrefix(clause, candidate) {
+					op = candidate
+					clause = strings.TrimSpace(strings.TrimPrefix(clause, candidate))
+					break
+				}
+			}
+
+			operand, err := parse(clause)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version in constraint %q: %w", value, err)
+			}
+			comparisons = append(comparisons, comparison{op: op, operand: operand})
+		}
+		constraint = append(constraint, comparisons)
+	}
+	return constraint, nil
+}
+
+func (c comparisonConstraint) Check(v Version) bool {
+	for _, comparisons := range c {
+		satisfied := true
+		for _, cmp := range comparisons {
+			if !cmp.check(v) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparison) check(v Version) bool {
+	result := v.Compare(c.operand)
+	switch c.op {
+	case ">=":
+		return result >= 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	case "<":
+		return result < 0
+	case "!=":
+		return result != 0
+	default:
+		return result == 0
+	}
+}
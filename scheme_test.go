@@ -0,0 +1,256 @@
+package binny
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersionScheme(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    VersionScheme
+		wantErr require.ErrorAssertionFunc
+	}{
+		{value: "", want: ""},
+		{value: "semver", want: SchemeSemver},
+		{value: " CalVer ", want: SchemeCalver},
+		{value: "numeric", want: SchemeNumeric},
+		{value: "lexical", want: SchemeLexical},
+		{value: "date", wantErr: require.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := ParseVersionScheme(tt.value)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVersionScheme_ParseVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		scheme         VersionScheme
+		value          string
+		wantPrerelease bool
+		wantErr        require.ErrorAssertionFunc
+	}{
+		{
+			name:   "semver",
+			scheme: SchemeSemver,
+			value:  "v1.2.3",
+		},
+		{
+			name:   "semver is the default",
+			value:  "v1.2.3",
+			scheme: "",
+		},
+		{
+			name:    "four parts is not semver",
+			scheme:  SchemeSemver,
+			value:   "2024.06.01.1",
+			wantErr: require.Error,
+		},
+		{
+			name:   "calver with four parts",
+			scheme: SchemeCalver,
+			value:  "2024.06.01.1",
+		},
+		{
+			name:   "calver with dashes",
+			scheme: SchemeCalver,
+			value:  "v2024-06-01",
+		},
+		{
+			name:           "calver with suffix",
+			scheme:         SchemeCalver,
+			value:          "2024.06.01-rc1",
+			wantPrerelease: true,
+		},
+		{
+			name:    "calver without numbers",
+			scheme:  SchemeCalver,
+			value:   "latest",
+			wantErr: require.Error,
+		},
+		{
+			name:   "numeric",
+			scheme: SchemeNumeric,
+			value:  "4567",
+		},
+		{
+			name:    "numeric with more than one part",
+			scheme:  SchemeNumeric,
+			value:   "45.67",
+			wantErr: require.Error,
+		},
+		{
+			name:   "lexical accepts anything",
+			scheme: SchemeLexical,
+			value:  "build-abc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := tt.scheme.ParseVersion(tt.value)
+			tt.wantErr(t, err)
+			if err != nil {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, tt.value, got.Original())
+			assert.Equal(t, tt.wantPrerelease, got.IsPrerelease())
+		})
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme VersionScheme
+		a, b   string
+		want   int
+	}{
+		{
+			name:   "semver",
+			scheme: SchemeSemver,
+			a:      "v1.10.0",
+			b:      "v1.9.0",
+			want:   1,
+		},
+		{
+			name:   "calver compares parts numerically",
+			scheme: SchemeCalver,
+			a:      "2024.06.01",
+			b:      "2024.10.01",
+			want:   -1,
+		},
+		{
+			name:   "calver missing parts are zero",
+			scheme: SchemeCalver,
+			a:      "2024.06",
+			b:      "2024.06.0",
+			want:   0,
+		},
+		{
+			name:   "calver prerelease sorts before release",
+			scheme: SchemeCalver,
+			a:      "2024.06.01-rc1",
+			b:      "2024.06.01",
+			want:   -1,
+		},
+		{
+			name:   "numeric",
+			scheme: SchemeNumeric,
+			a:      "100",
+			b:      "99",
+			want:   1,
+		},
+		{
+			name:   "lexical",
+			scheme: SchemeLexical,
+			a:      "100",
+			b:      "99",
+			want:   -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := tt.scheme.ParseVersion(tt.a)
+			require.NoError(t, err)
+			b, err := tt.scheme.ParseVersion(tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, a.Compare(b))
+			assert.Equal(t, -tt.want, b.Compare(a))
+		})
+	}
+}
+
+func TestVersionScheme_ParseConstraint(t *testing.T) {
+	tests := []struct {
+		name       string
+		scheme     VersionScheme
+		constraint string
+		version    string
+		want       bool
+		wantErr    require.ErrorAssertionFunc
+	}{
+		{
+			name:       "calver less than",
+			scheme:     SchemeCalver,
+			constraint: "< 2024.07",
+			version:    "2024.06.30",
+			want:       true,
+		},
+		{
+			name:       "calver range",
+			scheme:     SchemeCalver,
+			constraint: ">= 2024.01, < 2025",
+			version:    "2025.01.01",
+			want:       false,
+		},
+		{
+			name:       "calver alternatives",
+			scheme:     SchemeCalver,
+			constraint: "< 2023 || >= 2025",
+			version:    "2025.01.01",
+			want:       true,
+		},
+		{
+			name:       "numeric not equal",
+			scheme:     SchemeNumeric,
+			constraint: "!= 4567",
+			version:    "4567",
+			want:       false,
+		},
+		{
+			name:       "numeric implicit equality",
+			scheme:     SchemeNumeric,
+			constraint: "4567",
+			version:    "4567",
+			want:       true,
+		},
+		{
+			name:       "lexical",
+			scheme:     SchemeLexical,
+			constraint: "<= b",
+			version:    "abc",
+			want:       true,
+		},
+		{
+			name:       "invalid operand",
+			scheme:     SchemeNumeric,
+			constraint: "< 1.2",
+			wantErr:    require.Error,
+		},
+		{
+			name:       "empty comparison",
+			scheme:     SchemeCalver,
+			constraint: ">= 2024,",
+			wantErr:    require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			c, err := tt.scheme.ParseConstraint(tt.constraint, false)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			v, err := tt.scheme.ParseVersion(tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.Check(v))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package binny
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/anchore/binny/internal/log"
+)
+
+// VersionFilter describes which versions are candidates when searching for the latest version.
+type VersionFilter struct {
+	Constraint string
+	Prerelease PrereleasePolicy
+	Tags       TagMapping
+	Scheme     VersionScheme
+	// Skip is a deny-list of versions or constraints that are never candidates.
+	Skip []string
+}
+
+// Parse parses a version according to the filter's version scheme.
+func (f VersionFilter) Parse(version string) (Version, error) {
+	return f.Scheme.ParseVersion(version)
+}
+
+// Constraints parses the version constraint (if any) according to the filter's version scheme. Prerelease versions
+// are allowed to satisfy the constraint whenever the prerelease policy considers them candidates. Versions on the
+// skip list never satisfy the returned constraint.
+func (f VersionFilter) Constraints() (VersionConstraint, error) {
+	var constraint VersionConstraint
+	if f.Constraint != "" {
+		c, err := f.Scheme.ParseConstraint(f.Constraint, f.Prerelease.Allows(true))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse version constraint %q: %v", f.Constraint, err)
+		}
+		constraint = c
+	}
+
+	if len(f.Skip) == 0 {
+		return constraint, nil
+	}
+
+	skip, err := NewSkipList(f.Skip, f.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return skipConstraint{base: constraint, skip: skip}, nil
+}
+
+// IsSkipped reports whether the given version is on the skip list.
+func (f VersionFilter) IsSkipped(version string) (bool, error) {
+	skip, err := NewSkipList(f.Skip, f.Scheme)
+	if err != nil {
+		return false, err
+	}
+	ver, err := f.Parse(version)
+	if err != nil {
+		ver = nil
+	}
+	return skip.Contains(version, ver), nil
+}
+
+// Allows reports whether the given version passes the constraint and prerelease policy. The flaggedPrerelease
+// argument allows for sources that mark prereleases outside of the version string (e.g. GitHub releases).
+func (f VersionFilter) Allows(constraint VersionConstraint, ver Version, flaggedPrerelease bool) bool {
+	if !f.Prerelease.Allows(flaggedPrerelease || ver.IsPrerelease()) {
+		return false
+	}
+	return constraint == nil || constraint.Check(ver)
+}
+
+// IsVersion reports whether the given value is a valid version according to the filter's version scheme.
+func (f VersionFilter) IsVersion(value string) bool {
+	_, err := f.Parse(value)
+	return err == nil
+}
+
+// FilterToLatestVersion returns the latest version from the given tags that passes the filter. Tags are mapped to
+// versions with the filter's tag mapping, so the result is always a version (not a tag).
+func FilterToLatestVersion(tags []string, filter VersionFilter) (string, error) {
+	var parsed []Version
+	for _, tag := range tags {
+		v, ok := filter.Tags.Version(strings.TrimSpace(tag))
+		if !ok || v == "" {
+			continue
+		}
+		ver, err := filter.Parse(v)
+		if err != nil {
+			log.WithFields("scheme", filter.Scheme).Tracef("failed to parse version %q: %v", v, err)
+			continue
+		}
+		parsed = append(parsed, ver)
+	}
+
+	constraint, err := filter.Constraints()
+	if err != nil {
+		return "", err
+	}
+
+	var maxVal Version
+	for _, v := range parsed {
+		if !filter.Allows(constraint, v, false) {
+			continue
+		}
+		if maxVal == nil || v.Compare(maxVal) > 0 {
+			maxVal = v
+		}
+	}
+
+	if maxVal == nil {
+		return "", nil
+	}
+	return maxVal.Original(), nil
+}
+
+func IsSemver(v string) bool {
+	ver, err := semver.NewVersion(v)
+	if err != nil {
+		return false
+	}
+	return ver != nil
+}
@@ -0,0 +1,127 @@
+package binny
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_filterLatestVersion(t *testing.T) {
+
+	tests := []struct {
+		name              string
+		versions          []string
+		versionConstraint string
+		prerelease        PrereleasePolicy
+		tagPattern        string
+		tagTemplate       string
+		scheme            VersionScheme
+		skip              []string
+		want              string
+		wantErr           require.ErrorAssertionFunc
+	}{
+		{
+			name: "no versions",
+		},
+		{
+			name:     "simple version",
+			versions: []string{"v0.2.0", "v1.2.0", "v1.0.0", "v1.1.0"},
+			want:     "v1.2.0",
+		},
+		{
+			name:     "pre-release version",
+			versions: []string{"v0.2.0", "v1.2.0", "v1.2.0-rc0", "v1.0.0", "v1.1.0"},
+			want:     "v1.2.0",
+		},
+		{
+			name:       "pre-release versions included",
+			versions:   []string{"v0.2.0", "v1.2.0", "v1.3.0-rc0", "v1.0.0", "v1.1.0"},
+			prerelease: PrereleaseInclude,
+			want:       "v1.3.0-rc0",
+		},
+		{
+			name:       "only pre-release versions",
+			versions:   []string{"v0.2.0", "v1.2.0-rc1", "v1.3.0", "v1.2.0-rc0"},
+			prerelease: PrereleaseOnly,
+			want:       "v1.2.0-rc1",
+		},
+		{
+			name:              "pre-release versions included with constraint",
+			versions:          []string{"v1.2.0", "v1.3.0-rc0", "v2.0.0-rc0"},
+			versionConstraint: "< v1.9",
+			prerelease:        PrereleaseInclude,
+			want:              "v1.3.0-rc0",
+		},
+		{
+			name:              "with version constraint",
+			versions:          []string{"v0.2.0", "v1.2.0", "v1.0.0", "v1.1.0"},
+			versionConstraint: "<= v1.1.0",
+			want:              "v1.1.0",
+		},
+		{
+			name:              "with version constraint outside range",
+			versions:          []string{"v0.2.0", "v1.2.0", "v1.0.0", "v1.1.0"},
+			versionConstraint: "<= v5, >= v2",
+			want:              "",
+		},
+		{
+			name:              "tag pattern maps tags to versions",
+			versions:          []string{"cli/v1.0.0", "server/v3.0.0", "cli/v1.4.0", "cli/v2.0.0"},
+			versionConstraint: "< v2",
+			tagPattern:        "^cli/(v.*)$",
+			tagTemplate:       "cli/{{ .Version }}",
+			want:              "v1.4.0",
+		},
+		{
+			name:     "calver versions are not dropped",
+			versions: []string{"2024.06.01", "2024.10.02", "2024.10.02-rc1", "2024.9.30"},
+			scheme:   SchemeCalver,
+			want:     "2024.10.02",
+		},
+		{
+			name:              "calver with constraint",
+			versions:          []string{"2024.06.01", "2024.10.02", "2025.01.01.1"},
+			versionConstraint: "< 2025",
+			scheme:            SchemeCalver,
+			want:              "2024.10.02",
+		},
+		{
+			name:     "numeric build numbers",
+			versions: []string{"99", "100", "1000", "not-a-number"},
+			scheme:   SchemeNumeric,
+			want:     "1000",
+		},
+		{
+			name:     "lexical ordering",
+			versions: []string{"99", "100", "1000"},
+			scheme:   SchemeLexical,
+			want:     "99",
+		},
+		{
+			name:              "skipped versions are not candidates",
+			versions:          []string{"v1.0.0", "v1.1.0", "v1.2.0", "v1.2.1", "v2.0.0"},
+			versionConstraint: "< v2",
+			skip:              []string{"v1.2.1", ">= v1.2.0, < v1.2.1"},
+			want:              "v1.1.0",
+		},
+		{
+			name:              "bad constraint",
+			versions:          []string{"v0.2.0", "v1.2.0", "v1.0.0", "v1.1.0"},
+			versionConstraint: "<=! v1.1.0",
+			wantErr:           require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			tags, err := NewTagMapping(tt.tagPattern, tt.tagTemplate)
+			require.NoError(t, err)
+			got, err := FilterToLatestVersion(tt.versions, VersionFilter{Constraint: tt.versionConstraint, Prerelease: tt.prerelease, Tags: tags, Scheme: tt.scheme, Skip: tt.skip})
+			tt.wantErr(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package binny
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SkipList is a deny-list of versions that are never candidates during version resolution. Each entry is either a
+// version (matched exactly) or a constraint (e.g. ">= v1.2.0, < v1.2.3").
+type SkipList []skipEntry
+
+type skipEntry struct {
+	raw        string
+	version    Version
+	constraint VersionConstraint
+}
+
+// NewSkipList parses the deny-list entries according to the version scheme. Entries using constraint syntax must be
+// valid constraints, and entries that do not parse as a version are matched literally (e.g. a git reference).
+func NewSkipList(entries []string, scheme VersionScheme) (SkipList, error) {
+	var list SkipList
+	for _, raw := range entries {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		if looksLikeConstraint(raw) {
+			// prereleases are always candidates for the skip list, regardless of the prerelease policy
+			c, err := scheme.ParseConstraint(raw, true)
+			if err != nil {
+				return nil, fmt.Errorf("invalid skip constraint %q: %w", raw, err)
+			}
+			list = append(list, skipEntry{raw: raw, constraint: c})
+			continue
+		}
+
+		entry := skipEntry{raw: raw}
+		if v, err := scheme.ParseVersion(raw); err == nil {
+			entry.version = v
+		}
+		list = append(list, entry)
+	}
+	return list, nil
+}
+
+// looksLikeConstraint reports whether the value uses constraint syntax (which matters for schemes that accept any
+// string as a version, such as the lexical scheme).
+func looksLikeConstraint(value string) bool {
+	return strings.ContainsAny(value[:1], "<>=!~^") || strings.Contains(value, ",") || strings.Contains(value, "||")
+}
+
+// Contains reports whether the version is on the deny-list. The raw value is matched literally, which allows for
+// skipping references that do not parse as a version.
+func (s SkipList) Contains(raw string, v Version) bool {
+	for _, e := range s {
+		if e.raw == raw {
+			return true
+		}
+		if v == nil {
+			continue
+		}
+		if e.version != nil && e.version.Compare(v) == 0 {
+			return true
+		}
+		if e.constraint != nil && e.constraint.Check(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipConstraint wraps a constraint such that versions on the deny-list never satisfy it.
+type skipConstraint struct {
+	base VersionConstraint
+	skip SkipList
+}
+
+func (s skipConstraint) Check(v Version) bool {
+	if s.skip.Contains(v.Original(), v) {
+		return false
+	}
+	return s.base == nil || s.base.Check(v)
+}
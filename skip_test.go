@@ -0,0 +1,92 @@
+package binny
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkipList_Contains(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		scheme  VersionScheme
+		version string
+		want    bool
+	}{
+		{
+			name:    "exact version",
+			entries: []string{"v1.2.3"},
+			version: "1.2.3",
+			want:    true,
+		},
+		{
+			name:    "constraint",
+			entries: []string{">= v1.2.0, < v1.2.3"},
+			version: "v1.2.2",
+			want:    true,
+		},
+		{
+			name:    "constraint matches prereleases",
+			entries: []string{">= v1.2.0-0, < v1.3.0"},
+			version: "v1.2.0-rc1",
+			want:    true,
+		},
+		{
+			name:    "not on the list",
+			entries: []string{"v1.2.3", "< v1.0.0"},
+			version: "v1.2.4",
+			want:    false,
+		},
+		{
+			name:    "literal reference",
+			entries: []string{"main"},
+			version: "main",
+			want:    true,
+		},
+		{
+			name:    "calver constraint",
+			entries: []string{">= 2024.06, < 2024.07"},
+			scheme:  SchemeCalver,
+			version: "2024.06.15",
+			want:    true,
+		},
+		{
+			name:    "lexical constraint",
+			entries: []string{"< b"},
+			scheme:  SchemeLexical,
+			version: "abc",
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := NewSkipList(tt.entries, tt.scheme)
+			require.NoError(t, err)
+
+			v, err := tt.scheme.ParseVersion(tt.version)
+			if err != nil {
+				v = nil
+			}
+			assert.Equal(t, tt.want, list.Contains(tt.version, v))
+		})
+	}
+}
+
+func TestNewSkipList_invalidConstraint(t *testing.T) {
+	_, err := NewSkipList([]string{">= not-a-version"}, SchemeSemver)
+	require.Error(t, err)
+}
+
+func TestVersionFilter_IsSkipped(t *testing.T) {
+	filter := VersionFilter{Skip: []string{"v1.2.3"}}
+
+	skipped, err := filter.IsSkipped("v1.2.3")
+	require.NoError(t, err)
+	assert.True(t, skipped)
+
+	skipped, err = filter.IsSkipped("v1.2.4")
+	require.NoError(t, err)
+	assert.False(t, skipped)
+}
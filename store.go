@@ -29,9 +29,11 @@ func (e *ErrDigestMismatch) Error() string {
 }
 
 type Store struct {
-	root    string
-	entries []StoreEntry
-	lock    *sync.RWMutex
+	root      string
+	entries   []StoreEntry
+	lock      *sync.RWMutex
+	auditLock *sync.Mutex
+	actor     AuditActor
 }
 
 type state struct {
@@ -44,6 +46,11 @@ type StoreEntry struct {
 	InstalledVersion string            `json:"version"`
 	Digests          map[string]string `json:"digests"`
 	PathInRoot       string            `json:"path"`
+	// ChecksumSource describes what the downloaded files were verified against when installing (e.g. a checksums
+	// file). Empty when nothing was verified.
+	ChecksumSource string `json:"checksumSource,omitempty"`
+	// ModuleHash is the go checksum database hash ("h1:...") of the module source that the tool was built from.
+	ModuleHash string `json:"moduleHash,omitempty"`
 }
 
 func (e StoreEntry) Path() string {
@@ -52,9 +59,10 @@ func (e StoreEntry) Path() string {
 
 func NewStore(root string) (*Store, error) {
 	s := &Store{
-		root:    root,
-		entries: []StoreEntry{},
-		lock:    &sync.RWMutex{},
+		root:      root,
+		entries:   []StoreEntry{},
+		lock:      &sync.RWMutex{},
+		auditLock: &sync.Mutex{},
 	}
 
 	return s, s.loadState()
@@ -119,7 +127,21 @@ func (s Store) Entries() (entries []StoreEntry) {
 	return append(entries, s.entries...)
 }
 
-func (s *Store) AddTool(toolName string, resolvedVersion, pathOutsideRoot string) error {
+// ToolSource describes where a tool being added to the store came from.
+type ToolSource struct {
+	// URL is where the tool was downloaded from (e.g. a release asset or install script), or the go module (or
+	// repository) and version that it was built from.
+	URL string
+	// ChecksumSource describes what the downloaded files were verified against (empty when nothing was verified).
+	ChecksumSource string
+	// ModuleHash is the go checksum database hash ("h1:...") of the module source that the tool was built from.
+	ModuleHash string
+}
+
+// AddTool moves the binary into the store, recording the source of the checksum that the installation was verified
+// against and the hash of the go module it was built from (if any). The change is appended to the audit log once the
+// state is saved, and a failure to write the audit log is only logged.
+func (s *Store) AddTool(toolName string, resolvedVersion, pathOutsideRoot string, source ToolSource) error {
 	log.WithFields("tool", toolName, "from", pathOutsideRoot).Trace("adding tool to store")
 
 	err := s.loadState()
@@ -162,21 +184,54 @@ func (s *Store) AddTool(toolName string, resolvedVersion, pathOutsideRoot string
 		InstalledVersion: resolvedVersion,
 		Digests:          digests,
 		PathInRoot:       targetName, // path in the store relative to the root
+		ChecksumSource:   source.ChecksumSource,
+		ModuleHash:       source.ModuleHash,
+	}
+
+	record := AuditRecord{
+		Action:     AuditActionAdd,
+		Tool:       toolName,
+		NewVersion: resolvedVersion,
+		NewDigests: digests,
+		SourceURL:  source.URL,
 	}
 
 	// if entry name exists, replace it, otherwise add it
+	replaced := false
 	for i, entry := range s.entries {
 		if entry.Name == toolName {
 			log.WithFields("tool", toolName, "sha256", sha256Hash, pathOutsideRoot).Trace("replacing existing tool store entry")
+			record.Action = AuditActionReplace
+			record.OldVersion = entry.InstalledVersion
+			record.OldDigests = entry.Digests
 			s.entries[i] = fileInfo
-			return s.saveState()
+			replaced = true
+			break
 		}
 	}
 
-	log.WithFields("tool", toolName, "sha256", sha256Hash, pathOutsideRoot).Trace("adding new tool store entry")
+	if !replaced {
+		log.WithFields("tool", toolName, "sha256", sha256Hash, pathOutsideRoot).Trace("adding new tool store entry")
+		s.entries = append(s.entries, fileInfo)
+	}
+
+	// the state is saved before the change is audited, so that a failure to write the audit log cannot leave the
+	// new binary in the store with the digests of the old one
+	pruned, err := s.saveState()
+	if err != nil {
+		return err
+	}
 
-	s.entries = append(s.entries, fileInfo)
-	return s.saveState()
+	s.auditOrWarn(record)
+	for _, entry := range pruned {
+		s.auditOrWarn(AuditRecord{
+			Action:     AuditActionPrune,
+			Tool:       entry.Name,
+			OldVersion: entry.InstalledVersion,
+			OldDigests: entry.Digests,
+		})
+	}
+	return nil
 }
 
 func (s *Store) stateFilePath() string {
@@ -218,35 +273,38 @@ func (s *Store) loadState() error {
 	return nil
 }
 
-func (s Store) saveState() error {
+// saveState writes the state of the store, dropping (and returning) the entries whose binary is missing on disk.
+func (s Store) saveState() ([]StoreEntry, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
 	stateFilePath := s.stateFilePath()
 	log.WithFields("path", stateFilePath).Trace("saving state")
 
-	stateFile, err := os.OpenFile(stateFilePath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
-	}
-	defer stateFile.Close()
-
 	var encodeState state
+	var pruned []StoreEntry
 
 	for _, entry := range s.entries {
 		// check if bin exists on disk
 		if _, err := os.Stat(entry.Path()); os.IsNotExist(err) {
 			log.WithFields("name", entry.Name, "path", entry.PathInRoot).Trace("binary missing, removing from store")
+			pruned = append(pruned, entry)
 			continue
 		}
 
 		encodeState.Entries = append(encodeState.Entries, entry)
 	}
 
+	stateFile, err := os.OpenFile(stateFilePath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer stateFile.Close()
+
 	encoder := json.NewEncoder(stateFile)
 	encoder.SetIndent("", "  ")
 
-	return encoder.Encode(encodeState)
+	return pruned, encoder.Encode(encodeState)
 }
 
 func (e *StoreEntry) Verify(useXxh64, useSha256 bool) error {
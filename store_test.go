@@ -251,7 +251,7 @@ func TestStore_AddTool(t *testing.T) {
 	}
 
 	// add the first tool
-	require.NoError(t, store.AddTool(tool1, tool1Intent.Want, tool1OutsideRoot))
+	require.NoError(t, store.AddTool(tool1, tool1Intent.Want, tool1OutsideRoot, ToolSource{}))
 
 	// check that digest is in the store state
 	assertStoreHasString(tool1ExpectedSha)
@@ -267,21 +267,23 @@ func TestStore_AddTool(t *testing.T) {
 	}
 
 	// add the second tool
-	require.Error(t, store.AddTool(tool2, tool2Intent.Want, tool2OutsideRoot))
+	require.Error(t, store.AddTool(tool2, tool2Intent.Want, tool2OutsideRoot, ToolSource{}))
 
 	// create the path and add it again
 	tool2ExpectedSha := "6c607e095402c38173aeb767b4980455249993c4f40450528a3a99ea67f75c35"
 	createFile(tool2OutsideRoot, "nope hello world")
 
-	require.NoError(t, store.AddTool(tool2, tool2Intent.Want, tool2OutsideRoot))
+	require.NoError(t, store.AddTool(tool2, tool2Intent.Want, tool2OutsideRoot, ToolSource{ChecksumSource: `checksums file "checksums.txt"`, ModuleHash: "h1:abc="}))
 
 	assertStoreHasString(tool1ExpectedSha)
 	assertStoreHasString(tool2ExpectedSha)
+	assertStoreHasString(`"checksumSource": "checksums file \"checksums.txt\""`)
+	assertStoreHasString(`"moduleHash": "h1:abc="`)
 
 	// case 3: replace tool 1 /////////////////////////////////////////////////
 	createFile(tool1OutsideRoot, "replace hello world")
 	expectedReplaceSha := "781ac3727fddd802c1f7f540b4cd91398c034dd0bd1eafad808561c189dc0501"
-	require.NoError(t, store.AddTool(tool1, tool1Intent.Want, tool1OutsideRoot))
+	require.NoError(t, store.AddTool(tool1, tool1Intent.Want, tool1OutsideRoot, ToolSource{}))
 
 	assertStoreHasString(expectedReplaceSha)
 	assertStoreHasString(tool2ExpectedSha)
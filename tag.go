@@ -0,0 +1,102 @@
+package binny
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/anchore/binny/internal"
+)
+
+// TagMapping maps between the tags published by a version source (e.g. "cli/v1.2.3" or "release-1.2.3") and the
+// versions used for constraints, comparisons, and pins (e.g. "v1.2.3"). The zero value treats tags and versions as
+// the same thing.
+type TagMapping struct {
+	pattern  *regexp.Regexp
+	group    int
+	template string
+}
+
+// NewTagMapping creates a mapping from a regular expression that extracts the version from a tag and a template that
+// renders a tag from a version (e.g. "cli/{{ .Version }}"). The version is taken from the capture group named
+// "version", or from the first capture group if there is no named group. A pattern requires a template, otherwise
+// the tag of a version could not be recovered (e.g. when installing it).
+func NewTagMapping(pattern, template string) (TagMapping, error) {
+	m := TagMapping{template: template}
+	if pattern == "" {
+		return m, nil
+	}
+	if template == "" {
+		return m, fmt.Errorf("tag pattern %q requires a tag template to render the tag for a version (use %q if tags and versions are the same)", pattern, "{{ .Version }}")
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return m, fmt.Errorf("invalid tag pattern %q: %w", pattern, err)
+	}
+	if re.NumSubexp() == 0 {
+		return m, fmt.Errorf("tag pattern %q must have a capture group for the version", pattern)
+	}
+
+	m.pattern = re
+	m.group = 1
+	if idx := re.SubexpIndex("version"); idx > 0 {
+		m.group = idx
+	}
+	return m, nil
+}
+
+// Version extracts the version from the given tag. When no pattern is configured the tag is returned as-is. The
+// second return value is false when the tag does not match the pattern (and should not be considered a version).
+func (m TagMapping) Version(tag string) (string, bool) {
+	if m.pattern == nil {
+		return tag, true
+	}
+	match := m.pattern.FindStringSubmatch(tag)
+	if match == nil || match[m.group] == "" {
+		return "", false
+	}
+	return match[m.group], true
+}
+
+// Normalize returns the version for the given reference if it is a tag matching the pattern, otherwise the reference
+// is returned unchanged (e.g. it is already a version, or is a keyword like "latest").
+func (m TagMapping) Normalize(ref string) string {
+	if m.pattern == nil || !m.pattern.MatchString(ref) {
+		return ref
+	}
+	if v, ok := m.Version(ref); ok {
+		return v
+	}
+	return ref
+}
+
+// Tag renders the tag for the given version. When no template is configured the version is returned as-is.
+func (m TagMapping) Tag(version string) (string, error) {
+	if m.template == "" {
+		return version, nil
+	}
+	tag, err := internal.TemplateString(m.template, version)
+	if err != nil {
+		return "", fmt.Errorf("unable to render tag template %q: %w", m.template, err)
+	}
+	return tag, nil
+}
+
+// Versions maps each tag to a version, dropping tags that do not match the pattern. The returned map allows for
+// recovering the original tag for a version.
+func (m TagMapping) Versions(tags []string) ([]string, map[string]string) {
+	versions := make([]string, 0, len(tags))
+	tagsByVersion := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		v, ok := m.Version(tag)
+		if !ok {
+			continue
+		}
+		if _, exists := tagsByVersion[v]; exists {
+			continue
+		}
+		versions = append(versions, v)
+		tagsByVersion[v] = tag
+	}
+	return versions, tagsByVersion
+}
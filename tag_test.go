@@ -0,0 +1,157 @@
+package binny
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTagMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		template string
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			name:    "no pattern",
+			pattern: "",
+		},
+		{
+			name:     "pattern with capture group",
+			pattern:  "^cli/(v.*)$",
+			template: "cli/{{ .Version }}",
+		},
+		{
+			name:     "pattern without capture group",
+			pattern:  "^cli/v.*$",
+			template: "cli/{{ .Version }}",
+			wantErr:  require.Error,
+		},
+		{
+			name:     "invalid pattern",
+			pattern:  "^cli/(v.*$",
+			template: "cli/{{ .Version }}",
+			wantErr:  require.Error,
+		},
+		{
+			name:    "pattern without template",
+			pattern: "^cli/(v.*)$",
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			_, err := NewTagMapping(tt.pattern, tt.template)
+			tt.wantErr(t, err)
+		})
+	}
+}
+
+func TestTagMapping_Version(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		tag     string
+		want    string
+		wantOk  bool
+	}{
+		{
+			name:   "no pattern is the identity",
+			tag:    "cli/v1.2.3",
+			want:   "cli/v1.2.3",
+			wantOk: true,
+		},
+		{
+			name:    "monorepo prefix",
+			pattern: "^cli/(v.*)$",
+			tag:     "cli/v1.2.3",
+			want:    "v1.2.3",
+			wantOk:  true,
+		},
+		{
+			name:    "tag for another component",
+			pattern: "^cli/(v.*)$",
+			tag:     "server/v1.2.3",
+			wantOk:  false,
+		},
+		{
+			name:    "named capture group takes precedence",
+			pattern: "^(release|hotfix)-(?P<version>.*)$",
+			tag:     "release-1.2.3",
+			want:    "1.2.3",
+			wantOk:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewTagMapping(tt.pattern, "{{ .Version }}")
+			require.NoError(t, err)
+			got, ok := m.Version(tt.tag)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTagMapping_Normalize(t *testing.T) {
+	m, err := NewTagMapping("^cli/(v.*)$", "cli/{{ .Version }}")
+	require.NoError(t, err)
+
+	assert.Equal(t, "v1.2.3", m.Normalize("cli/v1.2.3"))
+	assert.Equal(t, "v1.2.3", m.Normalize("v1.2.3"))
+	assert.Equal(t, "latest", m.Normalize("latest"))
+}
+
+func TestTagMapping_Tag(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		version  string
+		want     string
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			name:    "no template is the identity",
+			version: "v1.2.3",
+			want:    "v1.2.3",
+		},
+		{
+			name:     "monorepo prefix",
+			template: "cli/{{ .Version }}",
+			version:  "v1.2.3",
+			want:     "cli/v1.2.3",
+		},
+		{
+			name:     "invalid template",
+			template: "cli/{{ .Version }",
+			version:  "v1.2.3",
+			wantErr:  require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			m, err := NewTagMapping("", tt.template)
+			require.NoError(t, err)
+			got, err := m.Tag(tt.version)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTagMapping_Versions(t *testing.T) {
+	m, err := NewTagMapping("^cli/(v.*)$", "cli/{{ .Version }}")
+	require.NoError(t, err)
+
+	versions, tags := m.Versions([]string{"cli/v1.0.0", "server/v2.0.0", "cli/v1.1.0"})
+	assert.Equal(t, []string{"v1.0.0", "v1.1.0"}, versions)
+	assert.Equal(t, map[string]string{"v1.0.0": "cli/v1.0.0", "v1.1.0": "cli/v1.1.0"}, tags)
+}
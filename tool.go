@@ -20,8 +20,68 @@ type VersionResolver interface {
 	UpdateVersion(ctx context.Context, intent VersionIntent) (string, error)
 }
 
+// RetractionChecker is implemented by version resolvers whose source can withdraw published versions (e.g. go module
+// retractions).
+type RetractionChecker interface {
+	// Retraction reports whether the version was withdrawn by its publisher, along with the rationale (if given).
+	Retraction(ctx context.Context, version string) (rationale string, retracted bool, err error)
+}
+
+// LatestStrategyReporter is implemented by version resolvers that have more than one way to choose the latest version.
+type LatestStrategyReporter interface {
+	// ResolveVersionWithStrategy resolves the version (as ResolveVersion does) along with the strategy that produced it. The
+	// strategy may differ from the configured one when it could not be applied, and is empty when the want was not
+	// resolved as "latest".
+	ResolveVersionWithStrategy(ctx context.Context, intent VersionIntent) (string, LatestStrategy, error)
+}
+
+// GitHubReleasesLister is implemented by version resolvers that resolve versions from the releases of a GitHub
+// repository, which can be listed for many tools at once (see githubrelease.WithPrefetchedReleases).
+type GitHubReleasesLister interface {
+	// GitHubRepo returns the repository ("owner/repo") that releases are listed from.
+	GitHubRepo() string
+}
+
+// ChecksumPinner is implemented by installers that can verify against digests pinned in the configuration for each
+// platform (see PinnedChecksums).
+type ChecksumPinner interface {
+	// PinChecksums returns fresh digests of what would be installed for the version on each of the pinned platforms,
+	// using the same hash algorithm as each existing pin.
+	PinChecksums(ctx context.Context, version string, pinned PinnedChecksums) (PinnedChecksums, error)
+}
+
+// ScriptChecksumPinner is implemented by installers that run a downloaded script, which can be pinned to a digest.
+type ScriptChecksumPinner interface {
+	// PinScriptChecksum returns a fresh digest of the script, using the same hash algorithm as the existing pin.
+	PinScriptChecksum(ctx context.Context, pinned string) (string, error)
+}
+
 type VersionIntent struct {
-	Want       string
+	Want string
+	// Ref is the branch or commit that the want was pinned from (if any). Updating moves the want forward to what the
+	// reference resolves to now.
+	Ref        string
 	Constraint string
 	Cooldown   time.Duration
+	Prerelease PrereleasePolicy
+	// Tags maps between the tags published by the version source and the versions used for constraints,
+	// comparisons, and pins. Installers are always given the tag.
+	Tags TagMapping
+	// Scheme determines how versions are ordered and how constraints are interpreted.
+	Scheme VersionScheme
+	// Skip is a deny-list of versions or constraints that must never be resolved.
+	Skip []string
+	// LatestStrategy determines how the latest version is chosen, for resolvers that have more than one way to do so.
+	LatestStrategy LatestStrategy
+}
+
+// Filter returns the version filter that describes which versions are candidates for this intent.
+func (i VersionIntent) Filter() VersionFilter {
+	return VersionFilter{
+		Constraint: i.Constraint,
+		Prerelease: i.Prerelease,
+		Tags:       i.Tags,
+		Scheme:     i.Scheme,
+		Skip:       i.Skip,
+	}
 }
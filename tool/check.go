@@ -1,14 +1,21 @@
 package tool
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/anchore/binny"
+	"github.com/anchore/binny/internal/executable"
+	"github.com/anchore/binny/internal/log"
 )
 
 type VerifyConfig struct {
 	VerifyXXH64Digest  bool
 	VerifySHA256Digest bool
+	// RequireChecksum fails installations that download files without a checksum (or signature) to verify them with.
+	RequireChecksum bool
+	// VerifyLinkage checks that the interpreter and shared libraries that linux binaries need are installed.
+	VerifyLinkage bool
 }
 
 func Check(store *binny.Store, toolName string, resolvedVersion string, verifyConfig VerifyConfig) error {
@@ -25,5 +32,28 @@ func Check(store *binny.Store, toolName string, resolvedVersion string, verifyCo
 		return fmt.Errorf("failed to validate tool %q: %w", toolName, err)
 	}
 
+	if verifyConfig.VerifyLinkage {
+		if err := checkLinkage(toolName, entry.Path(), ""); err != nil {
+			return fmt.Errorf("failed to validate tool %q: %w", toolName, err)
+		}
+	}
+
+	return nil
+}
+
+// checkLinkage fails when the interpreter of the tool binary is not installed on the host, and only warns about
+// missing libraries (since the loader may still find them somewhere that is not searched for them). The hint (if any)
+// is added to either.
+func checkLinkage(toolName, path, hint string) error {
+	err := executable.CheckLinkage(path)
+	var linkErr *executable.LinkageError
+	if !errors.As(err, &linkErr) {
+		return err
+	}
+	linkErr.Hint = hint
+	if linkErr.MissingInterpreter() {
+		return err
+	}
+	log.WithFields("tool", toolName).Warn(err.Error())
 	return nil
 }
@@ -1,6 +1,7 @@
 package tool
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/mitchellh/hashstructure/v2"
@@ -13,9 +14,14 @@ import (
 	"github.com/anchore/binny/tool/goinstall"
 	"github.com/anchore/binny/tool/goproxy"
 	"github.com/anchore/binny/tool/hostedshell"
+	"github.com/anchore/binny/tool/httpapi"
 )
 
 var _ binny.Tool = (*compositeTool)(nil)
+var _ binny.RetractionChecker = (*compositeTool)(nil)
+var _ binny.LatestStrategyReporter = (*compositeTool)(nil)
+var _ binny.ChecksumPinner = (*compositeTool)(nil)
+var _ binny.ScriptChecksumPinner = (*compositeTool)(nil)
 
 type compositeTool struct {
 	config Config
@@ -131,6 +137,12 @@ func getResolver(method string, params any) (resolver binny.VersionResolver, err
 			return nil, fmt.Errorf("invalid git version resolution parameters")
 		}
 		resolver = git.NewVersionResolver(config)
+	case httpapi.IsResolveMethod(method):
+		config, ok := params.(httpapi.VersionResolutionParameters)
+		if !ok {
+			return nil, fmt.Errorf("invalid http version resolution parameters")
+		}
+		resolver = httpapi.NewVersionResolver(method, config)
 	}
 
 	if err != nil {
@@ -144,6 +156,53 @@ func (c compositeTool) Name() string {
 	return c.config.Name
 }
 
+// Retraction reports whether the version was withdrawn upstream, when the version resolver is able to tell.
+func (c compositeTool) Retraction(ctx context.Context, version string) (string, bool, error) {
+	checker, ok := c.VersionResolver.(binny.RetractionChecker)
+	if !ok {
+		return "", false, nil
+	}
+	return checker.Retraction(ctx, version)
+}
+
+// ResolveVersionWithStrategy resolves the version along with the strategy that produced it, when the version resolver
+// is able to tell.
+func (c compositeTool) ResolveVersionWithStrategy(ctx context.Context, intent binny.VersionIntent) (string, binny.LatestStrategy, error) {
+	reporter, ok := c.VersionResolver.(binny.LatestStrategyReporter)
+	if !ok {
+		version, err := c.VersionResolver.ResolveVersion(ctx, intent)
+		return version, "", err
+	}
+	return reporter.ResolveVersionWithStrategy(ctx, intent)
+}
+
+// GitHubRepo returns the repository that versions are resolved from, when the version resolver lists GitHub releases.
+func (c compositeTool) GitHubRepo() string {
+	lister, ok := c.VersionResolver.(binny.GitHubReleasesLister)
+	if !ok {
+		return ""
+	}
+	return lister.GitHubRepo()
+}
+
+// PinChecksums refreshes the pinned digests for the version, when the installer supports pinning.
+func (c compositeTool) PinChecksums(ctx context.Context, version string, pinned binny.PinnedChecksums) (binny.PinnedChecksums, error) {
+	pinner, ok := c.Installer.(binny.ChecksumPinner)
+	if !ok {
+		return nil, fmt.Errorf("the %q install method does not support pinned checksums", c.config.InstallerConfig.Method)
+	}
+	return pinner.PinChecksums(ctx, version, pinned)
+}
+
+// PinScriptChecksum refreshes the pinned digest of the install script, when the installer runs one.
+func (c compositeTool) PinScriptChecksum(ctx context.Context, pinned string) (string, error) {
+	pinner, ok := c.Installer.(binny.ScriptChecksumPinner)
+	if !ok {
+		return "", fmt.Errorf("the %q install method does not run a script", c.config.InstallerConfig.Method)
+	}
+	return pinner.PinScriptChecksum(ctx, pinned)
+}
+
 func (c compositeTool) ID() string {
 	f, err := hashstructure.Hash(c.config, hashstructure.FormatV2, &hashstructure.HashOptions{
 		ZeroNil:      true,
@@ -0,0 +1,36 @@
+package git
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/memory"
+
+	"github.com/anchore/binny/internal/log"
+)
+
+// ListRemoteTags returns the names of all tags advertised by the remote repository at the given URL. No objects
+// are fetched, only the references are listed (similar to "git ls-remote --tags").
+func ListRemoteTags(ctx context.Context, repoURL string) ([]string, error) {
+	log.FromContext(ctx).WithFields("url", repoURL).Trace("listing remote tags")
+
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{repoURL},
+	})
+
+	refs, err := remote.ListContext(ctx, &git.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list remote references for %q: %w", repoURL, err)
+	}
+
+	var tags []string
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			tags = append(tags, ref.Name().Short())
+		}
+	}
+	return tags, nil
+}
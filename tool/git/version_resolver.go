@@ -53,12 +53,21 @@ func (v VersionResolver) ResolveVersion(ctx context.Context, intent binny.Versio
 		return commit, nil
 	}
 
-	ref, err := byReference(v.config.Path, want)
+	tag, err := intent.Tags.Tag(want)
+	if err != nil {
+		return "", err
+	}
+
+	ref, err := byReference(v.config.Path, tag)
 	if err != nil {
 		return "", err
 	}
 
 	if ref != "" {
+		if tag != want {
+			// the version was mapped to a tag, keep the version so that it can be mapped back again at install time
+			return want, nil
+		}
 		// found it!
 		return ref, nil
 	}
@@ -0,0 +1,276 @@
+package githubrelease
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anchore/binny/internal/log"
+)
+
+const githubGraphQLURL = "https://api.github.com/graphql"
+
+// maxReposPerQuery bounds how many repositories are listed by a single GraphQL query. Each repository asks for a
+// page of releases, so this keeps every query well within the GraphQL node limits.
+const maxReposPerQuery = 20
+
+type prefetchedReleasesKey struct{}
+
+// WithPrefetchedReleases fetches the releases of the given repositories ("owner/repo") with batched GraphQL queries
+// (many repositories per query) and returns a context where version resolvers use them instead of querying each
+// repository on their own. Repositories are fetched once no matter how many tools reference them. Repositories that
+// cannot be fetched are left for the version resolvers to query (and report errors for).
+func WithPrefetchedReleases(ctx context.Context, repos []string) context.Context {
+	lgr := log.FromContext(ctx)
+	if len(repos) == 0 {
+		return ctx
+	}
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		lgr.Debug("GITHUB_TOKEN environment variable not set, not prefetching github releases")
+		return ctx
+	}
+
+	releases, err := prefetchReleases(ctx, newRetryableGitHubClient(ctx, token), githubGraphQLURL, repos)
+	if err != nil {
+		lgr.WithFields("error", err).Warn("unable to prefetch github releases, resolving each repository on its own")
+	}
+	if len(releases) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, prefetchedReleasesKey{}, releases)
+}
+
+// prefetchedReleases returns the releases of the repository that were fetched ahead of time (if any).
+func prefetchedReleases(ctx context.Context, user, repo string) ([]ghRelease, bool) {
+	releases, ok := ctx.Value(prefetchedReleasesKey{}).(map[string][]ghRelease)
+	if !ok {
+		return nil, false
+	}
+	r, ok := releases[repoKey(user, repo)]
+	return r, ok
+}
+
+// repoKey identifies a repository regardless of case (as GitHub does).
+func repoKey(user, repo string) string {
+	return strings.ToLower(user + "/" + repo)
+}
+
+// pendingRepo is a repository with more releases to fetch, starting after the cursor (the first page when empty).
+type pendingRepo struct {
+	user, repo string
+	cursor     string
+}
+
+// prefetchReleases lists the releases of every repository, paginating each up to maxReleasesFetched releases (as
+// fetchAllReleasesFromGithubV4API does). Repositories that could not be listed are missing from the result.
+func prefetchReleases(ctx context.Context, client *http.Client, endpoint string, repos []string) (map[string][]ghRelease, error) {
+	var pending []pendingRepo
+	seen := make(map[string]bool)
+	for _, r := range repos {
+		fields := strings.Split(r, "/")
+		if len(fields) != 2 || seen[repoKey(fields[0], fields[1])] {
+			continue
+		}
+		seen[repoKey(fields[0], fields[1])] = true
+		pending = append(pending, pendingRepo{user: fields[0], repo: fields[1]})
+	}
+
+	releases := make(map[string][]ghRelease)
+	for len(pending) > 0 {
+		var next []pendingRepo
+		for start := 0; start < len(pending); start += maxReposPerQuery {
+			batch := pending[start:min(start+maxReposPerQuery, len(pending))]
+
+			pages, err := queryReleasesBatch(ctx, client, endpoint, batch)
+			if err != nil {
+				return completeReleases(releases, pending[start:], next), err
+			}
+
+			for i, p := range batch {
+				page, ok := pages[i]
+				if !ok {
+					// the repository could not be listed (e.g. it does not exist)
+					delete(releases, repoKey(p.user, p.repo))
+					continue
+				}
+				key := repoKey(p.user, p.repo)
+				releases[key] = append(releases[key], page.releases()...)
+				if page.PageInfo.HasNextPage && len(releases[key]) < maxReleasesFetched {
+					next = append(next, pendingRepo{user: p.user, repo: p.repo, cursor: page.PageInfo.EndCursor})
+				}
+			}
+		}
+		pending = next
+	}
+
+	return completeReleases(releases), nil
+}
+
+// completeReleases drops the repositories that still have releases to fetch (since a partial list could resolve the
+// wrong version) and sorts the releases of the rest.
+func completeReleases(releases map[string][]ghRelease, incomplete ...[]pendingRepo) map[string][]ghRelease {
+	for _, repos := range incomplete {
+		for _, p := range repos {
+			delete(releases, repoKey(p.user, p.repo))
+		}
+	}
+	for key := range releases {
+		sortReleasesByDate(releases[key])
+	}
+	return releases
+}
+
+type releasesPage struct {
+	PageInfo struct {
+		EndCursor   string
+		HasNextPage bool
+	}
+	Nodes []struct {
+		TagName      string
+		IsLatest     bool
+		IsDraft      bool
+		IsPrerelease bool
+		PublishedAt  time.Time
+	}
+}
+
+func (p releasesPage) releases() []ghRelease {
+	var releases []ghRelease
+	for _, node := range p.Nodes {
+		publishedAt := node.PublishedAt
+		releases = append(releases, ghRelease{
+			Tag:          node.TagName,
+			IsLatest:     boolRef(node.IsLatest),
+			IsDraft:      boolRef(node.IsDraft),
+			IsPrerelease: boolRef(node.IsPrerelease),
+			Date:         &publishedAt,
+		})
+	}
+	return releases
+}
+
+// queryReleasesBatch fetches a page of releases for every repository with a single query, where each repository is
+// aliased by its index in the batch. Repositories that could not be listed are missing from the result.
+func queryReleasesBatch(ctx context.Context, client *http.Client, endpoint string, batch []pendingRepo) (map[int]releasesPage, error) {
+	body, err := json.Marshal(map[string]string{"query": releasesBatchQuery(batch)})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		content, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected status %s from the GitHub GraphQL API: %s", resp.Status, strings.TrimSpace(string(content)))
+	}
+
+	var result struct {
+		Data   map[string]json.RawMessage
+		Errors []struct {
+			Message string
+			Path    []any
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("unable to decode the GitHub GraphQL API response: %w", err)
+	}
+
+	for _, e := range result.Errors {
+		log.FromContext(ctx).WithFields("path", e.Path).Debugf("github graphql error: %s", e.Message)
+	}
+	if result.Data == nil && len(result.Errors) > 0 {
+		return nil, fmt.Errorf("github graphql query failed: %s", result.Errors[0].Message)
+	}
+
+	if raw, ok := result.Data["rateLimit"]; ok {
+		var rateLimit graphQLRateLimit
+		if err := json.Unmarshal(raw, &rateLimit); err == nil {
+			rateLimit.observe(ctx, req.URL.Hostname())
+		}
+	}
+
+	pages := make(map[int]releasesPage)
+	for i := range batch {
+		raw, ok := result.Data[repoAlias(i)]
+		if !ok {
+			continue
+		}
+		var repo *struct{ Releases releasesPage }
+		if err := json.Unmarshal(raw, &repo); err != nil {
+			return nil, fmt.Errorf("unable to decode the releases of %s/%s: %w", batch[i].user, batch[i].repo, err)
+		}
+		if repo != nil {
+			pages[i] = repo.Releases
+		}
+	}
+	return pages, nil
+}
+
+// releasesBatchQuery builds a query that lists a page of releases for every repository in the batch. Release assets
+// are omitted for the same reason as in fetchAllReleasesFromGithubV4API.
+func releasesBatchQuery(batch []pendingRepo) string {
+	var sb strings.Builder
+	sb.WriteString("query {\n")
+	for i, p := range batch {
+		after := "null"
+		if p.cursor != "" {
+			after = graphQLString(p.cursor)
+		}
+		fmt.Fprintf(&sb, "  %s: repository(owner: %s, name: %s) {\n", repoAlias(i), graphQLString(p.user), graphQLString(p.repo))
+		fmt.Fprintf(&sb, "    releases(first: %d, after: %s) {\n", releasesPerPage, after)
+		sb.WriteString("      pageInfo { endCursor hasNextPage }\n")
+		sb.WriteString("      nodes { tagName isLatest isDraft isPrerelease publishedAt }\n")
+		sb.WriteString("    }\n  }\n")
+	}
+	sb.WriteString("  rateLimit { cost limit remaining resetAt }\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func repoAlias(i int) string {
+	return fmt.Sprintf("r%d", i)
+}
+
+// graphQLString quotes the value as a GraphQL string literal (which shares its escaping rules with JSON).
+func graphQLString(value string) string {
+	quoted, _ := json.Marshal(value)
+	return string(quoted)
+}
+
+// sortReleasesByDate sorts the releases from latest to earliest, with undated releases last.
+func sortReleasesByDate(releases []ghRelease) {
+	sort.SliceStable(releases, func(i, j int) bool {
+		if releases[i].Date == nil && releases[j].Date == nil {
+			return false
+		}
+
+		if releases[i].Date == nil {
+			return false
+		}
+
+		if releases[j].Date == nil {
+			return true
+		}
+
+		return releases[i].Date.After(*releases[j].Date)
+	})
+}
@@ -0,0 +1,170 @@
+package githubrelease
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/binny"
+)
+
+var repoQueryPattern = regexp.MustCompile(`(r\d+): repository\(owner: "([^"]+)", name: "([^"]+)"\) \{\s+releases\(first: \d+, after: (null|"[^"]*")\)`)
+
+// fakeGraphQLServer serves batched release queries for repositories with the given number of releases (one release
+// per day, newest first). Repositories that are not listed do not exist.
+func fakeGraphQLServer(t *testing.T, releaseCounts map[string]int, queries *atomic.Int32) *httptest.Server {
+	t.Helper()
+	base := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries.Add(1)
+
+		var payload struct{ Query string }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+		data := make(map[string]any)
+		var errs []any
+		for _, m := range repoQueryPattern.FindAllStringSubmatch(payload.Query, -1) {
+			alias, repo := m[1], m[2]+"/"+m[3]
+			count, ok := releaseCounts[repo]
+			if !ok {
+				data[alias] = nil
+				errs = append(errs, map[string]any{"type": "NOT_FOUND", "path": []string{alias}, "message": "not found"})
+				continue
+			}
+
+			offset := 0
+			if m[4] != "null" {
+				cursor, err := strconv.Unquote(m[4])
+				require.NoError(t, err)
+				offset, err = strconv.Atoi(cursor)
+				require.NoError(t, err)
+			}
+
+			var nodes []any
+			for i := offset; i < min(offset+releasesPerPage, count); i++ {
+				nodes = append(nodes, map[string]any{
+					"tagName":      fmt.Sprintf("v1.0.%d", count-1-i),
+					"isLatest":     i == 0,
+					"isDraft":      false,
+					"isPrerelease": false,
+					"publishedAt":  base.Add(-time.Duration(i) * 24 * time.Hour).Format(time.RFC3339),
+				})
+			}
+			end := offset + len(nodes)
+			data[alias] = map[string]any{
+				"releases": map[string]any{
+					"pageInfo": map[string]any{"endCursor": strconv.Itoa(end), "hasNextPage": end < count},
+					"nodes":    nodes,
+				},
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data, "errors": errs}))
+	}))
+}
+
+func Test_prefetchReleases(t *testing.T) {
+	var queries atomic.Int32
+	server := fakeGraphQLServer(t, map[string]int{
+		"anchore/syft":  30,
+		"anchore/grype": 3,
+		"anchore/huge":  500,
+	}, &queries)
+	defer server.Close()
+
+	releases, err := prefetchReleases(context.Background(), server.Client(), server.URL, []string{
+		"anchore/syft",
+		"anchore/grype",
+		"Anchore/Syft", // the same repository referenced by another tool
+		"anchore/missing",
+		"anchore/huge",
+	})
+	require.NoError(t, err)
+
+	// one query for the first page of every repository, then a query for each further page of the large ones
+	assert.Equal(t, int32(4), queries.Load())
+
+	require.Len(t, releases, 3)
+	assert.NotContains(t, releases, "anchore/missing")
+
+	syft := releases["anchore/syft"]
+	require.Len(t, syft, 30)
+	assert.Equal(t, "v1.0.29", syft[0].Tag)
+	assert.True(t, *syft[0].IsLatest)
+	assert.Equal(t, "v1.0.0", syft[29].Tag)
+
+	assert.Len(t, releases["anchore/grype"], 3)
+
+	// pagination stops at the same ceiling as listing the releases of a single repository
+	assert.Len(t, releases["anchore/huge"], maxReleasesFetched)
+}
+
+func Test_prefetchReleases_manyRepositories(t *testing.T) {
+	counts := make(map[string]int)
+	var repos []string
+	for i := range maxReposPerQuery + 5 {
+		repo := fmt.Sprintf("org/repo-%d", i)
+		counts[repo] = 1
+		repos = append(repos, repo)
+	}
+
+	var queries atomic.Int32
+	server := fakeGraphQLServer(t, counts, &queries)
+	defer server.Close()
+
+	releases, err := prefetchReleases(context.Background(), server.Client(), server.URL, repos)
+	require.NoError(t, err)
+	assert.Len(t, releases, len(repos))
+	assert.Equal(t, int32(2), queries.Load())
+}
+
+func Test_prefetchReleases_failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	releases, err := prefetchReleases(context.Background(), server.Client(), server.URL, []string{"anchore/syft"})
+	require.ErrorContains(t, err, "502")
+	assert.Empty(t, releases)
+}
+
+func TestVersionResolver_prefetchedReleases(t *testing.T) {
+	date := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.WithValue(context.Background(), prefetchedReleasesKey{}, map[string][]ghRelease{
+		"anchore/binny": {
+			{Tag: "v2.0.0", IsLatest: boolRef(true), Date: &date},
+			{Tag: "v1.1.0", Date: &date},
+		},
+	})
+
+	resolver := NewVersionResolver(VersionResolutionParameters{Repo: "Anchore/binny"})
+	resolver.latestReleaseFetcher = func(context.Context, string, string) (*ghRelease, error) {
+		t.Fatal("should not have been called")
+		return nil, nil
+	}
+	resolver.releasesFetcher = func(context.Context, string, string) ([]ghRelease, error) {
+		t.Fatal("should not have been called")
+		return nil, nil
+	}
+
+	got, err := resolver.ResolveVersion(ctx, binny.VersionIntent{Want: "latest"})
+	require.NoError(t, err)
+	assert.Equal(t, "v2.0.0", got)
+
+	got, err = resolver.UpdateVersion(ctx, binny.VersionIntent{Want: "v1.0.0", Constraint: "< v2"})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.1.0", got)
+}
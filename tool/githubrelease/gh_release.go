@@ -7,11 +7,12 @@ import (
 )
 
 type ghRelease struct {
-	Tag      string
-	Date     *time.Time
-	IsLatest *bool
-	IsDraft  *bool
-	Assets   []ghAsset
+	Tag          string
+	Date         *time.Time
+	IsLatest     *bool
+	IsDraft      *bool
+	IsPrerelease *bool
+	Assets       []ghAsset
 }
 
 type ghAsset struct {
@@ -19,6 +20,8 @@ type ghAsset struct {
 	ContentType string
 	URL         string
 	Checksum    string
+	// PinnedChecksum is the digest pinned in the configuration for the platform, which takes precedence over Checksum.
+	PinnedChecksum string
 }
 
 func (a *ghAsset) addChecksum(value string) {
@@ -19,6 +19,7 @@ import (
 
 	"github.com/anchore/binny"
 	"github.com/anchore/binny/internal"
+	"github.com/anchore/binny/internal/executable"
 	"github.com/anchore/binny/internal/log"
 )
 
@@ -83,9 +84,13 @@ var binaryMimeTypes = strset.New(
 var _ binny.Installer = (*Installer)(nil)
 
 type InstallerParameters struct {
-	Binary string `json:"binary" yaml:"binary" mapstructure:"binary"`
-	Repo   string `json:"repo" yaml:"repo" mapstructure:"repo"`
-	Assets any    `json:"assets" yaml:"assets" mapstructure:"assets"`
+	Binary string           `json:"binary" yaml:"binary" mapstructure:"binary"`
+	Repo   string           `json:"repo" yaml:"repo" mapstructure:"repo"`
+	Assets any              `json:"assets" yaml:"assets" mapstructure:"assets"`
+	Verify VerifyParameters `json:"verify" yaml:"verify,omitempty" mapstructure:"verify"`
+	// Checksums pins the digest of the asset for each platform (e.g. "linux/amd64"), for releases without a checksums
+	// file. These are refreshed by "binny update".
+	Checksums binny.PinnedChecksums `json:"checksums" yaml:"checksums,omitempty" mapstructure:"checksums"`
 }
 
 type Installer struct {
@@ -144,15 +149,9 @@ func (i Installer) InstallTo(ctx context.Context, version, destDir string) (stri
 
 	lgr.Debug("installing from github release assets")
 
-	fields := strings.Split(i.config.Repo, "/")
-	if len(fields) != 2 {
-		return "", fmt.Errorf("invalid github repo format: %q", i.config.Repo)
-	}
-	user, repo := fields[0], fields[1]
-
-	release, err := i.releaseFetcher(ctx, user, repo, version)
+	release, err := i.fetchRelease(ctx, version)
 	if err != nil {
-		return "", fmt.Errorf("unable to fetch github release %s@%s: %w", i.config.Repo, version, err)
+		return "", err
 	}
 
 	asset := selectBinaryAsset(ctx, release.Assets, runtime.GOOS, runtime.GOARCH, i.assetPatterns)
@@ -160,35 +159,108 @@ func (i Installer) InstallTo(ctx context.Context, version, destDir string) (stri
 		return "", fmt.Errorf("unable to find matching asset for %s@%s", i.config.Repo, version)
 	}
 
+	if err := i.config.Checksums.Validate(); err != nil {
+		return "", fmt.Errorf("invalid checksums for %s: %w", i.config.Repo, err)
+	}
+	asset.PinnedChecksum, _ = i.config.Checksums.ForPlatform(runtime.GOOS, runtime.GOARCH)
+
 	checksumAsset := selectChecksumAsset(ctx, release.Assets)
 
-	binPath, err := downloadAndExtractAsset(ctx, *asset, checksumAsset, destDir, i.config.Binary)
+	verifier, err := newSignatureVerifier(i.config.Verify, release.Assets)
+	if err != nil {
+		return "", fmt.Errorf("invalid signature verification config for %s: %w", i.config.Repo, err)
+	}
+
+	prov, err := newProvenanceVerifier(i.config.Verify.Provenance, release.Assets, version)
+	if err != nil {
+		return "", fmt.Errorf("invalid provenance verification config for %s: %w", i.config.Repo, err)
+	}
+
+	binPath, err := downloadAndExtractAsset(ctx, *asset, checksumAsset, destDir, i.config.Binary, verifier, prov)
 	if err != nil {
 		return "", fmt.Errorf("unable to download and extract asset %s@%s: %w", i.config.Repo, version, err)
 	}
 
+	// suggest another asset for when the linkage is checked and the installed one cannot run (unless the asset was
+	// explicitly selected)
+	if len(i.assetPatterns) == 0 {
+		if variant := libcVariantAsset(ctx, release.Assets, *asset, runtime.GOOS, runtime.GOARCH); variant != nil {
+			executable.LinkageHintFromContext(ctx).Set(fmt.Sprintf("the release also has the asset %q, which can be selected with the 'assets' option", variant.Name))
+		}
+	}
+
 	return binPath, nil
 }
 
-func downloadAndExtractAsset(ctx context.Context, asset ghAsset, checksumAsset *ghAsset, destDir string, binary string) (string, error) {
-	lgr := log.FromContext(ctx)
-	assetPath := filepath.Join(destDir, asset.Name)
+func (i Installer) fetchRelease(ctx context.Context, version string) (*ghRelease, error) {
+	fields := strings.Split(i.config.Repo, "/")
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid github repo format: %q", i.config.Repo)
+	}
+	user, repo := fields[0], fields[1]
 
-	checksum := asset.Checksum
-	if checksumAsset != nil && checksum == "" {
-		lgr.WithFields("asset", checksumAsset.Name).Trace("downloading checksum manifest")
+	release, err := i.releaseFetcher(ctx, user, repo, version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch github release %s@%s: %w", i.config.Repo, version, err)
+	}
+	return release, nil
+}
+
+// PinChecksums downloads the asset that would be installed on each of the pinned platforms and returns their digests.
+func (i Installer) PinChecksums(ctx context.Context, version string, pinned binny.PinnedChecksums) (binny.PinnedChecksums, error) {
+	if err := pinned.Validate(); err != nil {
+		return nil, err
+	}
 
-		checksumsPath := filepath.Join(destDir, checksumsFilename)
+	release, err := i.fetchRelease(ctx, version)
+	if err != nil {
+		return nil, err
+	}
 
-		if err := internal.DownloadFile(ctx, checksumAsset.URL, checksumsPath, ""); err != nil {
-			return "", fmt.Errorf("unable to download checksum asset %q: %w", checksumAsset.Name, err)
+	refreshed := binny.PinnedChecksums{}
+	for _, platform := range pinned.Platforms() {
+		goos, goarch, _ := binny.SplitPlatform(platform)
+
+		asset := selectBinaryAsset(ctx, release.Assets, goos, goarch, i.assetPatterns)
+		if asset == nil {
+			return nil, fmt.Errorf("unable to find matching asset for %s@%s on %s", i.config.Repo, version, platform)
 		}
 
-		var err error
-		checksum, err = getChecksumForAsset(asset.Name, checksumsPath)
+		digest, err := digestAsset(ctx, *asset, pinned[platform])
 		if err != nil {
-			return "", fmt.Errorf("unable to get checksum for asset %q: %w", asset.Name, err)
+			return nil, err
 		}
+		refreshed[platform] = digest
+	}
+	return refreshed, nil
+}
+
+// digestAsset downloads the asset and hashes it with the same algorithm as the given checksum.
+func digestAsset(ctx context.Context, asset ghAsset, like string) (string, error) {
+	reader, err := internal.DownloadURL(ctx, asset.URL)
+	if err != nil {
+		return "", fmt.Errorf("unable to download asset %q: %w", asset.Name, err)
+	}
+	defer reader.Close()
+
+	digest, err := internal.Digest(reader, like)
+	if err != nil {
+		return "", fmt.Errorf("unable to hash asset %q: %w", asset.Name, err)
+	}
+	return digest, nil
+}
+
+// downloadAndExtractAsset downloads the asset (verifying its checksum when known) and returns the path to the binary.
+// When a verifier is given, the signature of the checksums file is verified (which covers the asset through its
+// checksum), falling back to the signature of the asset itself. When a provenance verifier is given, the asset must
+// have provenance matching the configuration.
+func downloadAndExtractAsset(ctx context.Context, asset ghAsset, checksumAsset *ghAsset, destDir string, binary string, verifier *signatureVerifier, prov *provenanceVerifier) (string, error) {
+	lgr := log.FromContext(ctx)
+	assetPath := filepath.Join(destDir, asset.Name)
+
+	checksum, signed, err := assetChecksum(ctx, asset, checksumAsset, destDir, verifier)
+	if err != nil {
+		return "", err
 	}
 
 	logFields := map[string]any{
@@ -204,6 +276,11 @@ func downloadAndExtractAsset(ctx context.Context, asset ghAsset, checksumAsset *
 	if err := internal.DownloadFile(ctx, asset.URL, assetPath, checksum); err != nil {
 		return "", fmt.Errorf("unable to download asset %q: %w", asset.Name, err)
 	}
+	internal.ChecksumRecordFromContext(ctx).SetSourceURL(asset.URL)
+
+	if err := verifyDownloadedAsset(ctx, asset, checksumAsset, assetPath, checksum, signed, verifier, prov); err != nil {
+		return "", err
+	}
 
 	// check if it exists
 	v, err := os.Stat(assetPath)
@@ -225,6 +302,128 @@ func downloadAndExtractAsset(ctx context.Context, asset ghAsset, checksumAsset *
 	return "", fmt.Errorf("unsupported asset content-type: %q", asset.ContentType)
 }
 
+// assetChecksum determines the checksum to download the asset with, along with whether it is covered by a verified
+// signature of the checksums file. A pinned checksum always takes precedence (but must agree with the checksums file),
+// followed by a checksum from a signed checksums file.
+func assetChecksum(ctx context.Context, asset ghAsset, checksumAsset *ghAsset, destDir string, verifier *signatureVerifier) (string, bool, error) {
+	checksum := asset.Checksum
+	if checksumAsset == nil || (checksum != "" && verifier == nil && asset.PinnedChecksum == "") {
+		return firstNonEmpty(asset.PinnedChecksum, checksum), false, nil
+	}
+
+	manifestChecksum, signed, err := checksumFromManifest(ctx, asset, *checksumAsset, destDir, verifier)
+	if err != nil {
+		return "", false, err
+	}
+
+	if asset.PinnedChecksum != "" {
+		if manifestChecksum != "" && internal.ChecksumsConflict(asset.PinnedChecksum, manifestChecksum) {
+			return "", false, fmt.Errorf("pinned checksum %q for asset %q does not match %q from %q", asset.PinnedChecksum, asset.Name, manifestChecksum, checksumAsset.Name)
+		}
+		return asset.PinnedChecksum, signed && manifestChecksum != "" && !internal.ChecksumsConflict(asset.PinnedChecksum, manifestChecksum), nil
+	}
+
+	// a checksum from a signed manifest always takes precedence
+	if checksum == "" || signed {
+		return manifestChecksum, signed, nil
+	}
+	return checksum, false, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// verifyDownloadedAsset checks the signature and provenance of the downloaded asset (as configured) and records what
+// the asset was verified against, which fails the installation when nothing verified it and checksums are required.
+func verifyDownloadedAsset(ctx context.Context, asset ghAsset, checksumAsset *ghAsset, assetPath, checksum string, signed bool, verifier *signatureVerifier, prov *provenanceVerifier) error {
+	record := internal.ChecksumRecordFromContext(ctx)
+
+	if asset.PinnedChecksum != "" {
+		record.Verified("pinned checksum")
+	}
+	if checksum != "" && (asset.PinnedChecksum == "" || signed) {
+		record.Verified(checksumSource(checksumAsset, signed))
+	}
+
+	if verifier != nil && !signed {
+		assetSigned, err := verifyAssetSignature(ctx, verifier, asset, checksumAsset, assetPath)
+		if err != nil {
+			return err
+		}
+		if assetSigned {
+			record.Verified(fmt.Sprintf("signature for %q", asset.Name))
+		}
+	}
+
+	if prov != nil {
+		if err := prov.verify(ctx, asset, assetPath); err != nil {
+			return err
+		}
+		record.Verified(fmt.Sprintf("provenance for %q", asset.Name))
+	}
+
+	if record.Source() == "" {
+		return record.Missing(fmt.Sprintf("github release asset %q (no checksums file lists it)", asset.Name))
+	}
+	return nil
+}
+
+// checksumSource describes where the checksum of the asset came from.
+func checksumSource(checksumAsset *ghAsset, signed bool) string {
+	if checksumAsset == nil {
+		return "release checksums"
+	}
+	if signed {
+		return fmt.Sprintf("signed checksums file %q", checksumAsset.Name)
+	}
+	return fmt.Sprintf("checksums file %q", checksumAsset.Name)
+}
+
+// checksumFromManifest downloads the checksums file and returns the checksum it lists for the asset, along with whether
+// the checksums file has a verified signature (only when a verifier is given and the asset is listed).
+func checksumFromManifest(ctx context.Context, asset, checksumAsset ghAsset, destDir string, verifier *signatureVerifier) (string, bool, error) {
+	log.FromContext(ctx).WithFields("asset", checksumAsset.Name).Trace("downloading checksum manifest")
+
+	checksumsPath := filepath.Join(destDir, checksumsFilename)
+
+	if err := internal.DownloadFile(ctx, checksumAsset.URL, checksumsPath, ""); err != nil {
+		return "", false, fmt.Errorf("unable to download checksum asset %q: %w", checksumAsset.Name, err)
+	}
+
+	checksum, err := getChecksumForAsset(asset.Name, checksumsPath)
+	if err != nil {
+		return "", false, fmt.Errorf("unable to get checksum for asset %q: %w", asset.Name, err)
+	}
+
+	if verifier == nil || checksum == "" {
+		return checksum, false, nil
+	}
+
+	signed, err := verifier.verify(ctx, checksumAsset, checksumsPath)
+	return checksum, signed, err
+}
+
+// verifyAssetSignature verifies the signature of the downloaded asset itself (used when the checksums file is not
+// signed), reporting whether the asset had a signature.
+func verifyAssetSignature(ctx context.Context, verifier *signatureVerifier, asset ghAsset, checksumAsset *ghAsset, assetPath string) (bool, error) {
+	signed, err := verifier.verify(ctx, asset, assetPath)
+	if err != nil || signed {
+		return signed, err
+	}
+
+	names := []string{asset.Name}
+	if checksumAsset != nil {
+		names = append([]string{checksumAsset.Name}, names...)
+	}
+	return false, verifier.unsigned(ctx, names...)
+}
+
 func isArchiveAsset(asset ghAsset) bool {
 	if archiveMimeTypes.Has(asset.ContentType) {
 		return true
@@ -263,7 +462,8 @@ func getChecksumForAsset(assetName, checksumsPath string) (string, error) {
 		if len(fields) != 2 {
 			return "", fmt.Errorf("invalid checksum line: %q", line)
 		}
-		if fields[1] == assetName {
+		// "sha256sum --binary" marks names with a "*"
+		if strings.TrimPrefix(fields[1], "*") == assetName {
 			return fields[0], nil
 		}
 	}
@@ -406,7 +606,7 @@ func selectChecksumAsset(ctx context.Context, assets []ghAsset) *ghAsset {
 
 		lowerName := strings.ToLower(asset.Name)
 
-		if !strings.HasSuffix(lowerName, checksumsFilename) {
+		if !strings.HasSuffix(lowerName, checksumsFilename) && !strings.HasSuffix(lowerName, "sha256sums") && !strings.HasSuffix(lowerName, "sha256sums.txt") {
 			lgr.WithFields("asset", asset.Name).Trace("skipping asset (name does not indicate checksums)")
 			continue
 		}
@@ -481,8 +681,39 @@ func flattenAliases(aliases map[string][]string) []string {
 
 func selectBinaryAsset(ctx context.Context, assets []ghAsset, goOS, goArch string, assetPatterns []*regexp.Regexp) *ghAsset {
 	lgr := log.FromContext(ctx)
-	// search for the asset by name with the OS and arch in the name
-	// e.g. chronicle_0.7.0_linux_amd64.tar.gz
+
+	osArchCandidates := selectOSArchAssets(ctx, assets, goOS, goArch)
+	if len(osArchCandidates) == 0 {
+		return nil
+	}
+
+	// second pass: apply regex patterns if provided
+	if len(assetPatterns) == 0 {
+		// no asset patterns specified, return first matching asset
+		selectedAsset := &osArchCandidates[0]
+		lgr.WithFields("asset", selectedAsset.Name).Trace("found asset (no pattern filtering)")
+		return selectedAsset
+	}
+
+	// try each pattern in order until we find a match
+	for _, pattern := range assetPatterns {
+		for _, candidate := range osArchCandidates {
+			if pattern.MatchString(candidate.Name) {
+				lgr.WithFields("asset", candidate.Name, "pattern", pattern.String()).Trace("found asset (pattern matched)")
+				return &candidate
+			}
+		}
+	}
+
+	// no pattern matched
+	lgr.Trace("no asset matched any of the specified patterns")
+	return nil
+}
+
+// selectOSArchAssets returns the binary and archive assets for the OS and architecture (by name, e.g.
+// chronicle_0.7.0_linux_amd64.tar.gz).
+func selectOSArchAssets(ctx context.Context, assets []ghAsset, goOS, goArch string) []ghAsset {
+	lgr := log.FromContext(ctx)
 
 	goos := strings.ToLower(goOS)
 	gooss := allOSs(goos)
@@ -520,30 +751,22 @@ func selectBinaryAsset(ctx context.Context, assets []ghAsset, goOS, goArch strin
 		osArchCandidates = append(osArchCandidates, asset)
 	}
 
-	if len(osArchCandidates) == 0 {
-		return nil
-	}
+	return osArchCandidates
+}
 
-	// second pass: apply regex patterns if provided
-	if len(assetPatterns) == 0 {
-		// no asset patterns specified, return first matching asset
-		selectedAsset := &osArchCandidates[0]
-		lgr.WithFields("asset", selectedAsset.Name).Trace("found asset (no pattern filtering)")
-		return selectedAsset
+// libcVariantAsset returns an asset for the same OS and architecture as the selected asset that is built against musl
+// or statically linked (when the selected asset is not), which is more likely to run on hosts that are missing the
+// libraries the selected asset needs.
+func libcVariantAsset(ctx context.Context, assets []ghAsset, selected ghAsset, goOS, goArch string) *ghAsset {
+	variants := []string{"musl", "static"}
+	if containsOneOf(normalizedAssetName(selected.Name), variants) {
+		return nil
 	}
-
-	// try each pattern in order until we find a match
-	for _, pattern := range assetPatterns {
-		for _, candidate := range osArchCandidates {
-			if pattern.MatchString(candidate.Name) {
-				lgr.WithFields("asset", candidate.Name, "pattern", pattern.String()).Trace("found asset (pattern matched)")
-				return &candidate
-			}
+	for _, candidate := range selectOSArchAssets(ctx, assets, goOS, goArch) {
+		if containsOneOf(normalizedAssetName(candidate.Name), variants) {
+			return &candidate
 		}
 	}
-
-	// no pattern matched
-	lgr.Trace("no asset matched any of the specified patterns")
 	return nil
 }
 
@@ -835,14 +1058,6 @@ func fetchReleaseGithubV4API(ctx context.Context, user, repo, tag string) (*ghRe
 
 	client := githubv4.NewClient(newRetryableGitHubClient(ctx, token))
 
-	// TODO: act on hitting a rate limit
-	type rateLimit struct {
-		Cost      githubv4.Int
-		Limit     githubv4.Int
-		Remaining githubv4.Int
-		ResetAt   githubv4.DateTime
-	}
-
 	var query struct {
 		Repository struct {
 			DatabaseID githubv4.Int
@@ -851,6 +1066,7 @@ func fetchReleaseGithubV4API(ctx context.Context, user, repo, tag string) (*ghRe
 				TagName       githubv4.String
 				IsLatest      githubv4.Boolean
 				IsDraft       githubv4.Boolean
+				IsPrerelease  githubv4.Boolean
 				PublishedAt   githubv4.DateTime
 				ReleaseAssets struct {
 					PageInfo struct {
@@ -866,7 +1082,7 @@ func fetchReleaseGithubV4API(ctx context.Context, user, repo, tag string) (*ghRe
 			} `graphql:"release(tagName:$tagName)"`
 		} `graphql:"repository(owner:$repositoryOwner, name:$repositoryName)"`
 
-		RateLimit rateLimit
+		RateLimit graphQLRateLimit
 	}
 	variables := map[string]any{
 		"repositoryOwner": githubv4.String(user),
@@ -879,6 +1095,7 @@ func fetchReleaseGithubV4API(ctx context.Context, user, repo, tag string) (*ghRe
 	if err != nil {
 		return nil, err
 	}
+	query.RateLimit.observe(ctx, githubAPIHost)
 
 	var assets []ghAsset
 
@@ -902,10 +1119,11 @@ func fetchReleaseGithubV4API(ctx context.Context, user, repo, tag string) (*ghRe
 	// }
 
 	return &ghRelease{
-		Tag:      string(query.Repository.Release.TagName),
-		IsLatest: boolRef(bool(query.Repository.Release.IsLatest)),
-		IsDraft:  boolRef(bool(query.Repository.Release.IsDraft)),
-		Date:     &query.Repository.Release.PublishedAt.Time,
-		Assets:   assets,
+		Tag:          string(query.Repository.Release.TagName),
+		IsLatest:     boolRef(bool(query.Repository.Release.IsLatest)),
+		IsDraft:      boolRef(bool(query.Repository.Release.IsDraft)),
+		IsPrerelease: boolRef(bool(query.Repository.Release.IsPrerelease)),
+		Date:         &query.Repository.Release.PublishedAt.Time,
+		Assets:       assets,
 	}, nil
 }
@@ -19,6 +19,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/binny/internal/executable"
 )
 
 func TestInstaller_InstallTo(t *testing.T) {
@@ -367,6 +369,26 @@ func Test_selectChecksumAsset(t *testing.T) {
 				URL:         "http://localhost:8080/chronicle_0.7.0_checksums.txt",
 			},
 		},
+		{
+			name: "select SHA256SUMS but not its signature",
+			assets: []ghAsset{
+				{
+					Name:        "SHA256SUMS.asc",
+					ContentType: "text/plain",
+					URL:         "http://localhost:8080/SHA256SUMS.asc",
+				},
+				{
+					Name:        "SHA256SUMS",
+					ContentType: "text/plain",
+					URL:         "http://localhost:8080/SHA256SUMS",
+				},
+			},
+			want: &ghAsset{
+				Name:        "SHA256SUMS",
+				ContentType: "text/plain",
+				URL:         "http://localhost:8080/SHA256SUMS",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1216,3 +1238,110 @@ func Test_selectBinaryAsset_withRegexPatterns(t *testing.T) {
 		})
 	}
 }
+
+func Test_libcVariantAsset(t *testing.T) {
+	gnu := ghAsset{Name: "tool_1.0.0_linux_amd64_gnu.tar.gz", ContentType: "application/gzip"}
+	musl := ghAsset{Name: "tool_1.0.0_linux_amd64_musl.tar.gz", ContentType: "application/gzip"}
+	static := ghAsset{Name: "tool-1.0.0-x86_64-linux-static.tar.gz", ContentType: "application/gzip"}
+	otherArch := ghAsset{Name: "tool_1.0.0_linux_arm64_musl.tar.gz", ContentType: "application/gzip"}
+
+	tests := []struct {
+		name     string
+		assets   []ghAsset
+		selected ghAsset
+		want     string
+	}{
+		{
+			name:     "musl variant",
+			assets:   []ghAsset{gnu, otherArch, musl},
+			selected: gnu,
+			want:     musl.Name,
+		},
+		{
+			name:     "static variant",
+			assets:   []ghAsset{gnu, static},
+			selected: gnu,
+			want:     static.Name,
+		},
+		{
+			name:     "selected asset is already a variant",
+			assets:   []ghAsset{gnu, musl},
+			selected: musl,
+		},
+		{
+			name:     "no variant for the architecture",
+			assets:   []ghAsset{gnu, otherArch},
+			selected: gnu,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := libcVariantAsset(context.Background(), tt.assets, tt.selected, "linux", "amd64")
+			if tt.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want, got.Name)
+		})
+	}
+}
+
+func TestInstaller_InstallTo_linkageHint(t *testing.T) {
+	binaryPath := filepath.Join("testdata", "archive-contents", "flat", "syft")
+	binaryAssetName := fmt.Sprintf("syft_1.0.0_%s_%s", runtime.GOOS, runtime.GOARCH)
+	muslAssetName := binaryAssetName + "_musl"
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		by, err := os.ReadFile(binaryPath)
+		require.NoError(t, err)
+		_, err = w.Write(by)
+		require.NoError(t, err)
+	}))
+	t.Cleanup(s.Close)
+
+	tests := []struct {
+		name     string
+		assets   []string
+		patterns []string
+		wantHint string
+	}{
+		{
+			name:     "release with a musl variant",
+			assets:   []string{binaryAssetName, muslAssetName},
+			wantHint: fmt.Sprintf("the release also has the asset %q, which can be selected with the 'assets' option", muslAssetName),
+		},
+		{
+			name:     "explicitly selected asset",
+			assets:   []string{binaryAssetName, muslAssetName},
+			patterns: []string{binaryAssetName + "$"},
+		},
+		{
+			name:   "release without a variant",
+			assets: []string{binaryAssetName},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var assets []ghAsset
+			for _, name := range tt.assets {
+				assets = append(assets, ghAsset{Name: name, ContentType: "application/octet-stream", URL: s.URL + "/" + name})
+			}
+
+			var patterns any
+			if tt.patterns != nil {
+				patterns = tt.patterns
+			}
+			i := NewInstaller(InstallerParameters{Repo: "anchore/syft", Assets: patterns})
+			i.releaseFetcher = func(_ context.Context, _, _, tag string) (*ghRelease, error) {
+				return &ghRelease{Tag: tag, Assets: assets}, nil
+			}
+
+			ctx, hint := executable.WithLinkageHint(context.Background())
+			got, err := i.InstallTo(ctx, "1.0.0", t.TempDir())
+			require.NoError(t, err)
+			assert.Equal(t, binaryAssetName, filepath.Base(got))
+			assert.Equal(t, tt.wantHint, hint.String())
+		})
+	}
+}
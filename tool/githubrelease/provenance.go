@@ -0,0 +1,109 @@
+package githubrelease
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anchore/binny/internal/log"
+	"github.com/anchore/binny/internal/provenance"
+	"github.com/anchore/binny/internal/sigstore"
+)
+
+// attestationSuffixes are the names of release assets holding provenance attestations (e.g. "syft.intoto.jsonl" from
+// the SLSA GitHub generator, or a downloaded GitHub attestation bundle).
+var attestationSuffixes = []string{".intoto.jsonl", ".attestation.jsonl", ".attestation.json"}
+
+// provenanceVerifier verifies the provenance attestations published for the assets of a release.
+type provenanceVerifier struct {
+	verifier *provenance.Verifier
+	assets   []ghAsset
+	tag      string
+}
+
+// newProvenanceVerifier returns nil when no provenance verification is configured.
+func newProvenanceVerifier(cfg provenance.Config, assets []ghAsset, tag string) (*provenanceVerifier, error) {
+	if !cfg.IsSet() {
+		return nil, nil
+	}
+
+	verifier, err := cfg.Verifier()
+	if err != nil {
+		return nil, err
+	}
+
+	return &provenanceVerifier{
+		verifier: verifier,
+		assets:   assets,
+		tag:      tag,
+	}, nil
+}
+
+// verify checks that the downloaded target asset has provenance matching the configuration. Unlike signatures,
+// provenance is always required once configured.
+func (v *provenanceVerifier) verify(ctx context.Context, target ghAsset, path string) error {
+	lgr := log.FromContext(ctx).WithFields("asset", target.Name)
+
+	attestations, err := v.fetchAttestations(ctx, target.Name)
+	if err != nil {
+		return err
+	}
+	if len(attestations) == 0 {
+		return fmt.Errorf("provenance verification is configured but no attestation was found for %q", target.Name)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := v.verifier.Verify(contents, attestations, v.tag); err != nil {
+		return fmt.Errorf("unable to verify provenance for %q: %w", target.Name, err)
+	}
+
+	lgr.Debug("provenance verified")
+	return nil
+}
+
+// fetchAttestations downloads the attestations for the named asset. Attestations named after the asset are preferred,
+// otherwise all attestations of the release are considered (e.g. a single "multiple.intoto.jsonl" covering every asset).
+func (v *provenanceVerifier) fetchAttestations(ctx context.Context, name string) ([]sigstore.Attestation, error) {
+	candidates := v.findAttestationAssets(name)
+
+	var attestations []sigstore.Attestation
+	for _, asset := range candidates {
+		contents, err := fetchSignatureAsset(ctx, asset)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := sigstore.ParseAttestations(contents)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attestation %q: %w", asset.Name, err)
+		}
+		attestations = append(attestations, parsed...)
+	}
+	return attestations, nil
+}
+
+func (v *provenanceVerifier) findAttestationAssets(name string) []ghAsset {
+	var named, other []ghAsset
+	for _, asset := range v.assets {
+		for _, suffix := range attestationSuffixes {
+			if !strings.HasSuffix(asset.Name, suffix) {
+				continue
+			}
+			if asset.Name == name+suffix {
+				named = append(named, asset)
+			} else {
+				other = append(other, asset)
+			}
+			break
+		}
+	}
+
+	if len(named) > 0 {
+		return named
+	}
+	return other
+}
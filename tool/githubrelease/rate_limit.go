@@ -0,0 +1,76 @@
+package githubrelease
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	internalhttp "github.com/anchore/binny/internal/http"
+)
+
+const githubRateLimitURL = "https://api.github.com/rate_limit"
+
+// rateLimitResources are the GitHub API rate limits that binny requests count against.
+var rateLimitResources = []string{"core", "graphql"}
+
+// FetchRateLimits reports the state of the GitHub API rate limits that binny uses, for the credentials in the
+// GITHUB_TOKEN environment variable (or for unauthenticated requests when it is not set). Checking the rate limits
+// does not count against them.
+func FetchRateLimits(ctx context.Context) ([]internalhttp.RateLimit, error) {
+	client := internalhttp.ClientFromContext(ctx).StandardClient()
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		client = newRetryableGitHubClient(ctx, token)
+	}
+	return fetchRateLimits(ctx, client, githubRateLimitURL)
+}
+
+func fetchRateLimits(ctx context.Context, client *http.Client, url string) ([]internalhttp.RateLimit, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %q", resp.Status, url)
+	}
+
+	var doc struct {
+		Resources map[string]struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Reset     int64 `json:"reset"`
+		} `json:"resources"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("unable to decode response from %q: %w", url, err)
+	}
+
+	tracker := internalhttp.RateLimitsFromContext(ctx)
+	var limits []internalhttp.RateLimit
+	for _, resource := range rateLimitResources {
+		state, ok := doc.Resources[resource]
+		if !ok {
+			continue
+		}
+		limit := internalhttp.RateLimit{
+			Host:      req.URL.Hostname(),
+			Resource:  resource,
+			Limit:     state.Limit,
+			Remaining: state.Remaining,
+			Reset:     time.Unix(state.Reset, 0),
+		}
+		tracker.Observe(ctx, limit)
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
@@ -0,0 +1,65 @@
+package githubrelease
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	internalhttp "github.com/anchore/binny/internal/http"
+)
+
+func Test_fetchRateLimits(t *testing.T) {
+	reset := time.Date(2024, 6, 1, 1, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"resources": {
+			"search": {"limit": 30, "remaining": 30, "reset": %[1]d},
+			"graphql": {"limit": 5000, "remaining": 0, "reset": %[1]d},
+			"core": {"limit": 5000, "remaining": 4990, "reset": %[1]d}
+		}}`, reset.Unix())
+	}))
+	defer server.Close()
+
+	tracker := internalhttp.NewRateLimits(0)
+	ctx := internalhttp.WithRateLimits(context.Background(), tracker)
+
+	limits, err := fetchRateLimits(ctx, server.Client(), server.URL)
+	require.NoError(t, err)
+
+	host := "127.0.0.1"
+	want := []internalhttp.RateLimit{
+		{Host: host, Resource: "core", Limit: 5000, Remaining: 4990, Reset: reset.Local()},
+		{Host: host, Resource: "graphql", Limit: 5000, Remaining: 0, Reset: reset.Local()},
+	}
+	assert.Equal(t, want, limits)
+	// the state is shared with the rest of the requests
+	assert.Equal(t, want, tracker.Limits())
+}
+
+func Test_queryReleasesBatch_observesRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"data": {
+			"r0": {"releases": {"pageInfo": {"hasNextPage": false}, "nodes": []}},
+			"rateLimit": {"cost": 1, "limit": 5000, "remaining": 4321, "resetAt": "2024-06-01T01:00:00Z"}
+		}}`)
+	}))
+	defer server.Close()
+
+	tracker := internalhttp.NewRateLimits(0)
+	ctx := internalhttp.WithRateLimits(context.Background(), tracker)
+
+	pages, err := queryReleasesBatch(ctx, server.Client(), server.URL, []pendingRepo{{user: "anchore", repo: "binny"}})
+	require.NoError(t, err)
+	assert.Len(t, pages, 1)
+
+	assert.Equal(t, []internalhttp.RateLimit{
+		{Host: "127.0.0.1", Resource: "graphql", Limit: 5000, Remaining: 4321, Reset: time.Date(2024, 6, 1, 1, 0, 0, 0, time.UTC)},
+	}, tracker.Limits())
+}
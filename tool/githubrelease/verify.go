@@ -0,0 +1,236 @@
+package githubrelease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/anchore/binny/internal"
+	"github.com/anchore/binny/internal/log"
+	"github.com/anchore/binny/internal/pgp"
+	"github.com/anchore/binny/internal/provenance"
+	"github.com/anchore/binny/internal/sigstore"
+)
+
+// maxSignatureSize bounds how much of a signature, certificate, or bundle asset is read.
+const maxSignatureSize = 1 << 20
+
+// errNotCosignSignature is returned for a ".sig" asset that is not a cosign signature, such as a detached gpg signature.
+var errNotCosignSignature = errors.New("not a cosign signature")
+
+// VerifyParameters configures verification of the signatures published alongside release assets.
+type VerifyParameters struct {
+	// Required fails the installation when neither the checksums file nor the asset has a verified signature.
+	Required bool `json:"required" yaml:"required,omitempty" mapstructure:"required"`
+	// Cosign verifies cosign signatures (".sig", ".pem", and ".bundle" / ".sigstore.json" assets).
+	Cosign sigstore.Config `json:"cosign" yaml:"cosign,omitempty" mapstructure:"cosign"`
+	// GPG verifies detached OpenPGP signatures (".asc", ".sig", and ".gpg" assets), such as "SHA256SUMS.asc". When both
+	// cosign and gpg are configured, cosign signatures are checked first, and a ".sig" asset that is not a cosign
+	// signature is checked with gpg.
+	GPG pgp.Config `json:"gpg" yaml:"gpg,omitempty" mapstructure:"gpg"`
+	// Provenance verifies the SLSA provenance attestations (e.g. ".intoto.jsonl" assets) of the asset, which is always
+	// required once configured.
+	Provenance provenance.Config `json:"provenance" yaml:"provenance,omitempty" mapstructure:"provenance"`
+}
+
+// signatureVerifier verifies the signatures published for the assets of a release.
+type signatureVerifier struct {
+	required bool
+	// policy verifies cosign signatures (nil when cosign is not configured)
+	policy *sigstore.Policy
+	// keyring verifies gpg signatures (nil when gpg is not configured)
+	keyring *pgp.KeyRing
+	assets  []ghAsset
+}
+
+// IsSet reports whether cosign or gpg signature verification is configured.
+func (p VerifyParameters) IsSet() bool {
+	return p.Cosign.IsSet() || p.GPG.IsSet()
+}
+
+// Validate loads the configured keys and certificates, reporting an incomplete or invalid configuration the same way
+// installing would.
+func (p VerifyParameters) Validate() error {
+	_, err := newSignatureVerifier(p, nil)
+	return err
+}
+
+// newSignatureVerifier returns nil when no verification is configured.
+func newSignatureVerifier(cfg VerifyParameters, assets []ghAsset) (*signatureVerifier, error) {
+	if !cfg.IsSet() {
+		if cfg.Required {
+			return nil, fmt.Errorf("signature verification is required but no cosign or gpg keys are configured")
+		}
+		return nil, nil
+	}
+
+	v := &signatureVerifier{
+		required: cfg.Required,
+		assets:   assets,
+	}
+
+	if cfg.Cosign.IsSet() {
+		policy, err := cfg.Cosign.Policy()
+		if err != nil {
+			return nil, err
+		}
+		v.policy = policy
+	}
+
+	if cfg.GPG.IsSet() {
+		keyring, err := cfg.GPG.KeyRing()
+		if err != nil {
+			return nil, err
+		}
+		v.keyring = keyring
+	}
+
+	return v, nil
+}
+
+// verify checks the signature published for the downloaded target asset. It returns false (without an error) when the
+// release has no signature for the target.
+func (v *signatureVerifier) verify(ctx context.Context, target ghAsset, path string) (bool, error) {
+	lgr := log.FromContext(ctx).WithFields("asset", target.Name)
+
+	if v.policy != nil {
+		signed, err := v.verifyCosign(ctx, target, path)
+		switch {
+		case errors.Is(err, errNotCosignSignature) && v.keyring != nil:
+			// both verifiers claim ".sig" assets, so leave it to gpg
+			log.FromContext(ctx).WithFields("asset", target.Name, "error", err).Trace("not a cosign signature, trying gpg")
+		case err != nil || signed:
+			return signed, err
+		}
+	}
+
+	if v.keyring != nil {
+		signed, err := v.verifyGPG(ctx, target, path)
+		if err != nil || signed {
+			return signed, err
+		}
+	}
+
+	lgr.Trace("no signature found for asset")
+	return false, nil
+}
+
+func (v *signatureVerifier) verifyCosign(ctx context.Context, target ghAsset, path string) (bool, error) {
+	material, err := v.fetchMaterial(ctx, target.Name)
+	if err != nil || material == nil {
+		return false, err
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	if err := v.policy.Verify(contents, *material); err != nil {
+		return false, fmt.Errorf("unable to verify signature for %q: %w", target.Name, err)
+	}
+
+	log.FromContext(ctx).WithFields("asset", target.Name).Debug("cosign signature verified")
+	return true, nil
+}
+
+func (v *signatureVerifier) verifyGPG(ctx context.Context, target ghAsset, path string) (bool, error) {
+	sigAsset := v.findAsset(target.Name+".asc", target.Name+".sig", target.Name+".gpg")
+	if sigAsset == nil {
+		return false, nil
+	}
+
+	sig, err := fetchSignatureAsset(ctx, *sigAsset)
+	if err != nil {
+		return false, err
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	fingerprint, err := v.keyring.Verify(contents, sig)
+	if err != nil {
+		return false, fmt.Errorf("unable to verify signature %q for %q: %w", sigAsset.Name, target.Name, err)
+	}
+
+	log.FromContext(ctx).WithFields("asset", target.Name, "key", fingerprint).Debug("gpg signature verified")
+	return true, nil
+}
+
+// unsigned reports that neither of the given assets has a signature, which is an error when verification is required.
+func (v *signatureVerifier) unsigned(ctx context.Context, names ...string) error {
+	if v.required {
+		return fmt.Errorf("signature verification is required but no signature was found for %q", names)
+	}
+	log.FromContext(ctx).WithFields("assets", names).Warn("no signature found to verify (skipping signature verification)")
+	return nil
+}
+
+// fetchMaterial downloads the signature material published for the named asset, preferring bundles over detached
+// signatures. A nil result means that there is no signature for the asset.
+func (v *signatureVerifier) fetchMaterial(ctx context.Context, name string) (*sigstore.Material, error) {
+	if bundle := v.findAsset(name+".sigstore.json", name+".sigstore", name+".bundle"); bundle != nil {
+		contents, err := fetchSignatureAsset(ctx, *bundle)
+		if err != nil {
+			return nil, err
+		}
+		m, err := sigstore.ParseBundle(contents)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle %q: %w", bundle.Name, err)
+		}
+		return m, nil
+	}
+
+	sigAsset := v.findAsset(name + ".sig")
+	if sigAsset == nil {
+		return nil, nil
+	}
+
+	contents, err := fetchSignatureAsset(ctx, *sigAsset)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sigstore.ParseSignature(contents)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature %q: %w: %w", sigAsset.Name, errNotCosignSignature, err)
+	}
+	m := &sigstore.Material{Signature: sig}
+
+	if certAsset := v.findAsset(name+".pem", name+".cert", name+".crt"); certAsset != nil {
+		contents, err := fetchSignatureAsset(ctx, *certAsset)
+		if err != nil {
+			return nil, err
+		}
+		if m.Certificates, err = sigstore.ParseCertificates(contents); err != nil {
+			return nil, fmt.Errorf("invalid certificate %q: %w", certAsset.Name, err)
+		}
+	}
+
+	return m, nil
+}
+
+// findAsset returns the first asset with one of the given names (in order of preference).
+func (v *signatureVerifier) findAsset(names ...string) *ghAsset {
+	for _, name := range names {
+		for i := range v.assets {
+			if v.assets[i].Name == name {
+				return &v.assets[i]
+			}
+		}
+	}
+	return nil
+}
+
+func fetchSignatureAsset(ctx context.Context, asset ghAsset) ([]byte, error) {
+	reader, err := internal.DownloadURL(ctx, asset.URL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download %q: %w", asset.Name, err)
+	}
+	defer reader.Close()
+
+	return io.ReadAll(io.LimitReader(reader, maxSignatureSize))
+}
@@ -0,0 +1,698 @@
+package githubrelease
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/binny"
+	"github.com/anchore/binny/internal"
+	"github.com/anchore/binny/internal/pgp"
+	"github.com/anchore/binny/internal/provenance"
+	"github.com/anchore/binny/internal/sigstore"
+)
+
+func TestInstaller_InstallTo_signatures(t *testing.T) {
+	binaryPath := filepath.Join("testdata", "archive-contents", "flat", "syft")
+	binary, err := os.ReadFile(binaryPath)
+	require.NoError(t, err)
+
+	binaryAssetName := fmt.Sprintf("syft_1.0.0_%s_%s", runtime.GOOS, runtime.GOARCH)
+	checksums := []byte(fmt.Sprintf("%x  %s\n", sha256.Sum256(binary), binaryAssetName))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	signFor := func(key *ecdsa.PrivateKey, contents []byte) []byte {
+		digest := sha256.Sum256(contents)
+		sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+		return []byte(base64.StdEncoding.EncodeToString(sig))
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	tests := []struct {
+		name    string
+		assets  map[string][]byte
+		verify  VerifyParameters
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name: "signed checksums",
+			assets: map[string][]byte{
+				"checksums.txt":     checksums,
+				"checksums.txt.sig": signFor(key, checksums),
+			},
+			verify: VerifyParameters{Required: true, Cosign: sigstore.Config{Key: publicKey}},
+		},
+		{
+			name: "signed asset without checksums",
+			assets: map[string][]byte{
+				binaryAssetName + ".sig": signFor(key, binary),
+			},
+			verify: VerifyParameters{Required: true, Cosign: sigstore.Config{Key: publicKey}},
+		},
+		{
+			name: "signed asset with unsigned checksums",
+			assets: map[string][]byte{
+				"checksums.txt":          checksums,
+				binaryAssetName + ".sig": signFor(key, binary),
+			},
+			verify: VerifyParameters{Required: true, Cosign: sigstore.Config{Key: publicKey}},
+		},
+		{
+			name: "checksums signed by another key",
+			assets: map[string][]byte{
+				"checksums.txt":     checksums,
+				"checksums.txt.sig": signFor(otherKey, checksums),
+			},
+			verify:  VerifyParameters{Cosign: sigstore.Config{Key: publicKey}},
+			wantErr: require.Error,
+		},
+		{
+			name: "signature for other checksums",
+			assets: map[string][]byte{
+				"checksums.txt":     checksums,
+				"checksums.txt.sig": signFor(key, []byte("something else")),
+			},
+			verify:  VerifyParameters{Cosign: sigstore.Config{Key: publicKey}},
+			wantErr: require.Error,
+		},
+		{
+			name: "required signature is missing",
+			assets: map[string][]byte{
+				"checksums.txt": checksums,
+			},
+			verify: VerifyParameters{Required: true, Cosign: sigstore.Config{Key: publicKey}},
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, "no signature was found")
+			},
+		},
+		{
+			name: "optional signature is missing",
+			assets: map[string][]byte{
+				"checksums.txt": checksums,
+			},
+			verify: VerifyParameters{Cosign: sigstore.Config{Key: publicKey}},
+		},
+		{
+			name: "required verification without a key",
+			assets: map[string][]byte{
+				"checksums.txt":     checksums,
+				"checksums.txt.sig": signFor(key, checksums),
+			},
+			verify:  VerifyParameters{Required: true},
+			wantErr: require.Error,
+		},
+		{
+			name: "keyless identity without a trusted root",
+			assets: map[string][]byte{
+				"checksums.txt":     checksums,
+				"checksums.txt.sig": signFor(key, checksums),
+			},
+			verify: VerifyParameters{Cosign: sigstore.Config{Identity: "https://github.com/anchore/syft/.github/workflows/release.yaml@refs/heads/main", Issuer: "https://token.actions.githubusercontent.com"}},
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, "trusted-root")
+			},
+		},
+		{
+			name: "signatures are ignored when verification is not configured",
+			assets: map[string][]byte{
+				"checksums.txt":     checksums,
+				"checksums.txt.sig": signFor(otherKey, checksums),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			tt.assets[binaryAssetName] = binary
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contents, ok := tt.assets[strings.TrimPrefix(r.URL.Path, "/")]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write(contents)
+			}))
+			t.Cleanup(s.Close)
+
+			var assets []ghAsset
+			for name := range tt.assets {
+				contentType := "text/plain; charset=utf-8"
+				if name == binaryAssetName {
+					contentType = "application/octet-stream"
+				}
+				assets = append(assets, ghAsset{Name: name, ContentType: contentType, URL: s.URL + "/" + name})
+			}
+
+			i := NewInstaller(InstallerParameters{Repo: "anchore/syft", Verify: tt.verify})
+			i.releaseFetcher = func(_ context.Context, _, _, tag string) (*ghRelease, error) {
+				return &ghRelease{Tag: tag, Assets: assets}, nil
+			}
+
+			destDir := t.TempDir()
+			got, err := i.InstallTo(context.Background(), "1.0.0", destDir)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, filepath.Join(destDir, binaryAssetName), got)
+		})
+	}
+}
+
+func TestInstaller_InstallTo_provenance(t *testing.T) {
+	binaryPath := filepath.Join("testdata", "archive-contents", "flat", "syft")
+	binary, err := os.ReadFile(binaryPath)
+	require.NoError(t, err)
+
+	binaryAssetName := fmt.Sprintf("syft_1.0.0_%s_%s", runtime.GOOS, runtime.GOARCH)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	attest := func(subject []byte, repo string) []byte {
+		statement, err := json.Marshal(map[string]any{
+			"_type":         "https://in-toto.io/Statement/v1",
+			"predicateType": "https://slsa.dev/provenance/v1",
+			"subject":       []any{map[string]any{"name": binaryAssetName, "digest": map[string]string{"sha256": fmt.Sprintf("%x", sha256.Sum256(subject))}}},
+			"predicate": map[string]any{
+				"buildDefinition": map[string]any{
+					"externalParameters": map[string]any{
+						"workflow": map[string]any{"repository": "https://github.com/" + repo, "ref": "refs/tags/1.0.0"},
+					},
+				},
+				"runDetails": map[string]any{"builder": map[string]any{"id": "https://github.com/actions/runner/github-hosted"}},
+			},
+		})
+		require.NoError(t, err)
+
+		payloadType := "application/vnd.in-toto+json"
+		pae := fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(statement), statement)
+		digest := sha256.Sum256([]byte(pae))
+		sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+
+		envelope, err := json.Marshal(map[string]any{
+			"payloadType": payloadType,
+			"payload":     statement,
+			"signatures":  []any{map[string]any{"keyid": "", "sig": sig}},
+		})
+		require.NoError(t, err)
+		return append(envelope, '\n')
+	}
+
+	provenanceConfig := provenance.Config{
+		BuilderID:  "https://github.com/actions/runner/github-hosted",
+		SourceRepo: "anchore/syft",
+		Config:     sigstore.Config{Key: publicKey},
+	}
+
+	tests := []struct {
+		name    string
+		assets  map[string][]byte
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name: "provenance for the asset",
+			assets: map[string][]byte{
+				binaryAssetName + ".intoto.jsonl": attest(binary, "anchore/syft"),
+			},
+		},
+		{
+			name: "provenance for all assets",
+			assets: map[string][]byte{
+				"multiple.intoto.jsonl": attest(binary, "anchore/syft"),
+			},
+		},
+		{
+			name: "provenance from another repo",
+			assets: map[string][]byte{
+				binaryAssetName + ".intoto.jsonl": attest(binary, "attacker/syft"),
+			},
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, "unable to verify provenance")
+			},
+		},
+		{
+			name: "provenance for another asset",
+			assets: map[string][]byte{
+				binaryAssetName + ".intoto.jsonl": attest([]byte("something else"), "anchore/syft"),
+			},
+			wantErr: require.Error,
+		},
+		{
+			name:   "missing provenance",
+			assets: map[string][]byte{},
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, "no attestation was found")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			tt.assets[binaryAssetName] = binary
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contents, ok := tt.assets[strings.TrimPrefix(r.URL.Path, "/")]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write(contents)
+			}))
+			t.Cleanup(s.Close)
+
+			var assets []ghAsset
+			for name := range tt.assets {
+				contentType := "application/json"
+				if name == binaryAssetName {
+					contentType = "application/octet-stream"
+				}
+				assets = append(assets, ghAsset{Name: name, ContentType: contentType, URL: s.URL + "/" + name})
+			}
+
+			i := NewInstaller(InstallerParameters{Repo: "anchore/syft", Verify: VerifyParameters{Provenance: provenanceConfig}})
+			i.releaseFetcher = func(_ context.Context, _, _, tag string) (*ghRelease, error) {
+				return &ghRelease{Tag: tag, Assets: assets}, nil
+			}
+
+			destDir := t.TempDir()
+			got, err := i.InstallTo(context.Background(), "1.0.0", destDir)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, filepath.Join(destDir, binaryAssetName), got)
+		})
+	}
+}
+
+func TestInstaller_InstallTo_gpg(t *testing.T) {
+	binaryPath := filepath.Join("testdata", "archive-contents", "flat", "syft")
+	binary, err := os.ReadFile(binaryPath)
+	require.NoError(t, err)
+
+	binaryAssetName := fmt.Sprintf("syft_1.0.0_%s_%s", runtime.GOOS, runtime.GOARCH)
+	sums := []byte(fmt.Sprintf("%x *%s\n", sha256.Sum256(binary), binaryAssetName))
+
+	maintainer, err := openpgp.NewEntity("maintainer", "", "maintainer@example.com", nil)
+	require.NoError(t, err)
+	other, err := openpgp.NewEntity("other", "", "other@example.com", nil)
+	require.NoError(t, err)
+
+	var key bytes.Buffer
+	w, err := armor.Encode(&key, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, maintainer.Serialize(w))
+	require.NoError(t, w.Close())
+
+	detachSign := func(e *openpgp.Entity, contents []byte) []byte {
+		var buf bytes.Buffer
+		require.NoError(t, openpgp.ArmoredDetachSign(&buf, e, bytes.NewReader(contents), nil))
+		return buf.Bytes()
+	}
+	binaryDetachSign := func(e *openpgp.Entity, contents []byte) []byte {
+		var buf bytes.Buffer
+		require.NoError(t, openpgp.DetachSign(&buf, e, bytes.NewReader(contents), nil))
+		return buf.Bytes()
+	}
+
+	cosignKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&cosignKey.PublicKey)
+	require.NoError(t, err)
+	cosignPublicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	cosignSign := func(contents []byte) []byte {
+		digest := sha256.Sum256(contents)
+		sig, err := ecdsa.SignASN1(rand.Reader, cosignKey, digest[:])
+		require.NoError(t, err)
+		return []byte(base64.StdEncoding.EncodeToString(sig))
+	}
+	both := VerifyParameters{Required: true, Cosign: sigstore.Config{Key: cosignPublicKey}, GPG: pgp.Config{Keys: []string{key.String()}}}
+
+	tests := []struct {
+		name    string
+		assets  map[string][]byte
+		verify  VerifyParameters
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name: "signed SHA256SUMS",
+			assets: map[string][]byte{
+				"SHA256SUMS":     sums,
+				"SHA256SUMS.asc": detachSign(maintainer, sums),
+			},
+			verify: VerifyParameters{Required: true, GPG: pgp.Config{Keys: []string{key.String()}}},
+		},
+		{
+			name: "signed checksums.txt",
+			assets: map[string][]byte{
+				"checksums.txt":     sums,
+				"checksums.txt.sig": detachSign(maintainer, sums),
+			},
+			verify: VerifyParameters{Required: true, GPG: pgp.Config{Keys: []string{key.String()}}},
+		},
+		{
+			name: "gpg signed checksums.txt with cosign configured",
+			assets: map[string][]byte{
+				"checksums.txt":     sums,
+				"checksums.txt.sig": detachSign(maintainer, sums),
+			},
+			verify: both,
+		},
+		{
+			name: "binary gpg signature with cosign configured",
+			assets: map[string][]byte{
+				"checksums.txt":     sums,
+				"checksums.txt.sig": binaryDetachSign(maintainer, sums),
+			},
+			verify: both,
+		},
+		{
+			name: "cosign signed checksums.txt with gpg configured",
+			assets: map[string][]byte{
+				"checksums.txt":     sums,
+				"checksums.txt.sig": cosignSign(sums),
+			},
+			verify: both,
+		},
+		{
+			name: "gpg signed by another key with cosign configured",
+			assets: map[string][]byte{
+				"checksums.txt":     sums,
+				"checksums.txt.sig": detachSign(other, sums),
+			},
+			verify: both,
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, "invalid gpg signature")
+			},
+		},
+		{
+			name: "signed by another key",
+			assets: map[string][]byte{
+				"SHA256SUMS":     sums,
+				"SHA256SUMS.asc": detachSign(other, sums),
+			},
+			verify: VerifyParameters{GPG: pgp.Config{Keys: []string{key.String()}}},
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, "invalid gpg signature")
+			},
+		},
+		{
+			name: "tampered checksums",
+			assets: map[string][]byte{
+				"SHA256SUMS":     sums,
+				"SHA256SUMS.asc": detachSign(maintainer, []byte("other checksums")),
+			},
+			verify:  VerifyParameters{GPG: pgp.Config{Keys: []string{key.String()}}},
+			wantErr: require.Error,
+		},
+		{
+			name: "required signature is missing",
+			assets: map[string][]byte{
+				"SHA256SUMS": sums,
+			},
+			verify: VerifyParameters{Required: true, GPG: pgp.Config{Keys: []string{key.String()}}},
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				require.ErrorContains(t, err, "no signature was found")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			tt.assets[binaryAssetName] = binary
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contents, ok := tt.assets[strings.TrimPrefix(r.URL.Path, "/")]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write(contents)
+			}))
+			t.Cleanup(s.Close)
+
+			var assets []ghAsset
+			for name := range tt.assets {
+				contentType := "text/plain; charset=utf-8"
+				if name == binaryAssetName {
+					contentType = "application/octet-stream"
+				}
+				assets = append(assets, ghAsset{Name: name, ContentType: contentType, URL: s.URL + "/" + name})
+			}
+
+			i := NewInstaller(InstallerParameters{Repo: "anchore/syft", Verify: tt.verify})
+			i.releaseFetcher = func(_ context.Context, _, _, tag string) (*ghRelease, error) {
+				return &ghRelease{Tag: tag, Assets: assets}, nil
+			}
+
+			destDir := t.TempDir()
+			got, err := i.InstallTo(context.Background(), "1.0.0", destDir)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, filepath.Join(destDir, binaryAssetName), got)
+		})
+	}
+}
+
+func TestInstaller_InstallTo_requireChecksum(t *testing.T) {
+	binaryPath := filepath.Join("testdata", "archive-contents", "flat", "syft")
+	binary, err := os.ReadFile(binaryPath)
+	require.NoError(t, err)
+
+	binaryAssetName := fmt.Sprintf("syft_1.0.0_%s_%s", runtime.GOOS, runtime.GOARCH)
+	checksums := []byte(fmt.Sprintf("%x  %s\n", sha256.Sum256(binary), binaryAssetName))
+	platform := binny.Platform(runtime.GOOS, runtime.GOARCH)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	digest := sha256.Sum256(binary)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		assets     map[string][]byte
+		verify     VerifyParameters
+		pinned     binny.PinnedChecksums
+		required   bool
+		wantSource string
+		wantErr    require.ErrorAssertionFunc
+	}{
+		{
+			name:       "checksums file",
+			assets:     map[string][]byte{"checksums.txt": checksums},
+			required:   true,
+			wantSource: `checksums file "checksums.txt"`,
+		},
+		{
+			name:       "signed asset without checksums",
+			assets:     map[string][]byte{binaryAssetName + ".sig": []byte(base64.StdEncoding.EncodeToString(sig))},
+			verify:     VerifyParameters{Cosign: sigstore.Config{Key: publicKey}},
+			required:   true,
+			wantSource: fmt.Sprintf("signature for %q", binaryAssetName),
+		},
+		{
+			name:       "pinned checksum",
+			assets:     map[string][]byte{},
+			pinned:     binny.PinnedChecksums{platform: fmt.Sprintf("sha256:%x", sha256.Sum256(binary))},
+			required:   true,
+			wantSource: "pinned checksum",
+		},
+		{
+			name:       "pinned checksum with another algorithm than the checksums file",
+			assets:     map[string][]byte{"checksums.txt": checksums},
+			pinned:     binny.PinnedChecksums{platform: fmt.Sprintf("sha512:%x", sha512.Sum512(binary))},
+			wantSource: "pinned checksum",
+		},
+		{
+			name:     "pinned checksum mismatch",
+			assets:   map[string][]byte{},
+			pinned:   binny.PinnedChecksums{platform: fmt.Sprintf("%x", sha256.Sum256([]byte("other")))},
+			required: true,
+			wantErr:  require.Error,
+		},
+		{
+			name:    "pinned checksum conflicts with the checksums file",
+			assets:  map[string][]byte{"checksums.txt": checksums},
+			pinned:  binny.PinnedChecksums{platform: fmt.Sprintf("%x", sha256.Sum256([]byte("other")))},
+			wantErr: require.Error,
+		},
+		{
+			name:       "pinned checksum for another platform",
+			assets:     map[string][]byte{"checksums.txt": checksums},
+			pinned:     binny.PinnedChecksums{"plan9/mips": fmt.Sprintf("%x", sha256.Sum256([]byte("other")))},
+			wantSource: `checksums file "checksums.txt"`,
+		},
+		{
+			name:   "no checksums when not required",
+			assets: map[string][]byte{},
+		},
+		{
+			name:     "no checksums when required",
+			assets:   map[string][]byte{},
+			required: true,
+			wantErr: func(t require.TestingT, err error, _ ...any) {
+				var required *internal.ErrChecksumRequired
+				require.ErrorAs(t, err, &required)
+				require.ErrorContains(t, err, `checksum required for tool "syft"`)
+				require.ErrorContains(t, err, binaryAssetName)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			tt.assets[binaryAssetName] = binary
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contents, ok := tt.assets[strings.TrimPrefix(r.URL.Path, "/")]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write(contents)
+			}))
+			t.Cleanup(s.Close)
+
+			var assets []ghAsset
+			for name := range tt.assets {
+				contentType := "text/plain; charset=utf-8"
+				if name == binaryAssetName {
+					contentType = "application/octet-stream"
+				}
+				assets = append(assets, ghAsset{Name: name, ContentType: contentType, URL: s.URL + "/" + name})
+			}
+
+			i := NewInstaller(InstallerParameters{Repo: "anchore/syft", Verify: tt.verify, Checksums: tt.pinned})
+			i.releaseFetcher = func(_ context.Context, _, _, tag string) (*ghRelease, error) {
+				return &ghRelease{Tag: tag, Assets: assets}, nil
+			}
+
+			ctx, record := internal.WithChecksumRecord(context.Background(), "syft", tt.required)
+			_, err := i.InstallTo(ctx, "1.0.0", t.TempDir())
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantSource, record.Source())
+		})
+	}
+}
+
+func TestInstaller_PinChecksums(t *testing.T) {
+	assets := map[string][]byte{
+		"syft_1.0.0_linux_amd64":  []byte("linux binary"),
+		"syft_1.0.0_darwin_arm64": []byte("darwin binary"),
+	}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(assets[strings.TrimPrefix(r.URL.Path, "/")])
+	}))
+	t.Cleanup(s.Close)
+
+	var release ghRelease
+	for name := range assets {
+		release.Assets = append(release.Assets, ghAsset{Name: name, ContentType: "application/octet-stream", URL: s.URL + "/" + name})
+	}
+
+	tests := []struct {
+		name    string
+		pinned  binny.PinnedChecksums
+		want    binny.PinnedChecksums
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name: "refresh every platform keeping the algorithm",
+			pinned: binny.PinnedChecksums{
+				"linux/amd64":  "sha256:stale",
+				"darwin/arm64": "",
+			},
+			want: binny.PinnedChecksums{
+				"linux/amd64":  fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("linux binary"))),
+				"darwin/arm64": fmt.Sprintf("%x", sha256.Sum256([]byte("darwin binary"))),
+			},
+		},
+		{
+			name:   "sha512",
+			pinned: binny.PinnedChecksums{"linux/amd64": "sha512:stale"},
+			want:   binny.PinnedChecksums{"linux/amd64": fmt.Sprintf("sha512:%x", sha512.Sum512([]byte("linux binary")))},
+		},
+		{
+			name:    "no asset for the platform",
+			pinned:  binny.PinnedChecksums{"windows/arm64": ""},
+			wantErr: require.Error,
+		},
+		{
+			name:    "invalid platform",
+			pinned:  binny.PinnedChecksums{"linux": ""},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			i := NewInstaller(InstallerParameters{Repo: "anchore/syft"})
+			i.releaseFetcher = func(_ context.Context, _, _, tag string) (*ghRelease, error) {
+				assert.Equal(t, "v1.0.0", tag)
+				return &release, nil
+			}
+
+			got, err := i.PinChecksums(context.Background(), "v1.0.0", tt.pinned)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
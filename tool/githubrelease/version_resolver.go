@@ -3,21 +3,19 @@ package githubrelease
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"sort"
 	"strings"
 	"time"
 
-	"github.com/Masterminds/semver/v3"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/shurcooL/githubv4"
 	"golang.org/x/oauth2"
 
 	"github.com/anchore/binny"
-	"github.com/anchore/binny/internal"
 	internalhttp "github.com/anchore/binny/internal/http"
 	"github.com/anchore/binny/internal/log"
 )
@@ -42,38 +40,46 @@ func NewVersionResolver(cfg VersionResolutionParameters) *VersionResolver {
 	}
 }
 
+// GitHubRepo returns the repository ("owner/repo") that releases are listed from.
+func (v VersionResolver) GitHubRepo() string {
+	return v.config.Repo
+}
+
 func (v VersionResolver) UpdateVersion(ctx context.Context, intent binny.VersionIntent) (string, error) {
 	if intent.Want == "latest" {
 		return intent.Want, nil
 	}
 
-	if internal.IsSemver(intent.Want) {
-		return v.findLatestVersion(ctx, intent.Constraint, intent.Cooldown)
+	filter := intent.Filter()
+	if filter.IsVersion(intent.Want) {
+		version, _, err := v.findLatestVersion(ctx, filter, intent.Cooldown, intent.LatestStrategy)
+		return version, err
 	}
 
 	return intent.Want, nil
 }
 
 func (v VersionResolver) ResolveVersion(ctx context.Context, intent binny.VersionIntent) (string, error) {
-	log.FromContext(ctx).WithFields("repo", v.config.Repo, "version", intent.Want).Trace("resolving version from github release")
+	version, _, err := v.ResolveVersionWithStrategy(ctx, intent)
+	return version, err
+}
 
-	if internal.IsSemver(intent.Want) {
-		return intent.Want, nil
-	}
+func (v VersionResolver) ResolveVersionWithStrategy(ctx context.Context, intent binny.VersionIntent) (string, binny.LatestStrategy, error) {
+	log.FromContext(ctx).WithFields("repo", v.config.Repo, "version", intent.Want).Trace("resolving version from github release")
 
 	if intent.Want == "latest" {
-		return v.findLatestVersion(ctx, intent.Constraint, intent.Cooldown)
+		return v.findLatestVersion(ctx, intent.Filter(), intent.Cooldown, intent.LatestStrategy)
 	}
 
-	return intent.Want, nil
+	return intent.Want, "", nil
 }
 
-func (v VersionResolver) findLatestVersion(ctx context.Context, versionConstraint string, cooldown time.Duration) (string, error) {
+func (v VersionResolver) findLatestVersion(ctx context.Context, filter binny.VersionFilter, cooldown time.Duration, strategy binny.LatestStrategy) (string, binny.LatestStrategy, error) {
 	lgr := log.FromContext(ctx)
 	cfg := v.config
 	fields := strings.Split(cfg.Repo, "/")
 	if len(fields) != 2 {
-		return "", fmt.Errorf("invalid github repo format: %q", cfg.Repo)
+		return "", "", fmt.Errorf("invalid github repo format: %q", cfg.Repo)
 	}
 	user, repo := fields[0], fields[1]
 
@@ -83,77 +89,100 @@ func (v VersionResolver) findLatestVersion(ctx context.Context, versionConstrain
 		cutoff = &t
 	}
 
+	// releases listed ahead of time (for many repositories at once) carry everything the facade would tell us
+	releases, prefetched := prefetchedReleases(ctx, user, repo)
+
 	// when cooldown is active, skip the cheap facade path since it doesn't return publish dates
-	// (we need dates to enforce the cooldown). Fall through to the full API path instead.
-	if cutoff == nil {
+	// (we need dates to enforce the cooldown). Fall through to the full API path instead. The same is true
+	// when prereleases are candidates, since the facade only ever points to the latest non-prerelease.
+	switch {
+	case prefetched:
+		lgr.WithFields("repo", cfg.Repo).Trace("using prefetched releases")
+	case strategy != "" && strategy != binny.LatestGitHub:
+		lgr.WithFields("repo", cfg.Repo, "strategy", string(strategy)).
+			Trace("skipping facade path since the latest strategy does not use the latest release flag")
+	case cutoff == nil && !filter.Prerelease.Allows(true):
 		latestRelease, err := v.latestReleaseFetcher(ctx, user, repo)
 		if err != nil {
-			return "", fmt.Errorf("unable to fetch latest release: %v", err)
+			return "", "", fmt.Errorf("unable to fetch latest release: %v", err)
 		}
 
 		// try the cheapest path forward first -- if this is compliant to the constraint, use it.
 		if latestRelease != nil {
-			latestVersion, err := filterToLatestVersion([]ghRelease{*latestRelease}, versionConstraint, nil)
+			latestVersion, _, err := filterToLatestVersion([]ghRelease{*latestRelease}, filter, nil, strategy)
 			if err != nil {
-				return "", fmt.Errorf("unable to filter to latest version: %v", err)
+				return "", "", fmt.Errorf("unable to filter to latest version: %v", err)
 			}
 			if latestVersion != nil {
-				return latestVersion.Tag, nil
+				return latestVersion.Tag, binny.LatestGitHub, nil
 			}
 		}
-	} else {
+	case cutoff != nil:
 		lgr.WithFields("repo", cfg.Repo, "cooldown", cooldown.String()).
 			Trace("skipping facade path for cooldown enforcement (requires release dates from API)")
+	default:
+		lgr.WithFields("repo", cfg.Repo, "prerelease", string(filter.Prerelease)).
+			Trace("skipping facade path since prereleases are candidates")
 	}
 
 	// this path requires the most work, but is typically needed if there is a constraint or cooldown
-	releases, err := v.releasesFetcher(ctx, user, repo)
-	if err != nil {
-		return "", fmt.Errorf("unable to fetch all releases: %v", err)
+	if !prefetched {
+		var err error
+		releases, err = v.releasesFetcher(ctx, user, repo)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to fetch all releases: %v", err)
+		}
 	}
 
-	latestVersion, err := filterToLatestVersion(releases, versionConstraint, cutoff)
+	latestVersion, used, err := filterToLatestVersion(releases, filter, cutoff, strategy)
 	if err != nil {
-		return "", fmt.Errorf("unable to filter to latest version: %v", err)
+		return "", "", fmt.Errorf("unable to filter to latest version: %v", err)
 	}
 	if latestVersion == nil {
 		if cutoff != nil {
-			// find the absolute latest (without cooldown) to produce a helpful error message
-			absoluteLatest, _ := filterToLatestVersion(releases, versionConstraint, nil)
-			var latestTag string
-			var latestDate *time.Time
-			if absoluteLatest != nil {
-				latestTag = absoluteLatest.Tag
-				latestDate = absoluteLatest.Date
-			}
-			return "", &binny.CooldownError{
-				Cooldown:      cooldown,
-				LatestVersion: latestTag,
-				LatestDate:    latestDate,
-			}
+			return "", "", newCooldownError(releases, filter, cooldown, strategy)
 		}
-		return "", fmt.Errorf("no latest version found")
+		return "", "", fmt.Errorf("no latest version found")
 	}
 
-	lgr.WithFields("latest", latestVersion.Tag, "repo", cfg.Repo).
+	lgr.WithFields("latest", latestVersion.Tag, "repo", cfg.Repo, "strategy", string(used)).
 		Trace("found latest version from the github release")
 
-	return latestVersion.Tag, nil
+	return latestVersion.Tag, used, nil
+}
+
+// newCooldownError describes the absolute latest release (without cooldown) to produce a helpful error message.
+func newCooldownError(releases []ghRelease, filter binny.VersionFilter, cooldown time.Duration, strategy binny.LatestStrategy) error {
+	cooldownErr := &binny.CooldownError{Cooldown: cooldown}
+	if absoluteLatest, _, _ := filterToLatestVersion(releases, filter, nil, strategy); absoluteLatest != nil {
+		cooldownErr.LatestVersion = absoluteLatest.Tag
+		cooldownErr.LatestDate = absoluteLatest.Date
+	}
+	return cooldownErr
 }
 
-// filterToLatestVersion finds the latest release that satisfies the version constraint and cooldown cutoff.
-// If cutoff is non-nil, releases published after the cutoff time are skipped (too new).
+// filterToLatestVersion finds the latest release that satisfies the version constraint, prerelease policy, and
+// cooldown cutoff. If cutoff is non-nil, releases published after the cutoff time are skipped (too new). The tag of
+// the returned release is mapped to a version with the filter's tag mapping. Which release is the latest depends on
+// the strategy, and the strategy that was actually applied is returned (the "latest" flag falls back to the highest
+// version when no candidate is flagged).
 //
 //nolint:gocognit
-func filterToLatestVersion(releases []ghRelease, versionConstraint string, cutoff *time.Time) (*ghRelease, error) {
-	var constraint *semver.Constraints
-	var err error
+func filterToLatestVersion(releases []ghRelease, filter binny.VersionFilter, cutoff *time.Time, strategy binny.LatestStrategy) (*ghRelease, binny.LatestStrategy, error) {
+	constraint, err := filter.Constraints()
+	if err != nil {
+		return nil, "", err
+	}
 
-	if versionConstraint != "" {
-		constraint, err = semver.NewConstraint(versionConstraint)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse version constraint %q: %v", versionConstraint, err)
-		}
+	releases = releasesWithVersions(releases, filter.Tags)
+
+	// github never marks a prerelease as the latest release, so the "latest" flag can only be trusted when
+	// prereleases are not candidates
+	trustLatestFlag := (strategy == "" || strategy == binny.LatestGitHub) && !filter.Prerelease.Allows(true)
+
+	used := binny.LatestHighestSemver
+	if strategy == binny.LatestNewestDate {
+		used = binny.LatestNewestDate
 	}
 
 	var latest *ghRelease
@@ -170,49 +199,72 @@ func filterToLatestVersion(releases []ghRelease, versionConstraint string, cutof
 			}
 		}
 
-		ver, err := semver.NewVersion(ty.Tag)
+		flaggedPrerelease := ty.IsPrerelease != nil && *ty.IsPrerelease
+
+		ver, err := filter.Parse(ty.Tag)
 		if err != nil {
-			log.WithFields("tag", ty.Tag).Warn("unable to parse version as semver")
+			log.WithFields("tag", ty.Tag, "scheme", filter.Scheme).Warn("unable to parse version")
 			ver = nil
 		}
 
-		if ty.IsLatest != nil && *ty.IsLatest {
-			if constraint != nil && ver != nil {
-				if constraint.Check(ver) {
-					latest = &ty
-					break
-				}
-			} else {
-				latest = &ty
-				break
+		if ver != nil {
+			if !filter.Allows(constraint, ver, flaggedPrerelease) {
+				continue
 			}
+		} else if skipped, _ := filter.IsSkipped(ty.Tag); skipped || !filter.Prerelease.Allows(flaggedPrerelease) {
+			// note: the skip list was already validated when parsing the constraints above
+			continue
 		}
 
-		if latest != nil {
-			latestVer, err := semver.NewVersion(latest.Tag)
-			if err != nil {
-				log.WithFields("tag", latest.Tag).Warn("unable to parse current latest version as semver")
-				// can't compare semver, so skip this candidate entirely since we already have a latest
-				continue
-			}
+		if trustLatestFlag && ty.IsLatest != nil && *ty.IsLatest {
+			return &ty, binny.LatestGitHub, nil
+		}
 
-			if ver != nil {
-				if ver.LessThan(latestVer) || ver.Equal(latestVer) {
-					continue
-				}
-			}
+		if latest != nil && !isNewer(ty, ver, *latest, filter, used) {
+			continue
 		}
 
-		if constraint != nil && ver != nil {
-			if constraint.Check(ver) {
-				latest = &ty
-			}
-		} else {
-			latest = &ty
+		latest = &ty
+	}
+
+	return latest, used, nil
+}
+
+// isNewer reports whether the candidate release should replace the current latest release under the given strategy.
+func isNewer(candidate ghRelease, candidateVer binny.Version, current ghRelease, filter binny.VersionFilter, strategy binny.LatestStrategy) bool {
+	if strategy == binny.LatestNewestDate {
+		switch {
+		case candidate.Date == nil:
+			return false
+		case current.Date == nil:
+			return true
 		}
+		return candidate.Date.After(*current.Date)
 	}
 
-	return latest, nil
+	currentVer, err := filter.Parse(current.Tag)
+	if err != nil {
+		log.WithFields("tag", current.Tag, "scheme", filter.Scheme).Warn("unable to parse current latest version")
+		// can't compare versions, so skip this candidate entirely since we already have a latest
+		return false
+	}
+
+	return candidateVer == nil || candidateVer.Compare(currentVer) > 0
+}
+
+// releasesWithVersions replaces the tag of each release with the version extracted by the tag mapping, dropping
+// releases with tags that do not match (e.g. tags for other components within a monorepo).
+func releasesWithVersions(releases []ghRelease, tags binny.TagMapping) []ghRelease {
+	var mapped []ghRelease
+	for _, r := range releases {
+		version, ok := tags.Version(r.Tag)
+		if !ok {
+			continue
+		}
+		r.Tag = version
+		mapped = append(mapped, r)
+	}
+	return mapped
 }
 
 func fetchLatestReleaseFromGithubFacade(ctx context.Context, user, repo string) (*ghRelease, error) {
@@ -252,7 +304,7 @@ func fetchLatestReleaseFromGithubFacade(ctx context.Context, user, repo string)
 
 func downloadJSON(ctx context.Context, url string) (*http.Response, error) {
 	lgr := log.FromContext(ctx)
-	client := internalhttp.ClientFromContext(ctx)
+	client := internalhttp.ResolutionClientFromContext(ctx)
 
 	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -273,11 +325,15 @@ func downloadJSON(ctx context.Context, url string) (*http.Response, error) {
 // newRetryableGitHubClient creates an HTTP client with OAuth2 authentication and retry logic.
 func newRetryableGitHubClient(ctx context.Context, token string) *http.Client {
 	src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	oauth2Client := oauth2.NewClient(ctx, src)
 
-	// get base client from context and use oauth2 transport
+	// the response cache sits beneath the oauth2 transport so that the credentials are part of the cache key, and the
+	// rate limit tracking and per-host limits beneath the cache so that cached responses are neither limited nor
+	// mistaken for the current rate limit state (a nil base falls back to the default transport when none are enabled)
 	retryClient := retryablehttp.NewClient()
-	retryClient.HTTPClient.Transport = oauth2Client.Transport
+	retryClient.HTTPClient.Transport = &oauth2.Transport{
+		Source: oauth2.ReuseTokenSource(nil, src),
+		Base:   internalhttp.CacheFromContext(ctx).QueryTransport(internalhttp.RateLimitsFromContext(ctx).Transport(internalhttp.LimiterFromContext(ctx).Transport(nil))),
+	}
 	retryClient.Logger = nil
 
 	// keep retries short-lived: the default 1->30s backoff over 5 attempts could waste
@@ -295,11 +351,15 @@ func newRetryableGitHubClient(ctx context.Context, token string) *http.Client {
 // GitHub returns 403 for both auth failures and secondary rate limits; neither resolves
 // by retrying. The current default policy already declines 403 (it only retries 429 and
 // 5xx), so this is forward-compat insurance: an upstream change can't reintroduce the
-// wasted backoff window.
+// wasted backoff window. Requests held back by an exhausted rate limit are not retried
+// either, the rate limit wait policy already decided not to wait for the reset.
 func githubRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
 	if resp != nil && resp.StatusCode == http.StatusForbidden {
 		return false, nil
 	}
+	if rlErr := (&internalhttp.RateLimitError{}); errors.As(err, &rlErr) {
+		return false, nil
+	}
 	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
 }
 
@@ -337,13 +397,16 @@ func fetchAllReleasesFromGithubV4API(ctx context.Context, user, repo string) ([]
 					HasNextPage bool
 				}
 				Nodes []struct {
-					TagName     githubv4.String
-					IsLatest    githubv4.Boolean
-					IsDraft     githubv4.Boolean
-					PublishedAt githubv4.DateTime
+					TagName      githubv4.String
+					IsLatest     githubv4.Boolean
+					IsDraft      githubv4.Boolean
+					IsPrerelease githubv4.Boolean
+					PublishedAt  githubv4.DateTime
 				}
 			} `graphql:"releases(first:$releasesPerPage, after:$releasesCursor)"` // newest first
 		} `graphql:"repository(owner:$repositoryOwner, name:$repositoryName)"`
+
+		RateLimit graphQLRateLimit
 	}
 	variables := map[string]any{
 		"repositoryOwner": githubv4.String(user),
@@ -357,14 +420,16 @@ func fetchAllReleasesFromGithubV4API(ctx context.Context, user, repo string) ([]
 		if err := client.Query(ctx, &query, variables); err != nil {
 			return nil, err
 		}
+		query.RateLimit.observe(ctx, githubAPIHost)
 
 		for _, node := range query.Repository.Releases.Nodes {
 			publishedAt := node.PublishedAt.Time
 			allReleases = append(allReleases, ghRelease{
-				Tag:      string(node.TagName),
-				IsLatest: boolRef(bool(node.IsLatest)),
-				IsDraft:  boolRef(bool(node.IsDraft)),
-				Date:     &publishedAt,
+				Tag:          string(node.TagName),
+				IsLatest:     boolRef(bool(node.IsLatest)),
+				IsDraft:      boolRef(bool(node.IsDraft)),
+				IsPrerelease: boolRef(bool(node.IsPrerelease)),
+				Date:         &publishedAt,
 			})
 		}
 
@@ -377,24 +442,35 @@ func fetchAllReleasesFromGithubV4API(ctx context.Context, user, repo string) ([]
 		variables["releasesCursor"] = githubv4.NewString(query.Repository.Releases.PageInfo.EndCursor)
 	}
 
-	sort.Slice(allReleases, func(i, j int) bool {
-		// sort from latest to earliest
-		if allReleases[i].Date == nil && allReleases[j].Date == nil {
-			return false
-		}
+	sortReleasesByDate(allReleases)
 
-		if allReleases[i].Date == nil {
-			return false
-		}
+	return allReleases, nil
+}
 
-		if allReleases[j].Date == nil {
-			return true
-		}
+// githubAPIHost is where the GitHub REST and GraphQL APIs are served.
+const githubAPIHost = "api.github.com"
 
-		return allReleases[i].Date.After(*allReleases[j].Date)
-	})
+// graphQLRateLimit is the GraphQL rate limit state, which can be requested alongside any query.
+type graphQLRateLimit struct {
+	Cost      githubv4.Int
+	Limit     githubv4.Int
+	Remaining githubv4.Int
+	ResetAt   githubv4.DateTime
+}
 
-	return allReleases, nil
+// observe records the rate limit state with the rate limit tracker (if any).
+func (r graphQLRateLimit) observe(ctx context.Context, host string) {
+	if r.ResetAt.IsZero() {
+		// not part of the response
+		return
+	}
+	internalhttp.RateLimitsFromContext(ctx).Observe(ctx, internalhttp.RateLimit{
+		Host:      host,
+		Resource:  "graphql",
+		Limit:     int(r.Limit),
+		Remaining: int(r.Remaining),
+		Reset:     r.ResetAt.Time,
+	})
 }
 
 func boolRef(b bool) *bool {
@@ -17,6 +17,7 @@ func TestVersionResolver_ResolveVersion(t *testing.T) {
 		config               VersionResolutionParameters
 		version              string
 		constraint           string
+		prerelease           binny.PrereleasePolicy
 		releasesFetcher      func(ctx context.Context, user, repo string) ([]ghRelease, error)
 		latestReleaseFetcher func(ctx context.Context, user, repo string) (*ghRelease, error)
 		want                 string
@@ -63,6 +64,31 @@ func TestVersionResolver_ResolveVersion(t *testing.T) {
 				}, nil
 			},
 		},
+		{
+			name: "including prereleases skips the latest release facade",
+			config: VersionResolutionParameters{
+				Repo: "anchore/binny",
+			},
+			version:    "latest",
+			prerelease: binny.PrereleaseInclude,
+			want:       "2.1.0-rc1",
+			latestReleaseFetcher: func(_ context.Context, user, repo string) (*ghRelease, error) {
+				t.Fatal("should not have been called")
+				return nil, nil
+			},
+			releasesFetcher: func(_ context.Context, user, repo string) ([]ghRelease, error) {
+				return []ghRelease{
+					{
+						Tag:          "2.1.0-rc1",
+						IsPrerelease: boolRef(true),
+					},
+					{
+						Tag:      "2.0.0",
+						IsLatest: boolRef(true),
+					},
+				}, nil
+			},
+		},
 		{
 			name: "semver input will be honored as is",
 			config: VersionResolutionParameters{
@@ -89,7 +115,7 @@ func TestVersionResolver_ResolveVersion(t *testing.T) {
 			v.latestReleaseFetcher = tt.latestReleaseFetcher
 			v.releasesFetcher = tt.releasesFetcher
 
-			got, err := v.ResolveVersion(context.Background(), binny.VersionIntent{Want: tt.version, Constraint: tt.constraint})
+			got, err := v.ResolveVersion(context.Background(), binny.VersionIntent{Want: tt.version, Constraint: tt.constraint, Prerelease: tt.prerelease})
 			tt.wantErr(t, err)
 			assert.Equal(t, tt.want, got)
 		})
@@ -102,6 +128,7 @@ func TestVersionResolver_UpdateVersion(t *testing.T) {
 		config               VersionResolutionParameters
 		version              string
 		constraint           string
+		scheme               binny.VersionScheme
 		releaseFetcher       func(ctx context.Context, user, repo string) ([]ghRelease, error)
 		latestReleaseFetcher func(ctx context.Context, user, repo string) (*ghRelease, error)
 		want                 string
@@ -156,6 +183,34 @@ func TestVersionResolver_UpdateVersion(t *testing.T) {
 				return nil, nil
 			},
 		},
+		{
+			name: "calver pins are updated",
+			config: VersionResolutionParameters{
+				Repo: "koalaman/tool",
+			},
+			version:    "2024.06.01",
+			constraint: "< 2025",
+			scheme:     binny.SchemeCalver,
+			want:       "2024.10.02",
+			latestReleaseFetcher: func(_ context.Context, user, repo string) (*ghRelease, error) {
+				return &ghRelease{
+					Tag: "2025.01.01",
+				}, nil
+			},
+			releaseFetcher: func(_ context.Context, user, repo string) ([]ghRelease, error) {
+				return []ghRelease{
+					{
+						Tag: "2025.01.01",
+					},
+					{
+						Tag: "2024.10.02",
+					},
+					{
+						Tag: "2024.06.01",
+					},
+				}, nil
+			},
+		},
 		{
 			name: "non-semver input is honored as is",
 			config: VersionResolutionParameters{
@@ -174,7 +229,7 @@ func TestVersionResolver_UpdateVersion(t *testing.T) {
 			v.latestReleaseFetcher = tt.latestReleaseFetcher
 			v.releasesFetcher = tt.releaseFetcher
 
-			got, err := v.UpdateVersion(context.Background(), binny.VersionIntent{Want: tt.version, Constraint: tt.constraint})
+			got, err := v.UpdateVersion(context.Background(), binny.VersionIntent{Want: tt.version, Constraint: tt.constraint, Scheme: tt.scheme})
 			tt.wantErr(t, err)
 			assert.Equal(t, tt.want, got)
 		})
@@ -186,6 +241,9 @@ func Test_filterToLatestVersion(t *testing.T) {
 		name              string
 		releases          []ghRelease
 		versionConstraint string
+		prerelease        binny.PrereleasePolicy
+		tagPattern        string
+		tagTemplate       string
 		want              *ghRelease
 		wantErr           require.ErrorAssertionFunc
 	}{
@@ -268,19 +326,261 @@ func Test_filterToLatestVersion(t *testing.T) {
 				IsDraft:  boolRef(false),
 			},
 		},
+		{
+			name:              "tag pattern selects component tags and yields versions",
+			versionConstraint: "< 2.0.0",
+			tagPattern:        "^cli/(v.*)$",
+			tagTemplate:       "cli/{{ .Version }}",
+			releases: []ghRelease{
+				{
+					Tag:      "server/v3.0.0",
+					IsLatest: boolRef(true),
+				},
+				{
+					Tag: "cli/v2.0.0",
+				},
+				{
+					Tag: "cli/v1.2.0",
+				},
+				{
+					Tag: "cli/v1.1.0",
+				},
+			},
+			want: &ghRelease{
+				Tag: "v1.2.0",
+			},
+		},
+		{
+			name: "prereleases are excluded by default",
+			releases: []ghRelease{
+				{
+					Tag: "v2.0.0-rc1",
+				},
+				{
+					Tag:          "v1.2.0",
+					IsPrerelease: boolRef(true),
+				},
+				{
+					Tag: "v1.1.0",
+				},
+			},
+			want: &ghRelease{
+				Tag: "v1.1.0",
+			},
+		},
+		{
+			name:       "include prereleases does not trust the latest flag",
+			prerelease: binny.PrereleaseInclude,
+			releases: []ghRelease{
+				{
+					Tag:      "v1.1.0",
+					IsLatest: boolRef(true),
+				},
+				{
+					Tag:          "v1.2.0-beta",
+					IsPrerelease: boolRef(true),
+				},
+			},
+			want: &ghRelease{
+				Tag:          "v1.2.0-beta",
+				IsPrerelease: boolRef(true),
+			},
+		},
+		{
+			name:       "only prereleases honors the github prerelease flag",
+			prerelease: binny.PrereleaseOnly,
+			releases: []ghRelease{
+				{
+					Tag:      "v1.3.0",
+					IsLatest: boolRef(true),
+				},
+				{
+					Tag:          "v1.2.0",
+					IsPrerelease: boolRef(true),
+				},
+				{
+					Tag: "v1.1.0-rc1",
+				},
+			},
+			want: &ghRelease{
+				Tag:          "v1.2.0",
+				IsPrerelease: boolRef(true),
+			},
+		},
+		{
+			name:       "drafts are never candidates",
+			prerelease: binny.PrereleaseInclude,
+			releases: []ghRelease{
+				{
+					Tag:          "v1.2.0-rc1",
+					IsDraft:      boolRef(true),
+					IsPrerelease: boolRef(true),
+				},
+				{
+					Tag: "v1.1.0",
+				},
+			},
+			want: &ghRelease{
+				Tag: "v1.1.0",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.wantErr == nil {
 				tt.wantErr = require.NoError
 			}
-			got, err := filterToLatestVersion(tt.releases, tt.versionConstraint, nil)
+			tags, err := binny.NewTagMapping(tt.tagPattern, tt.tagTemplate)
+			require.NoError(t, err)
+			got, _, err := filterToLatestVersion(tt.releases, binny.VersionFilter{Constraint: tt.versionConstraint, Prerelease: tt.prerelease, Tags: tags}, nil, "")
 			tt.wantErr(t, err)
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
+func Test_filterToLatestVersion_strategies(t *testing.T) {
+	now := time.Now()
+	older := now.Add(-30 * 24 * time.Hour)
+	newer := now.Add(-1 * 24 * time.Hour)
+
+	// the maintainers keep the 1.x line marked as latest and published a backport after the 2.0.0 release
+	releases := []ghRelease{
+		{Tag: "1.9.1", Date: &newer, IsLatest: boolRef(true)},
+		{Tag: "2.0.0", Date: &older, IsLatest: boolRef(false)},
+		{Tag: "1.9.0", Date: &older, IsLatest: boolRef(false)},
+	}
+
+	tests := []struct {
+		name       string
+		releases   []ghRelease
+		strategy   binny.LatestStrategy
+		prerelease binny.PrereleasePolicy
+		want       string
+		wantUsed   binny.LatestStrategy
+	}{
+		{
+			name:     "default strategy honors the latest flag",
+			releases: releases,
+			want:     "1.9.1",
+			wantUsed: binny.LatestGitHub,
+		},
+		{
+			name:     "github latest honors the latest flag",
+			releases: releases,
+			strategy: binny.LatestGitHub,
+			want:     "1.9.1",
+			wantUsed: binny.LatestGitHub,
+		},
+		{
+			name: "github latest falls back to the highest version without a flagged release",
+			releases: []ghRelease{
+				{Tag: "1.9.1", Date: &newer},
+				{Tag: "2.0.0", Date: &older},
+			},
+			strategy: binny.LatestGitHub,
+			want:     "2.0.0",
+			wantUsed: binny.LatestHighestSemver,
+		},
+		{
+			name:       "github latest falls back to the highest version when prereleases are candidates",
+			releases:   releases,
+			strategy:   binny.LatestGitHub,
+			prerelease: binny.PrereleaseInclude,
+			want:       "2.0.0",
+			wantUsed:   binny.LatestHighestSemver,
+		},
+		{
+			name:     "highest semver ignores the latest flag",
+			releases: releases,
+			strategy: binny.LatestHighestSemver,
+			want:     "2.0.0",
+			wantUsed: binny.LatestHighestSemver,
+		},
+		{
+			name:     "newest date picks the most recently published release",
+			releases: releases,
+			strategy: binny.LatestNewestDate,
+			want:     "1.9.1",
+			wantUsed: binny.LatestNewestDate,
+		},
+		{
+			name: "newest date prefers releases with a publish date",
+			releases: []ghRelease{
+				{Tag: "3.0.0"},
+				{Tag: "2.0.0", Date: &older},
+			},
+			strategy: binny.LatestNewestDate,
+			want:     "2.0.0",
+			wantUsed: binny.LatestNewestDate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, used, err := filterToLatestVersion(tt.releases, binny.VersionFilter{Prerelease: tt.prerelease}, nil, tt.strategy)
+			require.NoError(t, err)
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want, got.Tag)
+			assert.Equal(t, tt.wantUsed, used)
+		})
+	}
+}
+
+func TestVersionResolver_ResolveVersionWithStrategy(t *testing.T) {
+	tests := []struct {
+		name                 string
+		version              string
+		strategy             binny.LatestStrategy
+		latestReleaseFetcher func(ctx context.Context, user, repo string) (*ghRelease, error)
+		want                 string
+		wantUsed             binny.LatestStrategy
+	}{
+		{
+			name:    "latest release from the facade",
+			version: "latest",
+			latestReleaseFetcher: func(_ context.Context, _, _ string) (*ghRelease, error) {
+				return &ghRelease{Tag: "1.9.1"}, nil
+			},
+			want:     "1.9.1",
+			wantUsed: binny.LatestGitHub,
+		},
+		{
+			name:     "highest semver skips the facade",
+			version:  "latest",
+			strategy: binny.LatestHighestSemver,
+			latestReleaseFetcher: func(_ context.Context, _, _ string) (*ghRelease, error) {
+				t.Fatal("should not have been called")
+				return nil, nil
+			},
+			want:     "2.0.0",
+			wantUsed: binny.LatestHighestSemver,
+		},
+		{
+			name:     "pinned versions have no strategy",
+			version:  "1.0.0",
+			strategy: binny.LatestNewestDate,
+			want:     "1.0.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVersionResolver(VersionResolutionParameters{Repo: "anchore/binny"})
+			v.latestReleaseFetcher = tt.latestReleaseFetcher
+			v.releasesFetcher = func(_ context.Context, _, _ string) ([]ghRelease, error) {
+				return []ghRelease{
+					{Tag: "1.9.1", IsLatest: boolRef(true)},
+					{Tag: "2.0.0", IsLatest: boolRef(false)},
+				}, nil
+			}
+
+			got, used, err := v.ResolveVersionWithStrategy(context.Background(), binny.VersionIntent{Want: tt.version, LatestStrategy: tt.strategy})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantUsed, used)
+		})
+	}
+}
+
 func Test_filterToLatestVersion_withCooldown(t *testing.T) {
 	now := time.Now()
 	oldDate := now.Add(-14 * 24 * time.Hour) // 14 days ago
@@ -376,7 +676,7 @@ func Test_filterToLatestVersion_withCooldown(t *testing.T) {
 			if tt.wantErr == nil {
 				tt.wantErr = require.NoError
 			}
-			got, err := filterToLatestVersion(tt.releases, tt.versionConstraint, tt.cutoff)
+			got, _, err := filterToLatestVersion(tt.releases, binny.VersionFilter{Constraint: tt.versionConstraint}, tt.cutoff, "")
 			tt.wantErr(t, err)
 			assert.Equal(t, tt.want, got)
 		})
@@ -9,8 +9,11 @@ import (
 	"runtime"
 	"strings"
 
+	"golang.org/x/mod/semver"
+
 	"github.com/anchore/binny"
 	"github.com/anchore/binny/internal"
+	"github.com/anchore/binny/internal/gosumdb"
 	"github.com/anchore/binny/internal/log"
 )
 
@@ -35,6 +38,9 @@ type InstallerParameters struct {
 	Env        []string   `json:"env,omitempty" yaml:"env,omitempty" mapstructure:"env"`
 	Source     SourceMode `json:"source,omitempty" yaml:"source,omitempty" mapstructure:"source"`
 	RepoURL    string     `json:"repo-url,omitempty" yaml:"repo-url,omitempty" mapstructure:"repo-url"`
+	// RequireChecksumDatabase fails the build when the module source cannot be verified against the go checksum
+	// database (e.g. as required by the require-signature policy).
+	RequireChecksumDatabase bool `json:"-" yaml:"-" mapstructure:"-"`
 }
 
 // Installer builds Go binaries from source code obtained via git or go proxy.
@@ -42,6 +48,7 @@ type Installer struct {
 	config        InstallerParameters
 	goBuildRunner func(ctx context.Context, workDir, outputPath, entrypoint, ldflags string, args, env []string) error
 	sourceGetter  func(ctx context.Context, module, version, repoURL string, mode SourceMode) (workDir string, cleanup func(), err error)
+	checksumDB    gosumdb.Verifier
 }
 
 // NewInstaller creates a new go-build installer with the given configuration.
@@ -54,9 +61,28 @@ func NewInstaller(cfg InstallerParameters) Installer {
 		config:        cfg,
 		goBuildRunner: runGoBuild,
 		sourceGetter:  getSource,
+		checksumDB:    gosumdb.ConfigFromEnv(),
 	}
 }
 
+// sourceURL describes where the source code was obtained from: the local module, the git repository, or the go module
+// (from the go proxy) at the given version.
+func (i Installer) sourceURL(version string, isLocal bool) string {
+	if isLocal {
+		return i.config.Module
+	}
+	if normalizeSourceMode(i.config.Source) == SourceModeGit {
+		repoURL := i.config.RepoURL
+		if repoURL == "" {
+			repoURL, _ = DeriveRepoURL(i.config.Module)
+		}
+		if repoURL != "" {
+			return fmt.Sprintf("%s@%s", repoURL, version)
+		}
+	}
+	return fmt.Sprintf("%s@%s", i.config.Module, version)
+}
+
 // InstallTo builds the Go module and places the resulting binary in destDir.
 func (i Installer) InstallTo(ctx context.Context, version, destDir string) (string, error) {
 	ctx, lgr := log.WithNested(ctx, "tool", fmt.Sprintf("%s@%s", i.config.Module, version))
@@ -79,8 +105,17 @@ func (i Installer) InstallTo(ctx context.Context, version, destDir string) (stri
 		// for local modules, use the module path directly as the working directory
 		workDir = i.config.Module
 		cleanup = func() {} // no cleanup needed for local modules
+		internal.ChecksumRecordFromContext(ctx).Verified("local source")
 	} else {
 		lgr.WithFields("module", i.config.Module, "version", version, "source", string(i.config.Source)).Debug("building go module from source")
+		if reason := gosumdb.Unverifiable(i.checksumDB, i.config.Module, version); reason != "" {
+			if i.config.RequireChecksumDatabase {
+				return "", fmt.Errorf("go module source %s@%s cannot be verified against the go checksum database: %s", i.config.Module, version, reason)
+			}
+			if err := internal.ChecksumRecordFromContext(ctx).Missing(fmt.Sprintf("go module source %s@%s", i.config.Module, version)); err != nil {
+				return "", err
+			}
+		}
 		// get source code for remote modules
 		var err error
 		workDir, cleanup, err = i.sourceGetter(ctx, i.config.Module, version, i.config.RepoURL, i.config.Source)
@@ -88,12 +123,19 @@ func (i Installer) InstallTo(ctx context.Context, version, destDir string) (stri
 			return "", fmt.Errorf("failed to get source: %w", err)
 		}
 	}
+	internal.ChecksumRecordFromContext(ctx).SetSourceURL(i.sourceURL(version, isLocal))
 	defer func() {
 		if cleanup != nil {
 			cleanup()
 		}
 	}()
 
+	if !isLocal {
+		if err := i.verifySource(ctx, workDir, version); err != nil {
+			return "", err
+		}
+	}
+
 	// template ldflags
 	ldflags, err := internal.TemplateFlags(i.config.LDFlags, version)
 	if err != nil {
@@ -123,6 +165,40 @@ func (i Installer) InstallTo(ctx context.Context, version, destDir string) (stri
 	return binPath, nil
 }
 
+// verifySource computes the hash of the module source the same way as the go command does for a module zip, and checks
+// it against the go checksum database. This detects a tampered git mirror or go proxy.
+func (i Installer) verifySource(ctx context.Context, workDir, version string) error {
+	lgr := log.FromContext(ctx)
+	if !semver.IsValid(version) {
+		lgr.WithFields("module", i.config.Module, "version", version).Debug("not a module version, skipping checksum database verification")
+		return nil
+	}
+
+	hash, err := moduleHash(workDir, i.config.Module, version)
+	if err != nil {
+		if !i.checksumDB.Enabled(i.config.Module) {
+			// nothing to verify against, so the hash is only informational
+			lgr.WithFields("module", i.config.Module, "error", err).Debug("unable to hash module source")
+			return nil
+		}
+		return err
+	}
+
+	record := internal.ChecksumRecordFromContext(ctx)
+	record.SetModuleHash(hash)
+
+	if !i.checksumDB.Enabled(i.config.Module) {
+		lgr.WithFields("module", i.config.Module, "hash", hash).Debug("module is excluded from checksum database verification")
+		return nil
+	}
+
+	if err := i.checksumDB.Verify(ctx, i.config.Module, version, hash); err != nil {
+		return fmt.Errorf("failed to verify source: %w", err)
+	}
+	record.Verified(fmt.Sprintf("go checksum database %s", i.checksumDB.Name()))
+	return nil
+}
+
 // IsLocalModule returns true if the module path refers to a local filesystem path.
 func IsLocalModule(module string) bool {
 	return strings.HasPrefix(module, ".") || strings.HasPrefix(module, "/")
@@ -3,14 +3,17 @@ package gobuild
 import (
 	"context"
 	"errors"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/anchore/binny/internal"
+	"github.com/anchore/binny/internal/gosumdb"
 )
 
 func TestIsLocalModule(t *testing.T) {
@@ -357,6 +360,7 @@ func TestInstaller_InstallTo(t *testing.T) {
 				config:        tt.config,
 				goBuildRunner: mockRunner,
 				sourceGetter:  mockSourceGetter,
+				checksumDB:    gosumdb.Config{GoSumDB: "off"},
 			}
 
 			destDir := "/dest"
@@ -418,6 +422,7 @@ func TestInstaller_InstallTo_SourceGetterError(t *testing.T) {
 		},
 		goBuildRunner: mockRunner,
 		sourceGetter:  mockSourceGetter,
+		checksumDB:    gosumdb.Config{GoSumDB: "off"},
 	}
 
 	_, err := installer.InstallTo(context.Background(), "v1.0.0", "/dest")
@@ -425,3 +430,121 @@ func TestInstaller_InstallTo_SourceGetterError(t *testing.T) {
 	require.Contains(t, err.Error(), "failed to get source")
 	require.Contains(t, err.Error(), "simulated source getter error")
 }
+
+type fakeChecksumDB struct {
+	enabled bool
+	hashes  map[string]string
+}
+
+func (f fakeChecksumDB) Enabled(string) bool { return f.enabled }
+
+func (f fakeChecksumDB) Name() string { return "sum.example.com" }
+
+func (f fakeChecksumDB) Verify(_ context.Context, module, version, hash string) error {
+	if f.hashes[module+"@"+version] != hash {
+		return errors.New("hash mismatch")
+	}
+	return nil
+}
+
+func TestInstaller_InstallTo_checksumDatabase(t *testing.T) {
+	const module = "github.com/owner/repo"
+
+	source := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(source, "go.mod"), []byte("module "+module+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "main.go"), []byte("package main\n"), 0o600))
+
+	hash, err := gosumdb.HashDir(source, module, "v1.0.0")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		version        string
+		db             fakeChecksumDB
+		required       bool
+		requireDB      bool
+		wantSource     string
+		wantModuleHash string
+		wantErr        require.ErrorAssertionFunc
+	}{
+		{
+			name:           "source matches the checksum database",
+			version:        "v1.0.0",
+			db:             fakeChecksumDB{enabled: true, hashes: map[string]string{module + "@v1.0.0": hash}},
+			required:       true,
+			wantSource:     "go checksum database sum.example.com",
+			wantModuleHash: hash,
+		},
+		{
+			name:    "tampered source",
+			version: "v1.0.0",
+			db:      fakeChecksumDB{enabled: true, hashes: map[string]string{module + "@v1.0.0": "h1:other="}},
+			wantErr: require.Error,
+		},
+		{
+			name:           "excluded from the checksum database",
+			version:        "v1.0.0",
+			db:             fakeChecksumDB{enabled: false},
+			wantModuleHash: hash,
+		},
+		{
+			name:     "excluded from the checksum database when checksums are required",
+			version:  "v1.0.0",
+			db:       fakeChecksumDB{enabled: false},
+			required: true,
+			wantErr:  require.Error,
+		},
+		{
+			name:     "branch when checksums are required",
+			version:  "main",
+			db:       fakeChecksumDB{enabled: true},
+			required: true,
+			wantErr:  require.Error,
+		},
+		{
+			name:      "excluded from the checksum database when signatures are required",
+			version:   "v1.0.0",
+			db:        fakeChecksumDB{enabled: false},
+			requireDB: true,
+			wantErr:   require.Error,
+		},
+		{
+			name:      "branch when signatures are required",
+			version:   "main",
+			db:        fakeChecksumDB{enabled: true},
+			requireDB: true,
+			wantErr:   require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			built := false
+			installer := Installer{
+				config: InstallerParameters{Module: module, RequireChecksumDatabase: tt.requireDB},
+				goBuildRunner: func(_ context.Context, _, _, _, _ string, _, _ []string) error {
+					built = true
+					return nil
+				},
+				sourceGetter: func(_ context.Context, _, _, _ string, _ SourceMode) (string, func(), error) {
+					return source, func() {}, nil
+				},
+				checksumDB: tt.db,
+			}
+
+			ctx, record := internal.WithChecksumRecord(context.Background(), "repo", tt.required)
+			_, err := installer.InstallTo(ctx, tt.version, t.TempDir())
+			tt.wantErr(t, err)
+			if err != nil {
+				assert.False(t, built, "unverified source must not be built")
+				return
+			}
+			assert.True(t, built)
+			assert.Equal(t, tt.wantSource, record.Source())
+			assert.Equal(t, tt.wantModuleHash, record.ModuleHash())
+		})
+	}
+}
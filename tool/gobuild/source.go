@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+
+	"github.com/anchore/binny/internal/gosumdb"
 	"github.com/anchore/binny/internal/log"
 )
 
@@ -63,6 +68,8 @@ func cloneSource(ctx context.Context, module, version, repoURL string) (string,
 		}
 	}
 
+	version = gitRevision(version)
+
 	// try shallow clone with --branch first (works for tags and branches)
 	lgr.WithFields("repo", repoURL, "version", version).Debug("cloning repository")
 
@@ -79,6 +86,19 @@ func cloneSource(ctx context.Context, module, version, repoURL string) (string,
 	return tempDir, cleanup, nil
 }
 
+// gitRevision returns the git revision for a version. A pseudo-version (e.g. resolved from a branch by the go proxy)
+// does not exist as a tag, so the commit it refers to is used instead.
+func gitRevision(version string) string {
+	if !module.IsPseudoVersion(version) {
+		return version
+	}
+	rev, err := module.PseudoVersionRev(version)
+	if err != nil {
+		return version
+	}
+	return rev
+}
+
 // shallowClone performs a shallow clone (depth=1) for a specific tag or branch.
 func shallowClone(ctx context.Context, repoURL, version, destDir string) error {
 	args := []string{"clone", "--depth", "1", "--branch", version, repoURL, destDir}
@@ -250,3 +270,57 @@ func copyFileWithRoot(srcRoot, dstRoot *os.Root, relPath string, perm os.FileMod
 	_, err = io.Copy(dstFile, srcFile)
 	return err
 }
+
+// moduleHash returns the hash ("h1:...") of the module within the source directory.
+func moduleHash(workDir, modulePath, version string) (string, error) {
+	moduleDir, err := findModuleDir(workDir, modulePath)
+	if err != nil {
+		return "", err
+	}
+	return gosumdb.HashDir(moduleDir, modulePath, version)
+}
+
+// findModuleDir returns the directory within the source that holds the module (the one with a go.mod declaring the
+// module path). Modules without a go.mod are taken to be at the root.
+func findModuleDir(root, modulePath string) (string, error) {
+	var found string
+	var hasGoMod bool
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "vendor", "testdata":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != "go.mod" {
+			return nil
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		hasGoMod = true
+		if modfile.ModulePath(contents) == modulePath {
+			found = filepath.Dir(path)
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to find module %q in source: %w", modulePath, err)
+	}
+
+	switch {
+	case found != "":
+		return found, nil
+	case !hasGoMod:
+		return root, nil
+	default:
+		return "", fmt.Errorf("no go.mod for module %q found in source", modulePath)
+	}
+}
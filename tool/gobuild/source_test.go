@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -70,6 +71,41 @@ func TestNormalizeSourceMode(t *testing.T) {
 	}
 }
 
+func TestGitRevision(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{
+			name:    "tag",
+			version: "v1.2.3",
+			want:    "v1.2.3",
+		},
+		{
+			name:    "branch",
+			version: "main",
+			want:    "main",
+		},
+		{
+			name:    "pseudo-version",
+			version: "v0.0.0-20240601120000-abcdef123456",
+			want:    "abcdef123456",
+		},
+		{
+			name:    "pseudo-version after a prerelease",
+			version: "v1.2.4-rc.1.0.20240601120000-abcdef123456",
+			want:    "abcdef123456",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, gitRevision(tt.version))
+		})
+	}
+}
+
 func TestCopyDir(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("permission tests not supported on Windows")
@@ -187,3 +223,64 @@ func TestCopyDir_Symlinks(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "subdir", target)
 }
+
+func Test_findModuleDir(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, contents string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, path), []byte(contents), 0o600))
+	}
+	write("go.mod", "module github.com/owner/repo\n")
+	write("tools/go.mod", "module github.com/owner/repo/tools\n")
+	write("vendor/github.com/other/go.mod", "module github.com/other\n")
+
+	tests := []struct {
+		name    string
+		root    string
+		module  string
+		want    string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:   "module at the root",
+			root:   root,
+			module: "github.com/owner/repo",
+			want:   root,
+		},
+		{
+			name:   "nested module",
+			root:   root,
+			module: "github.com/owner/repo/tools",
+			want:   filepath.Join(root, "tools"),
+		},
+		{
+			name:    "vendored modules are not considered",
+			root:    root,
+			module:  "github.com/other",
+			wantErr: require.Error,
+		},
+		{
+			name:   "no go.mod",
+			root:   t.TempDir(),
+			module: "github.com/owner/legacy",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := findModuleDir(tt.root, tt.module)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			want := tt.want
+			if want == "" {
+				want = tt.root
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package goinstall
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/anchore/binny"
 	"github.com/anchore/binny/internal"
+	"github.com/anchore/binny/internal/gosumdb"
 	"github.com/anchore/binny/internal/log"
 )
 
@@ -21,17 +23,24 @@ type InstallerParameters struct {
 	LDFlags    []string `json:"ldflags" yaml:"ldflags" mapstructure:"ldflags"`
 	Args       []string `json:"args" yaml:"args" mapstructure:"args"`
 	Env        []string `json:"env" yaml:"env" mapstructure:"env"`
+	// RequireChecksumDatabase fails the installation when the module cannot be verified against the go checksum
+	// database (e.g. as required by the require-signature policy).
+	RequireChecksumDatabase bool `json:"-" yaml:"-" mapstructure:"-"`
 }
 
 type Installer struct {
-	config          InstallerParameters
-	goInstallRunner func(spec, ldflags string, args []string, env []string, destDir string, isLocal bool, binName string) error
+	config           InstallerParameters
+	goInstallRunner  func(spec, ldflags string, args []string, env []string, destDir string, isLocal bool, binName string) error
+	moduleDownloader func(ctx context.Context, module, version string, env []string) (zipPath string, err error)
+	checksumDB       gosumdb.Verifier
 }
 
 func NewInstaller(cfg InstallerParameters) Installer {
 	return Installer{
-		config:          cfg,
-		goInstallRunner: runGoInstall,
+		config:           cfg,
+		goInstallRunner:  runGoInstall,
+		moduleDownloader: downloadModule,
+		checksumDB:       gosumdb.ConfigFromEnv(),
 	}
 }
 
@@ -51,9 +60,11 @@ func (i Installer) InstallTo(ctx context.Context, version, destDir string) (stri
 	if isLocal {
 		spec = path
 		lgr.WithFields("module", i.config.Module, "version", version).Debug("installing go module (local)")
+		internal.ChecksumRecordFromContext(ctx).Verified("local source")
 	} else {
 		lgr.WithFields("module", i.config.Module, "version", version).Debug("installing go module (remote)")
 	}
+	internal.ChecksumRecordFromContext(ctx).SetSourceURL(spec)
 
 	ldflags, err := internal.TemplateFlags(i.config.LDFlags, version)
 	if err != nil {
@@ -74,6 +85,12 @@ func (i Installer) InstallTo(ctx context.Context, version, destDir string) (stri
 		return "", fmt.Errorf("failed to template env: %v", err)
 	}
 
+	if !isLocal {
+		if err := i.verifyModule(ctx, spec, version, env); err != nil {
+			return "", err
+		}
+	}
+
 	if err := i.goInstallRunner(spec, ldflags, args, env, destDir, isLocal, binName); err != nil {
 		return "", fmt.Errorf("failed to install: %v", err)
 	}
@@ -81,6 +98,65 @@ func (i Installer) InstallTo(ctx context.Context, version, destDir string) (stri
 	return binPath, nil
 }
 
+// verifyModule downloads the module ahead of "go install" (which then builds from the module cache) and checks the
+// module zip against the go checksum database.
+func (i Installer) verifyModule(ctx context.Context, spec, version string, env []string) error {
+	record := internal.ChecksumRecordFromContext(ctx)
+	if reason := gosumdb.Unverifiable(i.checksumDB, i.config.Module, version); reason != "" {
+		if i.config.RequireChecksumDatabase {
+			return fmt.Errorf("go module %q cannot be verified against the go checksum database: %s", spec, reason)
+		}
+		return record.Missing(fmt.Sprintf("go module %q", spec))
+	}
+
+	zipPath, err := i.moduleDownloader(ctx, i.config.Module, version, env)
+	if err != nil {
+		return fmt.Errorf("failed to download module: %v", err)
+	}
+
+	hash, err := gosumdb.HashZip(zipPath)
+	if err != nil {
+		return err
+	}
+
+	if err := i.checksumDB.Verify(ctx, i.config.Module, version, hash); err != nil {
+		return fmt.Errorf("failed to verify module: %v", err)
+	}
+
+	record.SetModuleHash(hash)
+	record.Verified(fmt.Sprintf("go checksum database %s", i.checksumDB.Name()))
+	return nil
+}
+
+// downloadModule downloads the module into the module cache, returning the path to the module zip.
+func downloadModule(ctx context.Context, module, version string, userEnv []string) (string, error) {
+	args := []string{"mod", "download", "-json", module + "@" + version}
+	log.Trace("running: go " + strings.Join(args, " "))
+
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Env = append(os.Environ(), userEnv...)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("go mod download failed: %v\nOutput: %s", err, output)
+	}
+
+	var downloadInfo struct {
+		Zip   string `json:"Zip"`
+		Error string `json:"Error"`
+	}
+	if err := json.Unmarshal(output, &downloadInfo); err != nil {
+		return "", fmt.Errorf("failed to parse go mod download output: %w", err)
+	}
+	if downloadInfo.Error != "" {
+		return "", fmt.Errorf("go mod download error: %s", downloadInfo.Error)
+	}
+	if downloadInfo.Zip == "" {
+		return "", fmt.Errorf("go mod download did not return a module zip")
+	}
+	return downloadInfo.Zip, nil
+}
+
 func runGoInstall(spec, ldflags string, userArgs, userEnv []string, destDir string, isLocal bool, binName string) error {
 	var args []string
 	if isLocal {
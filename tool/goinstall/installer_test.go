@@ -1,15 +1,20 @@
 package goinstall
 
 import (
+	"archive/zip"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/anchore/binny/internal"
+	"github.com/anchore/binny/internal/gosumdb"
 )
 
 func Test_templateFlags(t *testing.T) {
@@ -248,6 +253,7 @@ func TestInstaller_InstallTo(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			i := NewInstaller(tt.fields.config)
 			i.goInstallRunner = tt.fields.goInstallRunner
+			i.checksumDB = gosumdb.Config{GoSumDB: "off"}
 
 			got, err := i.InstallTo(context.Background(), tt.args.version, tt.args.destDir)
 			got = strings.ReplaceAll(got, string(os.PathSeparator), "/")
@@ -258,3 +264,120 @@ func TestInstaller_InstallTo(t *testing.T) {
 		})
 	}
 }
+
+type fakeChecksumDB struct {
+	enabled bool
+	hashes  map[string]string
+}
+
+func (f fakeChecksumDB) Enabled(string) bool { return f.enabled }
+
+func (f fakeChecksumDB) Name() string { return "sum.example.com" }
+
+func (f fakeChecksumDB) Verify(_ context.Context, module, version, hash string) error {
+	if f.hashes[module+"@"+version] != hash {
+		return errors.New("hash mismatch")
+	}
+	return nil
+}
+
+func TestInstaller_InstallTo_checksumDatabase(t *testing.T) {
+	const module = "github.com/anchore/binny"
+
+	zipPath := filepath.Join(t.TempDir(), "v1.0.0.zip")
+	fh, err := os.Create(zipPath)
+	require.NoError(t, err)
+	w := zip.NewWriter(fh)
+	f, err := w.Create(module + "@v1.0.0/go.mod")
+	require.NoError(t, err)
+	_, err = f.Write([]byte("module " + module + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, fh.Close())
+
+	hash, err := gosumdb.HashZip(zipPath)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		version        string
+		db             fakeChecksumDB
+		required       bool
+		requireDB      bool
+		wantSource     string
+		wantModuleHash string
+		wantErr        require.ErrorAssertionFunc
+	}{
+		{
+			name:           "module matches the checksum database",
+			version:        "v1.0.0",
+			db:             fakeChecksumDB{enabled: true, hashes: map[string]string{module + "@v1.0.0": hash}},
+			required:       true,
+			wantSource:     "go checksum database sum.example.com",
+			wantModuleHash: hash,
+		},
+		{
+			name:    "tampered module",
+			version: "v1.0.0",
+			db:      fakeChecksumDB{enabled: true, hashes: map[string]string{module + "@v1.0.0": "h1:other="}},
+			wantErr: require.Error,
+		},
+		{
+			name:    "excluded from the checksum database",
+			version: "v1.0.0",
+			db:      fakeChecksumDB{enabled: false},
+		},
+		{
+			name:     "excluded from the checksum database when checksums are required",
+			version:  "v1.0.0",
+			db:       fakeChecksumDB{enabled: false},
+			required: true,
+			wantErr:  require.Error,
+		},
+		{
+			name:      "excluded from the checksum database when signatures are required",
+			version:   "v1.0.0",
+			db:        fakeChecksumDB{enabled: false},
+			requireDB: true,
+			wantErr:   require.Error,
+		},
+		{
+			name:      "branch cannot be verified when signatures are required",
+			version:   "main",
+			db:        fakeChecksumDB{enabled: true},
+			requireDB: true,
+			wantErr:   require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			installed := false
+			i := NewInstaller(InstallerParameters{Module: module, Entrypoint: "cmd/binny", RequireChecksumDatabase: tt.requireDB})
+			i.checksumDB = tt.db
+			i.moduleDownloader = func(_ context.Context, m, version string, _ []string) (string, error) {
+				assert.Equal(t, module, m)
+				assert.Equal(t, tt.version, version)
+				return zipPath, nil
+			}
+			i.goInstallRunner = func(_, _ string, _, _ []string, _ string, _ bool, _ string) error {
+				installed = true
+				return nil
+			}
+
+			ctx, record := internal.WithChecksumRecord(context.Background(), "binny", tt.required)
+			_, err := i.InstallTo(ctx, tt.version, t.TempDir())
+			tt.wantErr(t, err)
+			if err != nil {
+				assert.False(t, installed, "an unverified module must not be installed")
+				return
+			}
+			assert.True(t, installed)
+			assert.Equal(t, tt.wantSource, record.Source())
+			assert.Equal(t, tt.wantModuleHash, record.ModuleHash())
+		})
+	}
+}
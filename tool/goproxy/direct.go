@@ -0,0 +1,141 @@
+package goproxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/net/html"
+
+	"github.com/anchore/binny/internal"
+	"github.com/anchore/binny/internal/log"
+	"github.com/anchore/binny/tool/git"
+)
+
+// hosts where the repository root is always the first three path elements of the module path
+var wellKnownHosts = []string{"github.com", "gitlab.com", "bitbucket.org"}
+
+// repoRoot describes where the source for a module lives.
+type repoRoot struct {
+	// prefix is the module path prefix that corresponds to the root of the repository
+	prefix string
+	// url is the URL of the git repository
+	url string
+}
+
+// fetchDirectVersions lists the versions of a module by reading the tags from the module's git repository, which
+// is what the go command does when GOPROXY (or GONOPROXY/GOPRIVATE) selects "direct".
+func fetchDirectVersions(ctx context.Context, modulePath string) ([]string, error) {
+	root, err := findRepoRoot(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	log.FromContext(ctx).WithFields("module", modulePath, "repo", root.url).Trace("resolving versions directly from version control")
+
+	tags, err := git.ListRemoteTags(ctx, root.url)
+	if err != nil {
+		return nil, err
+	}
+
+	return versionsFromTags(modulePath, root.prefix, tags), nil
+}
+
+// versionsFromTags selects the tags that are valid versions for the given module. Modules in a subdirectory of the
+// repository use tags prefixed with that directory (e.g. "sub/v1.2.3"), and modules with a major version suffix
+// (e.g. "/v2") only consider tags with a matching major version.
+func versionsFromTags(modulePath, rootPrefix string, tags []string) []string {
+	pathPrefix, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		pathPrefix, pathMajor = modulePath, ""
+	}
+
+	var tagPrefix string
+	if subdir := strings.TrimPrefix(strings.TrimPrefix(pathPrefix, rootPrefix), "/"); subdir != "" {
+		tagPrefix = subdir + "/"
+	}
+
+	var versions []string
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, tagPrefix) {
+			continue
+		}
+		v := strings.TrimPrefix(tag, tagPrefix)
+		if !semver.IsValid(v) || semver.Build(v) != "" {
+			continue
+		}
+		if module.CheckPathMajor(v, pathMajor) != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// findRepoRoot determines the git repository for a module path. Well known hosts are derived from the path, all
+// others are discovered via the "go-import" meta tag (the same mechanism the go command uses for vanity paths).
+func findRepoRoot(ctx context.Context, modulePath string) (*repoRoot, error) {
+	for _, host := range wellKnownHosts {
+		if !strings.HasPrefix(modulePath, host+"/") {
+			continue
+		}
+		fields := strings.Split(modulePath, "/")
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid module path %q: need at least %s/owner/repo", modulePath, host)
+		}
+		prefix := strings.Join(fields[:3], "/")
+		return &repoRoot{prefix: prefix, url: "https://" + prefix}, nil
+	}
+
+	url := fmt.Sprintf("https://%s?go-get=1", modulePath)
+	reader, err := internal.DownloadURL(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to discover repository for module %q: %w", modulePath, err)
+	}
+	defer reader.Close()
+
+	root := findGoImport(reader, modulePath)
+	if root == nil {
+		return nil, fmt.Errorf("no git repository found for module %q (missing go-import meta tag)", modulePath)
+	}
+	return root, nil
+}
+
+// findGoImport searches an HTML document for a <meta name="go-import" content="prefix vcs url"> tag that applies
+// to the given module path.
+func findGoImport(reader io.Reader, modulePath string) *repoRoot {
+	tokenizer := html.NewTokenizer(reader)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if token.Data != "meta" {
+				continue
+			}
+
+			var name, content string
+			for _, attr := range token.Attr {
+				switch attr.Key {
+				case "name":
+					name = attr.Val
+				case "content":
+					content = attr.Val
+				}
+			}
+
+			fields := strings.Fields(content)
+			if name != "go-import" || len(fields) != 3 || fields[1] != "git" {
+				continue
+			}
+
+			if modulePath == fields[0] || strings.HasPrefix(modulePath, fields[0]+"/") {
+				return &repoRoot{prefix: fields[0], url: fields[2]}
+			}
+		}
+	}
+}
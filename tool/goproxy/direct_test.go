@@ -0,0 +1,67 @@
+package goproxy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_versionsFromTags(t *testing.T) {
+	tags := []string{
+		"v1.0.0",
+		"v1.1.0",
+		"v2.0.0",
+		"v2.1.0+incompatible",
+		"sub/v0.3.0",
+		"sub/v2.0.0",
+		"not-a-version",
+	}
+
+	tests := []struct {
+		name       string
+		module     string
+		rootPrefix string
+		want       []string
+	}{
+		{
+			name:       "repo root module",
+			module:     "github.com/anchore/tool",
+			rootPrefix: "github.com/anchore/tool",
+			want:       []string{"v1.0.0", "v1.1.0"},
+		},
+		{
+			name:       "major version suffix",
+			module:     "github.com/anchore/tool/v2",
+			rootPrefix: "github.com/anchore/tool",
+			want:       []string{"v2.0.0"},
+		},
+		{
+			name:       "module in a subdirectory",
+			module:     "github.com/anchore/tool/sub",
+			rootPrefix: "github.com/anchore/tool",
+			want:       []string{"v0.3.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, versionsFromTags(tt.module, tt.rootPrefix, tags))
+		})
+	}
+}
+
+func Test_findGoImport(t *testing.T) {
+	doc := `<html><head>
+<meta name="go-source" content="go.example.com/tool https://git.example.com/tool">
+<meta name="go-import" content="go.example.com/other git https://git.example.com/other.git">
+<meta name="go-import" content="go.example.com/tool git https://git.example.com/tool.git">
+</head></html>`
+
+	got := findGoImport(strings.NewReader(doc), "go.example.com/tool/cmd/tool")
+	require.NotNil(t, got)
+	assert.Equal(t, "go.example.com/tool", got.prefix)
+	assert.Equal(t, "https://git.example.com/tool.git", got.url)
+
+	assert.Nil(t, findGoImport(strings.NewReader(doc), "go.example.com/missing"))
+}
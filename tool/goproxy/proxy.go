@@ -0,0 +1,129 @@
+package goproxy
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+const (
+	defaultProxyList = "https://proxy.golang.org,direct"
+
+	// proxyDirect indicates that the module should be resolved directly from version control.
+	proxyDirect = "direct"
+
+	// proxyOff indicates that module lookups are disallowed.
+	proxyOff = "off"
+)
+
+// proxySpec is a single entry within a GOPROXY list.
+type proxySpec struct {
+	// url is the base URL of the proxy, or one of the "direct" or "off" keywords.
+	url string
+
+	// fallBackOnError is true when the entry is followed by a "|" separator, meaning any error (not only a
+	// "not found" response) should cause the next entry to be tried.
+	fallBackOnError bool
+}
+
+// statusError is returned when a proxy responds with a non-200 status code.
+type statusError struct {
+	url        string
+	statusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status from go proxy %q: %d %s", e.url, e.statusCode, http.StatusText(e.statusCode))
+}
+
+// isNotFound reports whether the error indicates that the proxy does not know about the module or version, which
+// is the only condition that allows falling back to the next entry when entries are separated by a comma.
+func isNotFound(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.statusCode == http.StatusNotFound || se.statusCode == http.StatusGone
+	}
+	return false
+}
+
+// proxyListForModule determines the ordered list of proxies to consult for the given module. An explicitly configured
+// proxy list takes precedence over the GOPROXY, GONOPROXY, and GOPRIVATE environment variables.
+func proxyListForModule(modulePath, configured string) ([]proxySpec, error) {
+	if configured != "" {
+		return parseProxyList(configured)
+	}
+
+	noProxy := os.Getenv("GONOPROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("GOPRIVATE")
+	}
+
+	if noProxy != "" && module.MatchPrefixPatterns(noProxy, modulePath) {
+		return []proxySpec{{url: proxyDirect}}, nil
+	}
+
+	return parseProxyList(os.Getenv("GOPROXY"))
+}
+
+// parseProxyList parses a GOPROXY value following the same rules as the go command: entries are separated by
+// "," (fall back only when the module is not found) or "|" (fall back on any error), and the "direct" and "off"
+// keywords terminate the list.
+func parseProxyList(value string) ([]proxySpec, error) {
+	if strings.TrimSpace(value) == "" {
+		value = defaultProxyList
+	}
+
+	var specs []proxySpec
+	for value != "" {
+		var entry string
+		var fallBackOnError bool
+		if i := strings.IndexAny(value, ",|"); i >= 0 {
+			entry = value[:i]
+			fallBackOnError = value[i] == '|'
+			value = value[i+1:]
+		} else {
+			entry = value
+			value = ""
+		}
+
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		switch entry {
+		case proxyDirect, proxyOff:
+			// the go command ignores anything after these keywords
+			return append(specs, proxySpec{url: entry, fallBackOnError: fallBackOnError}), nil
+		}
+
+		if !strings.Contains(entry, "://") {
+			// the go command assumes https when no scheme is given
+			entry = "https://" + entry
+		}
+
+		specs = append(specs, proxySpec{
+			url:             strings.TrimSuffix(entry, "/"),
+			fallBackOnError: fallBackOnError,
+		})
+	}
+
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("GOPROXY list is not the empty string, but contains no entries")
+	}
+
+	return specs, nil
+}
+
+// proxyURL renders the URL for the given endpoint (e.g. "@v/list") of a module on a proxy.
+func proxyURL(base, modulePath, endpoint string) (string, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", fmt.Errorf("invalid module path %q: %w", modulePath, err)
+	}
+	return fmt.Sprintf("%s/%s/%s", base, escaped, endpoint), nil
+}
@@ -0,0 +1,155 @@
+package goproxy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseProxyList(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []proxySpec
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:  "empty value uses the default list",
+			value: "",
+			want: []proxySpec{
+				{url: "https://proxy.golang.org"},
+				{url: proxyDirect},
+			},
+		},
+		{
+			name:  "comma and pipe separators",
+			value: "https://athens.example.com|https://proxy.golang.org,direct",
+			want: []proxySpec{
+				{url: "https://athens.example.com", fallBackOnError: true},
+				{url: "https://proxy.golang.org"},
+				{url: proxyDirect},
+			},
+		},
+		{
+			name:  "missing scheme defaults to https and trailing slash is dropped",
+			value: "athens.example.com/",
+			want: []proxySpec{
+				{url: "https://athens.example.com"},
+			},
+		},
+		{
+			name:  "entries after off are ignored",
+			value: "off,https://proxy.golang.org",
+			want: []proxySpec{
+				{url: proxyOff},
+			},
+		},
+		{
+			name:  "entries after direct are ignored",
+			value: "direct|https://proxy.golang.org",
+			want: []proxySpec{
+				{url: proxyDirect, fallBackOnError: true},
+			},
+		},
+		{
+			name:    "only separators",
+			value:   ",|,",
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+			got, err := parseProxyList(tt.value)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_proxyListForModule(t *testing.T) {
+	tests := []struct {
+		name       string
+		module     string
+		configured string
+		env        map[string]string
+		want       []proxySpec
+	}{
+		{
+			name:   "GOPROXY is honored",
+			module: "github.com/anchore/binny",
+			env: map[string]string{
+				"GOPROXY": "https://athens.example.com",
+			},
+			want: []proxySpec{{url: "https://athens.example.com"}},
+		},
+		{
+			name:   "GOPRIVATE selects direct",
+			module: "git.example.com/team/tool",
+			env: map[string]string{
+				"GOPROXY":   "https://athens.example.com",
+				"GOPRIVATE": "*.example.com",
+			},
+			want: []proxySpec{{url: proxyDirect}},
+		},
+		{
+			name:   "GONOPROXY takes precedence over GOPRIVATE",
+			module: "git.example.com/team/tool",
+			env: map[string]string{
+				"GOPROXY":   "https://athens.example.com",
+				"GOPRIVATE": "*.example.com",
+				"GONOPROXY": "none",
+			},
+			want: []proxySpec{{url: "https://athens.example.com"}},
+		},
+		{
+			name:   "non-matching GOPRIVATE uses GOPROXY",
+			module: "github.com/anchore/binny",
+			env: map[string]string{
+				"GOPROXY":   "https://athens.example.com",
+				"GOPRIVATE": "*.example.com",
+			},
+			want: []proxySpec{{url: "https://athens.example.com"}},
+		},
+		{
+			name:       "configured proxy overrides the environment",
+			module:     "git.example.com/team/tool",
+			configured: "https://other.example.com,off",
+			env: map[string]string{
+				"GOPROXY":   "https://athens.example.com",
+				"GOPRIVATE": "*.example.com",
+			},
+			want: []proxySpec{
+				{url: "https://other.example.com"},
+				{url: proxyOff},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"GOPROXY", "GONOPROXY", "GOPRIVATE"} {
+				t.Setenv(key, tt.env[key])
+			}
+			got, err := proxyListForModule(tt.module, tt.configured)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_isNotFound(t *testing.T) {
+	assert.True(t, isNotFound(&statusError{statusCode: http.StatusNotFound}))
+	assert.True(t, isNotFound(&statusError{statusCode: http.StatusGone}))
+	assert.False(t, isNotFound(&statusError{statusCode: http.StatusInternalServerError}))
+	assert.False(t, isNotFound(assert.AnError))
+}
+
+func Test_proxyURL(t *testing.T) {
+	got, err := proxyURL("https://proxy.golang.org", "github.com/BurntSushi/toml", "@v/list")
+	require.NoError(t, err)
+	assert.Equal(t, "https://proxy.golang.org/github.com/!burnt!sushi/toml/@v/list", got)
+}
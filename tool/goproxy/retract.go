@@ -0,0 +1,166 @@
+package goproxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+
+	internalhttp "github.com/anchore/binny/internal/http"
+	"github.com/anchore/binny/internal/log"
+)
+
+// retraction is a range of versions withdrawn by the module author with a "retract" directive.
+type retraction struct {
+	low       string
+	high      string
+	rationale string
+}
+
+// Retraction reports whether the given version of the module has been retracted by the module author, along with
+// the rationale given (if any).
+func (v VersionResolver) Retraction(ctx context.Context, version string) (string, bool, error) {
+	versions, err := v.fetchAvailableVersions(ctx)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get available versions from go proxy: %w", err)
+	}
+
+	retractions, err := v.findRetractions(ctx, versions)
+	if err != nil {
+		return "", false, err
+	}
+
+	rationale, retracted := isRetracted(version, retractions)
+	return rationale, retracted, nil
+}
+
+// removeRetracted drops all retracted versions from the given list. Failing to determine retractions is not fatal,
+// since the go.mod file is not available in all cases (e.g. when resolving directly from version control).
+func (v VersionResolver) removeRetracted(ctx context.Context, versions []string) []string {
+	retractions, err := v.findRetractions(ctx, versions)
+	if err != nil {
+		log.FromContext(ctx).WithFields("module", v.config.Module).Tracef("unable to determine retracted versions: %v", err)
+		return versions
+	}
+	if len(retractions) == 0 {
+		return versions
+	}
+
+	var kept []string
+	for _, version := range versions {
+		if rationale, retracted := isRetracted(version, retractions); retracted {
+			log.FromContext(ctx).WithFields("module", v.config.Module, "version", version, "rationale", rationale).
+				Trace("skipping retracted version")
+			continue
+		}
+		kept = append(kept, version)
+	}
+	return kept
+}
+
+// findRetractions reads the retract directives from the go.mod of the latest version of the module, which is where
+// the go command looks for retractions as well.
+func (v VersionResolver) findRetractions(ctx context.Context, versions []string) ([]retraction, error) {
+	latestVersion := latestModuleVersion(versions)
+	if latestVersion == "" {
+		return nil, nil
+	}
+
+	contents, err := v.modFileFetcher(ctx, v.config.Module, latestVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return retractionsFromModFile(latestVersion, contents)
+}
+
+// latestModuleVersion returns the highest release version, or the highest prerelease if there are no releases.
+// Versions without a go.mod file (+incompatible) are not considered.
+func latestModuleVersion(versions []string) string {
+	var latestRelease, latestPrerelease string
+	for _, version := range versions {
+		if !semver.IsValid(version) || semver.Build(version) != "" {
+			continue
+		}
+		if semver.Prerelease(version) != "" {
+			if semver.Compare(version, latestPrerelease) > 0 {
+				latestPrerelease = version
+			}
+			continue
+		}
+		if semver.Compare(version, latestRelease) > 0 {
+			latestRelease = version
+		}
+	}
+	if latestRelease != "" {
+		return latestRelease
+	}
+	return latestPrerelease
+}
+
+func retractionsFromModFile(version string, contents []byte) ([]retraction, error) {
+	f, err := modfile.ParseLax(version+".mod", contents, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse go.mod for version %q: %w", version, err)
+	}
+
+	var retractions []retraction
+	for _, r := range f.Retract {
+		retractions = append(retractions, retraction{
+			low:       r.Low,
+			high:      r.High,
+			rationale: r.Rationale,
+		})
+	}
+	return retractions, nil
+}
+
+func isRetracted(version string, retractions []retraction) (string, bool) {
+	for _, r := range retractions {
+		if semver.Compare(version, r.low) >= 0 && semver.Compare(version, r.high) <= 0 {
+			return r.rationale, true
+		}
+	}
+	return "", false
+}
+
+// fetchModFile retrieves the go.mod file for a specific version from the first proxy that knows about it.
+func (v VersionResolver) fetchModFile(ctx context.Context, module, version string) ([]byte, error) {
+	return queryProxies(ctx, v.config, func(spec proxySpec) ([]byte, error) {
+		if spec.url == proxyDirect {
+			return nil, fmt.Errorf("go.mod files are not available when resolving %q directly from version control", module)
+		}
+
+		url, err := proxyURL(spec.url, module, "@v/"+version+".mod")
+		if err != nil {
+			return nil, err
+		}
+		return fetchModFileFromURL(ctx, url)
+	})
+}
+
+// fetchModFileFromURL retrieves the /@v/{version}.mod document from a single go proxy.
+func fetchModFileFromURL(ctx context.Context, url string) ([]byte, error) {
+	client := internalhttp.ResolutionClientFromContext(ctx)
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{url: url, statusCode: resp.StatusCode}
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,107 @@
+package goproxy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_latestModuleVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		want     string
+	}{
+		{
+			name:     "highest release",
+			versions: []string{"v1.0.0", "v1.10.0", "v1.9.0", "v2.0.0-rc1"},
+			want:     "v1.10.0",
+		},
+		{
+			name:     "highest prerelease when there are no releases",
+			versions: []string{"v1.0.0-rc1", "v1.0.0-rc2"},
+			want:     "v1.0.0-rc2",
+		},
+		{
+			name:     "incompatible versions have no go.mod",
+			versions: []string{"v1.0.0", "v2.0.0+incompatible"},
+			want:     "v1.0.0",
+		},
+		{
+			name:     "no valid versions",
+			versions: []string{"", "1.0.0"},
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, latestModuleVersion(tt.versions))
+		})
+	}
+}
+
+func Test_isRetracted(t *testing.T) {
+	contents := []byte(`module github.com/anchore/binny
+
+go 1.21
+
+retract (
+	v1.0.1 // published accidentally
+	[v1.2.0, v1.2.3]
+)
+`)
+	retractions, err := retractionsFromModFile("v1.3.0", contents)
+	require.NoError(t, err)
+
+	tests := []struct {
+		version       string
+		wantRetracted bool
+		wantRationale string
+	}{
+		{version: "v1.0.0"},
+		{version: "v1.0.1", wantRetracted: true, wantRationale: "published accidentally"},
+		{version: "v1.2.0", wantRetracted: true},
+		{version: "v1.2.2", wantRetracted: true},
+		{version: "v1.2.3", wantRetracted: true},
+		{version: "v1.2.4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			rationale, retracted := isRetracted(tt.version, retractions)
+			assert.Equal(t, tt.wantRetracted, retracted)
+			assert.Equal(t, tt.wantRationale, rationale)
+		})
+	}
+}
+
+func TestVersionResolver_Retraction(t *testing.T) {
+	v := NewVersionResolver(VersionResolutionParameters{
+		Module: "github.com/anchore/binny",
+	})
+	v.availableVersionsFetcher = func(_ context.Context, _ string) ([]string, error) {
+		return []string{"v1.0.0", "v1.1.0"}, nil
+	}
+	v.modFileFetcher = func(_ context.Context, _, version string) ([]byte, error) {
+		if version != "v1.1.0" {
+			return nil, fmt.Errorf("unexpected version: %s", version)
+		}
+		return []byte("module github.com/anchore/binny\n\nretract v1.0.0 // contains a security issue\n"), nil
+	}
+
+	rationale, retracted, err := v.Retraction(context.Background(), "v1.0.0")
+	require.NoError(t, err)
+	assert.True(t, retracted)
+	assert.Equal(t, "contains a security issue", rationale)
+
+	_, retracted, err = v.Retraction(context.Background(), "v1.1.0")
+	require.NoError(t, err)
+	assert.False(t, retracted)
+}
+
+// noRetractions is a go.mod fetcher for tests that are not concerned with retractions.
+func noRetractions(_ context.Context, module, _ string) ([]byte, error) {
+	return []byte("module " + module + "\n"), nil
+}
@@ -8,13 +8,14 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/Masterminds/semver/v3"
 	"github.com/hashicorp/go-retryablehttp"
+	modulepkg "golang.org/x/mod/module"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/anchore/binny"
-	"github.com/anchore/binny/internal"
 	internalhttp "github.com/anchore/binny/internal/http"
 	"github.com/anchore/binny/internal/log"
 )
@@ -22,15 +23,21 @@ import (
 const latest = "latest"
 
 var _ binny.VersionResolver = (*VersionResolver)(nil)
+var _ binny.RetractionChecker = (*VersionResolver)(nil)
 
 type VersionResolver struct {
 	config                   VersionResolutionParameters
 	availableVersionsFetcher func(ctx context.Context, url string) ([]string, error)
+	directVersionsFetcher    func(ctx context.Context, module string) ([]string, error)
 	versionInfoFetcher       func(ctx context.Context, module, version string) (*versionInfo, error)
+	modFileFetcher           func(ctx context.Context, module, version string) ([]byte, error)
 }
 
 type VersionResolutionParameters struct {
-	Module                 string `json:"module" yaml:"module" mapstructure:"module"`
+	Module string `json:"module" yaml:"module" mapstructure:"module"`
+	// Proxy is a GOPROXY-style list of proxies to use. When set, this takes precedence over the GOPROXY, GONOPROXY,
+	// and GOPRIVATE environment variables.
+	Proxy                  string `json:"proxy" yaml:"proxy" mapstructure:"proxy"`
 	AllowUnresolvedVersion bool   `json:"allow-unresolved-version" yaml:"allow-unresolved-version" mapstructure:"allow-unresolved-version"`
 }
 
@@ -41,29 +48,42 @@ type versionInfo struct {
 }
 
 func NewVersionResolver(cfg VersionResolutionParameters) *VersionResolver {
-	return &VersionResolver{
+	v := &VersionResolver{
 		config:                   cfg,
 		availableVersionsFetcher: availableVersionsFetcher,
-		versionInfoFetcher:       fetchVersionInfo,
+		directVersionsFetcher:    fetchDirectVersions,
 	}
+	v.versionInfoFetcher = v.fetchVersionInfo
+	v.modFileFetcher = v.fetchModFile
+	return v
 }
 
 func (v VersionResolver) ResolveVersion(ctx context.Context, intent binny.VersionIntent) (string, error) {
 	log.FromContext(ctx).WithFields("module", v.config.Module, "version", intent.Want).Trace("resolving version from go proxy")
-	if internal.IsSemver(intent.Want) {
+	filter := intent.Filter()
+	if intent.Want != latest && filter.IsVersion(intent.Want) {
 		return intent.Want, nil
 	}
 
-	if intent.Want == latest && !v.config.AllowUnresolvedVersion {
-		return v.findLatestVersion(ctx, "", intent.Cooldown)
+	if intent.Want == latest {
+		if v.config.AllowUnresolvedVersion {
+			return intent.Want, nil
+		}
+		// note: constraints are not considered when resolving "latest" for go modules
+		filter.Constraint = ""
+		return v.findLatestVersion(ctx, filter, intent.Cooldown)
 	}
 
-	// TODO: dunno
-
-	return intent.Want, nil
+	// the want is a branch or commit, which is resolved to the version of the module at that revision
+	return v.resolveQueryOrKeep(ctx, intent.Want), nil
 }
 
 func (v VersionResolver) UpdateVersion(ctx context.Context, intent binny.VersionIntent) (string, error) {
+	if intent.Ref != "" {
+		// the want is pinned to what a branch or commit resolved to, move it forward to what it resolves to now
+		return v.resolveQuery(ctx, intent.Ref)
+	}
+
 	if intent.Want == latest {
 		if intent.Constraint != "" {
 			return "", fmt.Errorf("cannot specify a version constraint with 'latest' go module version")
@@ -71,31 +91,59 @@ func (v VersionResolver) UpdateVersion(ctx context.Context, intent binny.Version
 		return intent.Want, nil
 	}
 
-	if internal.IsSemver(intent.Want) {
-		return v.findLatestVersion(ctx, intent.Constraint, intent.Cooldown)
+	if filter := intent.Filter(); filter.IsVersion(intent.Want) {
+		return v.findLatestVersion(ctx, filter, intent.Cooldown)
+	}
+
+	return v.resolveQueryOrKeep(ctx, intent.Want), nil
+}
+
+// resolveQuery resolves a version query that is not a version (a branch or commit) to the canonical version of the
+// module at that revision, which is a pseudo-version unless the revision is tagged.
+func (v VersionResolver) resolveQuery(ctx context.Context, query string) (string, error) {
+	info, err := v.versionInfoFetcher(ctx, v.config.Module, query)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve %q to a version of module %q: %w", query, v.config.Module, err)
 	}
+	if info.Version == "" {
+		return "", fmt.Errorf("no version reported for %q of module %q", query, v.config.Module)
+	}
+
+	log.FromContext(ctx).WithFields("module", v.config.Module, "query", query, "version", info.Version).
+		Trace("resolved version query from go proxy")
 
-	// TODO: dunno
+	return info.Version, nil
+}
 
-	return intent.Want, nil
+// resolveQueryOrKeep resolves the query like resolveQuery, but keeps the query as-is when the proxy cannot answer
+// (e.g. when resolving directly from version control), since the go command is able to resolve it at install time.
+func (v VersionResolver) resolveQueryOrKeep(ctx context.Context, query string) string {
+	version, err := v.resolveQuery(ctx, query)
+	if err != nil {
+		log.FromContext(ctx).WithFields("module", v.config.Module, "query", query).
+			Debugf("using version query as-is: %v", err)
+		return query
+	}
+	return version
 }
 
-func (v VersionResolver) findLatestVersion(ctx context.Context, versionConstraint string, cooldown time.Duration) (string, error) {
+func (v VersionResolver) findLatestVersion(ctx context.Context, filter binny.VersionFilter, cooldown time.Duration) (string, error) {
 	lgr := log.FromContext(ctx)
 
 	// ask the go proxy for the latest version
-	url := "https://proxy.golang.org/" + v.config.Module + "/@v/list"
-	versions, err := v.availableVersionsFetcher(ctx, url)
+	versions, err := v.fetchAvailableVersions(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to get available versions from go proxy: %v", err)
 	}
 
+	versions = v.removeRetracted(ctx, versions)
+
 	// when cooldown is active, we need to check publish dates via the /@v/{version}.info endpoint
 	if cooldown > 0 {
-		return v.findLatestVersionWithCooldown(ctx, versions, versionConstraint, cooldown)
+		return v.findLatestVersionWithCooldown(ctx, versions, filter, cooldown)
 	}
 
-	latestVersion, err := internal.FilterToLatestVersion(versions, versionConstraint)
+	latestVersion, err := binny.FilterToLatestVersion(versions, filter)
 	if err != nil {
 		return "", fmt.Errorf("failed to filter latest version: %v", err)
 	}
@@ -119,21 +167,24 @@ func (v VersionResolver) findLatestVersion(ctx context.Context, versionConstrain
 	return latestVersion, nil
 }
 
-// findLatestVersionWithCooldown checks version candidates in descending order, fetching their publish
-// dates from the go proxy info endpoint. It returns the first version that passes the cooldown check.
-// To avoid excessive API calls, it only checks the top candidates (up to maxCooldownCandidates).
-const maxCooldownCandidates = 10
+// maxConcurrentInfoLookups bounds the number of concurrent requests to the go proxy info endpoint.
+const maxConcurrentInfoLookups = 8
 
 type versionCandidate struct {
+	// original is the version as reported by the proxy (used for further proxy queries)
 	original string
-	parsed   *semver.Version
+	// version is the original mapped through the configured tag mapping (this is what is returned to the caller)
+	version string
+	parsed  binny.Version
 }
 
-func (v VersionResolver) findLatestVersionWithCooldown(ctx context.Context, versions []string, versionConstraint string, cooldown time.Duration) (string, error) {
+// findLatestVersionWithCooldown finds the newest version candidate that was published before the cooldown cutoff,
+// fetching publish dates from the go proxy info endpoint.
+func (v VersionResolver) findLatestVersionWithCooldown(ctx context.Context, versions []string, filter binny.VersionFilter, cooldown time.Duration) (string, error) {
 	lgr := log.FromContext(ctx)
 	cutoff := time.Now().Add(-cooldown)
 
-	candidates, err := parseAndSortCandidates(versions, versionConstraint)
+	candidates, err := parseAndSortCandidates(versions, filter)
 	if err != nil {
 		return "", err
 	}
@@ -146,44 +197,36 @@ func (v VersionResolver) findLatestVersionWithCooldown(ctx context.Context, vers
 		return result.foundVersion, nil
 	}
 
-	if result.checkedCount < len(candidates) {
-		lgr.WithFields("checked", result.checkedCount, "total", len(candidates), "module", v.config.Module).
-			Warn("cooldown candidate limit reached, older versions were not checked")
-	}
-
-	return "", result.buildCooldownError(cooldown, len(candidates))
+	return "", result.buildCooldownError(cooldown)
 }
 
-// parseAndSortCandidates parses version strings, filters by constraint, and returns them sorted
-// in descending order (newest first).
-func parseAndSortCandidates(versions []string, versionConstraint string) ([]versionCandidate, error) {
-	var constraint *semver.Constraints
-	if versionConstraint != "" {
-		var err error
-		constraint, err = semver.NewConstraint(versionConstraint)
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse version constraint %q: %v", versionConstraint, err)
-		}
+// parseAndSortCandidates parses version strings, filters by constraint and prerelease policy, and returns them
+// sorted in descending order (newest first).
+func parseAndSortCandidates(versions []string, filter binny.VersionFilter) ([]versionCandidate, error) {
+	constraint, err := filter.Constraints()
+	if err != nil {
+		return nil, err
 	}
 
 	var candidates []versionCandidate
-	for _, vs := range versions {
-		vs = strings.TrimSpace(vs)
-		if vs == "" {
+	for _, original := range versions {
+		original = strings.TrimSpace(original)
+		vs, ok := filter.Tags.Version(original)
+		if !ok || vs == "" {
 			continue
 		}
-		ver, err := semver.NewVersion(vs)
+		ver, err := filter.Parse(vs)
 		if err != nil {
 			continue
 		}
-		if constraint != nil && !constraint.Check(ver) {
+		if !filter.Allows(constraint, ver, false) {
 			continue
 		}
-		candidates = append(candidates, versionCandidate{original: vs, parsed: ver})
+		candidates = append(candidates, versionCandidate{original: original, version: vs, parsed: ver})
 	}
 
 	sort.Slice(candidates, func(i, j int) bool {
-		return candidates[i].parsed.GreaterThan(candidates[j].parsed)
+		return candidates[i].parsed.Compare(candidates[j].parsed) > 0
 	})
 
 	return candidates, nil
@@ -195,65 +238,237 @@ type cooldownCheckResult struct {
 	foundDate      time.Time
 	absoluteLatest string
 	latestDate     *time.Time
-	checkedCount   int
 }
 
-func (r cooldownCheckResult) buildCooldownError(cooldown time.Duration, totalCandidates int) *binny.CooldownError {
-	err := &binny.CooldownError{
+func (r cooldownCheckResult) buildCooldownError(cooldown time.Duration) *binny.CooldownError {
+	return &binny.CooldownError{
 		Cooldown:      cooldown,
 		LatestVersion: r.absoluteLatest,
 		LatestDate:    r.latestDate,
 	}
-	if r.checkedCount < totalCandidates {
-		err.CheckedCount = r.checkedCount
-		err.TotalCount = totalCandidates
-	}
-	return err
 }
 
-// checkCandidatesForCooldown iterates through version candidates and checks their publish dates
-// against the cooldown cutoff time. Returns the first version that passes the cooldown check.
+// checkCandidatesForCooldown finds the newest candidate (candidates are sorted newest first) that was published
+// before the cutoff. Since publish dates mostly follow the version order, the boundary between versions that are too
+// new and versions that pass is searched for first, which takes a handful of lookups regardless of the number of
+// candidates. Publish dates do not strictly follow the version order (e.g. a patch for an older release line), so
+// all candidates newer than the boundary are checked as well, which makes the result exact.
 func (v VersionResolver) checkCandidatesForCooldown(ctx context.Context, candidates []versionCandidate, cutoff time.Time) cooldownCheckResult {
-	lgr := log.FromContext(ctx)
-
 	result := cooldownCheckResult{}
-	if len(candidates) > 0 {
-		result.absoluteLatest = candidates[0].original
+	if len(candidates) == 0 {
+		return result
 	}
+	result.absoluteLatest = candidates[0].version
 
-	limit := min(maxCooldownCandidates, len(candidates))
-	result.checkedCount = limit
+	lookup := newPublishDateLookup(ctx, v, candidates)
 
-	for i := 0; i < limit; i++ {
-		c := candidates[i]
-		info, err := v.versionInfoFetcher(ctx, v.config.Module, c.original)
-		if err != nil {
-			lgr.WithFields("version", c.original, "module", v.config.Module).
-				Tracef("failed to fetch version info for cooldown check: %v", err)
+	boundary := lookup.searchBoundary(cutoff)
+
+	// check every candidate newer than the boundary (or all candidates when the search did not find one that passes)
+	last := min(boundary, len(candidates)-1)
+	lookup.fetch(indexRange(0, last+1)...)
+
+	result.latestDate = lookup.date(0)
+
+	for i := 0; i <= last; i++ {
+		if lookup.passes(i, cutoff) {
+			result.foundVersion = candidates[i].version
+			result.foundDate = *lookup.date(i)
+			return result
+		}
+	}
+
+	return result
+}
+
+// publishDateLookup fetches (and remembers) the publish dates of version candidates from the go proxy, allowing for
+// several lookups to happen concurrently.
+type publishDateLookup struct {
+	ctx        context.Context
+	resolver   VersionResolver
+	candidates []versionCandidate
+
+	lock  sync.Mutex
+	dates map[int]*time.Time // a nil date means the lookup failed
+}
+
+func newPublishDateLookup(ctx context.Context, resolver VersionResolver, candidates []versionCandidate) *publishDateLookup {
+	return &publishDateLookup{
+		ctx:        ctx,
+		resolver:   resolver,
+		candidates: candidates,
+		dates:      make(map[int]*time.Time),
+	}
+}
+
+// searchBoundary returns the index of the first candidate that passes the cooldown, assuming that publish dates follow
+// the version order (the number of candidates is returned when none pass). Each round looks up evenly spaced
+// candidates concurrently and narrows the range down to between the last probe that is too new and the first probe
+// that passes.
+func (l *publishDateLookup) searchBoundary(cutoff time.Time) int {
+	lo, hi := 0, len(l.candidates)
+	for lo < hi {
+		probes := probeIndices(lo, hi, maxConcurrentInfoLookups)
+		l.fetch(probes...)
+
+		next := hi
+		for _, p := range probes {
+			if l.passes(p, cutoff) {
+				next = p
+				break
+			}
+			lo = p + 1
+		}
+		hi = next
+	}
+	return lo
+}
+
+// fetch looks up the publish dates for the given candidates that have not been looked up yet.
+func (l *publishDateLookup) fetch(indices ...int) {
+	g := errgroup.Group{}
+	g.SetLimit(maxConcurrentInfoLookups)
+
+	for _, i := range indices {
+		l.lock.Lock()
+		_, done := l.dates[i]
+		l.lock.Unlock()
+		if done {
 			continue
 		}
 
-		if i == 0 {
-			result.latestDate = &info.Time
+		g.Go(func() error {
+			c := l.candidates[i]
+			var date *time.Time
+			info, err := l.resolver.versionInfoFetcher(l.ctx, l.resolver.config.Module, c.original)
+			if err != nil {
+				log.FromContext(l.ctx).WithFields("version", c.original, "module", l.resolver.config.Module).
+					Tracef("failed to fetch version info for cooldown check: %v", err)
+			} else {
+				date = &info.Time
+			}
+
+			l.lock.Lock()
+			l.dates[i] = date
+			l.lock.Unlock()
+			return nil
+		})
+	}
+
+	// note: lookup failures are recorded as unknown dates rather than returned
+	_ = g.Wait()
+}
+
+func (l *publishDateLookup) date(i int) *time.Time {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.dates[i]
+}
+
+// passes reports whether the candidate is known to have been published before the cutoff. A candidate with an unknown
+// publish date never passes.
+func (l *publishDateLookup) passes(i int, cutoff time.Time) bool {
+	date := l.date(i)
+	return date != nil && !date.After(cutoff)
+}
+
+// probeIndices returns up to n evenly spaced indices within [lo, hi), always including lo.
+func probeIndices(lo, hi, n int) []int {
+	size := hi - lo
+	if size <= n {
+		return indexRange(lo, hi)
+	}
+	probes := make([]int, n)
+	for i := range probes {
+		probes[i] = lo + i*size/n
+	}
+	return probes
+}
+
+func indexRange(lo, hi int) []int {
+	indices := make([]int, 0, hi-lo)
+	for i := lo; i < hi; i++ {
+		indices = append(indices, i)
+	}
+	return indices
+}
+
+// fetchAvailableVersions lists the versions of the module from the first proxy (or version control, when "direct"
+// is selected) that is able to answer.
+func (v VersionResolver) fetchAvailableVersions(ctx context.Context) ([]string, error) {
+	return queryProxies(ctx, v.config, func(spec proxySpec) ([]string, error) {
+		if spec.url == proxyDirect {
+			return v.directVersionsFetcher(ctx, v.config.Module)
 		}
 
-		if info.Time.Before(cutoff) || info.Time.Equal(cutoff) {
-			result.foundVersion = c.original
-			result.foundDate = info.Time
-			return result
+		url, err := proxyURL(spec.url, v.config.Module, "@v/list")
+		if err != nil {
+			return nil, err
+		}
+		return v.availableVersionsFetcher(ctx, url)
+	})
+}
+
+// fetchVersionInfo retrieves the publish timestamp for a specific version from the first proxy that knows about it.
+func (v VersionResolver) fetchVersionInfo(ctx context.Context, module, version string) (*versionInfo, error) {
+	return queryProxies(ctx, v.config, func(spec proxySpec) (*versionInfo, error) {
+		if spec.url == proxyDirect {
+			return nil, fmt.Errorf("publish dates are not available when resolving %q directly from version control", module)
+		}
+
+		escaped, err := modulepkg.EscapeVersion(version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version query %q: %w", version, err)
+		}
+
+		url, err := proxyURL(spec.url, module, "@v/"+escaped+".info")
+		if err != nil {
+			return nil, err
+		}
+		return fetchVersionInfoFromURL(ctx, url)
+	})
+}
+
+// queryProxies runs the given query against each entry of the proxy list in order, honoring the fallback semantics
+// of the separators: after a "," the next entry is only tried when the module or version was not found, after a "|"
+// the next entry is tried on any error.
+func queryProxies[T any](ctx context.Context, cfg VersionResolutionParameters, query func(spec proxySpec) (T, error)) (T, error) {
+	var zero T
+
+	proxies, err := proxyListForModule(cfg.Module, cfg.Proxy)
+	if err != nil {
+		return zero, err
+	}
+
+	var lastErr error
+	for _, spec := range proxies {
+		if spec.url == proxyOff {
+			if lastErr != nil {
+				return zero, fmt.Errorf("module lookup disabled by GOPROXY=off: %w", lastErr)
+			}
+			return zero, fmt.Errorf("module lookup disabled by GOPROXY=off")
 		}
 
-		lgr.WithFields("version", c.original, "published", info.Time, "cutoff", cutoff).
-			Trace("version too new for cooldown, checking older versions")
+		result, err := query(spec)
+		if err == nil {
+			return result, nil
+		}
+
+		if !spec.fallBackOnError && !isNotFound(err) {
+			return zero, err
+		}
+
+		log.FromContext(ctx).WithFields("proxy", spec.url, "module", cfg.Module).
+			Tracef("trying next go proxy: %v", err)
+		lastErr = err
 	}
 
-	return result
+	return zero, lastErr
 }
 
 func availableVersionsFetcher(ctx context.Context, url string) ([]string, error) {
-	// TODO: honor GOPROXY env vars
 	lgr := log.FromContext(ctx)
-	client := internalhttp.ClientFromContext(ctx)
+	client := internalhttp.ResolutionClientFromContext(ctx)
 
 	lgr.WithFields("url", url).Trace("requesting latest version")
 
@@ -269,7 +484,7 @@ func availableVersionsFetcher(ctx context.Context, url string) ([]string, error)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get available versions from go proxy: %s", resp.Status)
+		return nil, &statusError{url: url, statusCode: resp.StatusCode}
 	}
 
 	// get the last entry in a newline delimited list
@@ -282,11 +497,10 @@ func availableVersionsFetcher(ctx context.Context, url string) ([]string, error)
 	return lines, nil
 }
 
-// fetchVersionInfo retrieves the publish timestamp for a specific version from the go proxy.
-func fetchVersionInfo(ctx context.Context, module, version string) (*versionInfo, error) {
-	client := internalhttp.ClientFromContext(ctx)
+// fetchVersionInfoFromURL retrieves the /@v/{version}.info document from a single go proxy.
+func fetchVersionInfoFromURL(ctx context.Context, url string) (*versionInfo, error) {
+	client := internalhttp.ResolutionClientFromContext(ctx)
 
-	url := fmt.Sprintf("https://proxy.golang.org/%s/@v/%s.info", module, version)
 	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -299,7 +513,7 @@ func fetchVersionInfo(ctx context.Context, module, version string) (*versionInfo
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get version info from go proxy: %s", resp.Status)
+		return nil, &statusError{url: url, statusCode: resp.StatusCode}
 	}
 
 	contents, err := io.ReadAll(resp.Body)
@@ -3,6 +3,7 @@ package goproxy
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -18,7 +19,10 @@ func TestVersionResolver_ResolveVersion(t *testing.T) {
 		config                   VersionResolutionParameters
 		version                  string
 		constraint               string
+		skip                     []string
 		availableVersionsFetcher func(ctx context.Context, url string) ([]string, error)
+		modFileFetcher           func(ctx context.Context, module, version string) ([]byte, error)
+		versionInfoFetcher       func(ctx context.Context, module, version string) (*versionInfo, error)
 		want                     string
 		wantErr                  require.ErrorAssertionFunc
 	}{
@@ -33,6 +37,33 @@ func TestVersionResolver_ResolveVersion(t *testing.T) {
 				return []string{"1.0.0", "2.0.0", "1.1.0"}, nil
 			},
 		},
+		{
+			name: "retracted versions are not candidates",
+			config: VersionResolutionParameters{
+				Module: "github.com/anchore/binny",
+			},
+			version: "latest",
+			want:    "v1.0.0",
+			availableVersionsFetcher: func(_ context.Context, url string) ([]string, error) {
+				return []string{"v1.0.0", "v1.1.0", "v1.2.0"}, nil
+			},
+			modFileFetcher: func(_ context.Context, _, version string) ([]byte, error) {
+				require.Equal(t, "v1.2.0", version)
+				return []byte("module github.com/anchore/binny\n\nretract [v1.1.0, v1.2.0] // broken release\n"), nil
+			},
+		},
+		{
+			name: "skipped versions are not candidates",
+			config: VersionResolutionParameters{
+				Module: "github.com/anchore/binny",
+			},
+			version: "latest",
+			skip:    []string{"2.0.0", ">= 1.1.0, < 1.2.0"},
+			want:    "1.0.0",
+			availableVersionsFetcher: func(_ context.Context, url string) ([]string, error) {
+				return []string{"1.0.0", "2.0.0", "1.1.0"}, nil
+			},
+		},
 		{
 			name: "semver input will be honored as is",
 			config: VersionResolutionParameters{
@@ -48,6 +79,43 @@ func TestVersionResolver_ResolveVersion(t *testing.T) {
 			},
 			version: "bogus",
 			want:    "bogus",
+			versionInfoFetcher: func(_ context.Context, _, _ string) (*versionInfo, error) {
+				return nil, &statusError{url: "https://proxy.golang.org/github.com/anchore/binny/@v/bogus.info", statusCode: http.StatusNotFound}
+			},
+		},
+		{
+			name: "branch resolves to a pseudo-version",
+			config: VersionResolutionParameters{
+				Module: "github.com/anchore/binny",
+			},
+			version: "main",
+			want:    "v0.0.0-20240601120000-abcdef123456",
+			versionInfoFetcher: func(_ context.Context, _, version string) (*versionInfo, error) {
+				require.Equal(t, "main", version)
+				return &versionInfo{Version: "v0.0.0-20240601120000-abcdef123456"}, nil
+			},
+		},
+		{
+			name: "commit resolves to the tagged version",
+			config: VersionResolutionParameters{
+				Module: "github.com/anchore/binny",
+			},
+			version: "abcdef123456",
+			want:    "v1.2.3",
+			versionInfoFetcher: func(_ context.Context, _, _ string) (*versionInfo, error) {
+				return &versionInfo{Version: "v1.2.3"}, nil
+			},
+		},
+		{
+			name: "pinned pseudo-version is honored as is",
+			config: VersionResolutionParameters{
+				Module: "github.com/anchore/binny",
+			},
+			version: "v0.0.0-20240601120000-abcdef123456",
+			want:    "v0.0.0-20240601120000-abcdef123456",
+			versionInfoFetcher: func(_ context.Context, _, _ string) (*versionInfo, error) {
+				return nil, fmt.Errorf("should never be called")
+			},
 		},
 		{
 			name: "do not allow for unresolved versions",
@@ -80,8 +148,10 @@ func TestVersionResolver_ResolveVersion(t *testing.T) {
 			}
 			v := NewVersionResolver(tt.config)
 			v.availableVersionsFetcher = tt.availableVersionsFetcher
+			v.modFileFetcher = tt.modFileFetcher
+			v.versionInfoFetcher = tt.versionInfoFetcher
 
-			got, err := v.ResolveVersion(context.Background(), binny.VersionIntent{Want: tt.version, Constraint: tt.constraint})
+			got, err := v.ResolveVersion(context.Background(), binny.VersionIntent{Want: tt.version, Constraint: tt.constraint, Skip: tt.skip})
 			tt.wantErr(t, err)
 			assert.Equal(t, tt.want, got)
 		})
@@ -93,8 +163,10 @@ func TestVersionResolver_UpdateVersion(t *testing.T) {
 		name                     string
 		config                   VersionResolutionParameters
 		version                  string
+		ref                      string
 		constraint               string
 		availableVersionsFetcher func(ctx context.Context, url string) ([]string, error)
+		versionInfoFetcher       func(ctx context.Context, module, version string) (*versionInfo, error)
 		want                     string
 		wantErr                  require.ErrorAssertionFunc
 	}{
@@ -124,6 +196,45 @@ func TestVersionResolver_UpdateVersion(t *testing.T) {
 			},
 			version: "bogus",
 			want:    "bogus",
+			versionInfoFetcher: func(_ context.Context, _, _ string) (*versionInfo, error) {
+				return nil, &statusError{url: "https://proxy.golang.org/github.com/anchore/binny/@v/bogus.info", statusCode: http.StatusNotFound}
+			},
+		},
+		{
+			name: "branch is pinned to a pseudo-version",
+			config: VersionResolutionParameters{
+				Module: "github.com/anchore/binny",
+			},
+			version: "main",
+			want:    "v0.0.0-20240601120000-abcdef123456",
+			versionInfoFetcher: func(_ context.Context, _, _ string) (*versionInfo, error) {
+				return &versionInfo{Version: "v0.0.0-20240601120000-abcdef123456"}, nil
+			},
+		},
+		{
+			name: "pinned pseudo-version moves forward with the branch",
+			config: VersionResolutionParameters{
+				Module: "github.com/anchore/binny",
+			},
+			version: "v0.0.0-20240601120000-abcdef123456",
+			ref:     "main",
+			want:    "v0.0.0-20240701120000-123456abcdef",
+			versionInfoFetcher: func(_ context.Context, _, version string) (*versionInfo, error) {
+				require.Equal(t, "main", version)
+				return &versionInfo{Version: "v0.0.0-20240701120000-123456abcdef"}, nil
+			},
+		},
+		{
+			name: "failing to resolve the branch of a pinned pseudo-version is an error",
+			config: VersionResolutionParameters{
+				Module: "github.com/anchore/binny",
+			},
+			version: "v0.0.0-20240601120000-abcdef123456",
+			ref:     "deleted-branch",
+			versionInfoFetcher: func(_ context.Context, _, _ string) (*versionInfo, error) {
+				return nil, &statusError{url: "https://proxy.golang.org/github.com/anchore/binny/@v/deleted-branch.info", statusCode: http.StatusNotFound}
+			},
+			wantErr: require.Error,
 		},
 	}
 	for _, tt := range tests {
@@ -133,8 +244,9 @@ func TestVersionResolver_UpdateVersion(t *testing.T) {
 			}
 			v := NewVersionResolver(tt.config)
 			v.availableVersionsFetcher = tt.availableVersionsFetcher
+			v.versionInfoFetcher = tt.versionInfoFetcher
 
-			got, err := v.UpdateVersion(context.Background(), binny.VersionIntent{Want: tt.version, Constraint: tt.constraint})
+			got, err := v.UpdateVersion(context.Background(), binny.VersionIntent{Want: tt.version, Ref: tt.ref, Constraint: tt.constraint})
 			tt.wantErr(t, err)
 			assert.Equal(t, tt.want, got)
 		})
@@ -150,6 +262,8 @@ func TestVersionResolver_ResolveVersion_withCooldown(t *testing.T) {
 		name                     string
 		cooldown                 time.Duration
 		version                  string
+		tagPattern               string
+		tagTemplate              string
 		availableVersionsFetcher func(ctx context.Context, url string) ([]string, error)
 		versionInfoFetcher       func(ctx context.Context, module, version string) (*versionInfo, error)
 		want                     string
@@ -173,6 +287,26 @@ func TestVersionResolver_ResolveVersion_withCooldown(t *testing.T) {
 				return nil, fmt.Errorf("unexpected version: %s", version)
 			},
 		},
+		{
+			name:        "tag pattern queries the original version and returns the mapped version",
+			cooldown:    7 * 24 * time.Hour,
+			version:     "latest",
+			tagPattern:  `^v(\d+\.\d+\.\d+)$`,
+			tagTemplate: "v{{ .Version }}",
+			want:        "1.0.0",
+			availableVersionsFetcher: func(_ context.Context, _ string) ([]string, error) {
+				return []string{"v1.0.0", "v2.0.0"}, nil
+			},
+			versionInfoFetcher: func(_ context.Context, _, version string) (*versionInfo, error) {
+				switch version {
+				case "v2.0.0":
+					return &versionInfo{Version: "v2.0.0", Time: newDate}, nil
+				case "v1.0.0":
+					return &versionInfo{Version: "v1.0.0", Time: oldDate}, nil
+				}
+				return nil, fmt.Errorf("unexpected version: %s", version)
+			},
+		},
 		{
 			name:     "cooldown error when all versions are too new",
 			cooldown: 7 * 24 * time.Hour,
@@ -224,28 +358,69 @@ func TestVersionResolver_ResolveVersion_withCooldown(t *testing.T) {
 			},
 		},
 		{
-			name:     "candidate limit reached surfaces info in error",
+			name:     "all candidates too new reports the newest",
 			cooldown: 7 * 24 * time.Hour,
 			version:  "latest",
 			availableVersionsFetcher: func(_ context.Context, _ string) ([]string, error) {
-				// generate more candidates than maxCooldownCandidates
 				var versions []string
-				for i := range maxCooldownCandidates + 5 {
+				for i := range 50 {
 					versions = append(versions, fmt.Sprintf("1.0.%d", i))
 				}
 				return versions, nil
 			},
-			versionInfoFetcher: func(_ context.Context, _, _ string) (*versionInfo, error) {
-				// all checked versions are too new
-				return &versionInfo{Version: "1.0.0", Time: newDate}, nil
+			versionInfoFetcher: func(_ context.Context, _, version string) (*versionInfo, error) {
+				return &versionInfo{Version: version, Time: newDate}, nil
 			},
 			wantErr: func(t require.TestingT, err error, _ ...any) {
 				require.Error(t, err)
 				var cooldownErr *binny.CooldownError
 				require.ErrorAs(t, err, &cooldownErr)
-				assert.Equal(t, maxCooldownCandidates, cooldownErr.CheckedCount)
-				assert.Equal(t, maxCooldownCandidates+5, cooldownErr.TotalCount)
-				assert.Contains(t, cooldownErr.Error(), "only checked")
+				assert.Equal(t, "1.0.49", cooldownErr.LatestVersion)
+				require.NotNil(t, cooldownErr.LatestDate)
+				assert.Equal(t, newDate, *cooldownErr.LatestDate)
+			},
+		},
+		{
+			name:     "many candidates finds the exact boundary",
+			cooldown: 7 * 24 * time.Hour,
+			version:  "latest",
+			want:     "1.0.899",
+			availableVersionsFetcher: func(_ context.Context, _ string) ([]string, error) {
+				var versions []string
+				for i := range 1000 {
+					versions = append(versions, fmt.Sprintf("1.0.%d", i))
+				}
+				return versions, nil
+			},
+			versionInfoFetcher: func(_ context.Context, _, version string) (*versionInfo, error) {
+				var patch int
+				_, err := fmt.Sscanf(version, "1.0.%d", &patch)
+				require.NoError(t, err)
+				if patch >= 900 {
+					return &versionInfo{Version: version, Time: newDate}, nil
+				}
+				return &versionInfo{Version: version, Time: oldDate}, nil
+			},
+		},
+		{
+			name:     "newer version published before the boundary is found",
+			cooldown: 7 * 24 * time.Hour,
+			version:  "latest",
+			want:     "2.0.1",
+			availableVersionsFetcher: func(_ context.Context, _ string) ([]string, error) {
+				var versions []string
+				for i := range 100 {
+					versions = append(versions, fmt.Sprintf("1.0.%d", i))
+				}
+				// a release line whose latest patch was published before an older one
+				return append(versions, "2.0.0", "2.0.1", "2.0.2"), nil
+			},
+			versionInfoFetcher: func(_ context.Context, _, version string) (*versionInfo, error) {
+				switch version {
+				case "2.0.0", "2.0.2":
+					return &versionInfo{Version: version, Time: newDate}, nil
+				}
+				return &versionInfo{Version: version, Time: oldDate}, nil
 			},
 		},
 	}
@@ -260,8 +435,12 @@ func TestVersionResolver_ResolveVersion_withCooldown(t *testing.T) {
 			})
 			v.availableVersionsFetcher = tt.availableVersionsFetcher
 			v.versionInfoFetcher = tt.versionInfoFetcher
+			v.modFileFetcher = noRetractions
 
-			got, err := v.ResolveVersion(context.Background(), binny.VersionIntent{Want: tt.version, Cooldown: tt.cooldown})
+			tags, err := binny.NewTagMapping(tt.tagPattern, tt.tagTemplate)
+			require.NoError(t, err)
+
+			got, err := v.ResolveVersion(context.Background(), binny.VersionIntent{Want: tt.version, Cooldown: tt.cooldown, Tags: tags})
 			tt.wantErr(t, err)
 			if err == nil {
 				assert.Equal(t, tt.want, got)
@@ -359,6 +538,7 @@ func TestVersionResolver_UpdateVersion_withCooldown(t *testing.T) {
 			})
 			v.availableVersionsFetcher = tt.availableVersionsFetcher
 			v.versionInfoFetcher = tt.versionInfoFetcher
+			v.modFileFetcher = noRetractions
 
 			got, err := v.UpdateVersion(context.Background(), binny.VersionIntent{Want: tt.version, Cooldown: tt.cooldown, Constraint: tt.constraint})
 			tt.wantErr(t, err)
@@ -368,3 +548,91 @@ func TestVersionResolver_UpdateVersion_withCooldown(t *testing.T) {
 		})
 	}
 }
+
+func TestVersionResolver_ResolveVersion_proxyFallback(t *testing.T) {
+	tests := []struct {
+		name           string
+		proxy          string
+		proxyResponses map[string]error
+		wantURLs       []string
+		wantDirect     bool
+		want           string
+		wantErr        require.ErrorAssertionFunc
+	}{
+		{
+			name:     "first proxy answers",
+			proxy:    "https://athens.example.com,https://proxy.golang.org",
+			wantURLs: []string{"https://athens.example.com/github.com/anchore/binny/@v/list"},
+			want:     "2.0.0",
+		},
+		{
+			name:  "comma falls back on not found",
+			proxy: "https://athens.example.com,https://proxy.golang.org",
+			proxyResponses: map[string]error{
+				"https://athens.example.com/github.com/anchore/binny/@v/list": &statusError{statusCode: http.StatusNotFound},
+			},
+			wantURLs: []string{
+				"https://athens.example.com/github.com/anchore/binny/@v/list",
+				"https://proxy.golang.org/github.com/anchore/binny/@v/list",
+			},
+			want: "2.0.0",
+		},
+		{
+			name:  "comma does not fall back on other errors",
+			proxy: "https://athens.example.com,https://proxy.golang.org",
+			proxyResponses: map[string]error{
+				"https://athens.example.com/github.com/anchore/binny/@v/list": &statusError{statusCode: http.StatusInternalServerError},
+			},
+			wantURLs: []string{"https://athens.example.com/github.com/anchore/binny/@v/list"},
+			wantErr:  require.Error,
+		},
+		{
+			name:  "pipe falls back on any error",
+			proxy: "https://athens.example.com|direct",
+			proxyResponses: map[string]error{
+				"https://athens.example.com/github.com/anchore/binny/@v/list": fmt.Errorf("connection refused"),
+			},
+			wantURLs:   []string{"https://athens.example.com/github.com/anchore/binny/@v/list"},
+			wantDirect: true,
+			want:       "3.0.0",
+		},
+		{
+			name:    "off disables lookups",
+			proxy:   "off",
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			var gotURLs []string
+			var gotDirect bool
+
+			v := NewVersionResolver(VersionResolutionParameters{
+				Module: "github.com/anchore/binny",
+				Proxy:  tt.proxy,
+			})
+			v.availableVersionsFetcher = func(_ context.Context, url string) ([]string, error) {
+				gotURLs = append(gotURLs, url)
+				if err := tt.proxyResponses[url]; err != nil {
+					return nil, err
+				}
+				return []string{"1.0.0", "2.0.0"}, nil
+			}
+			v.modFileFetcher = noRetractions
+			v.directVersionsFetcher = func(_ context.Context, _ string) ([]string, error) {
+				gotDirect = true
+				return []string{"1.0.0", "3.0.0"}, nil
+			}
+
+			got, err := v.ResolveVersion(context.Background(), binny.VersionIntent{Want: "latest"})
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantURLs, gotURLs)
+			assert.Equal(t, tt.wantDirect, gotDirect)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"runtime"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/google/shlex"
@@ -24,11 +25,20 @@ var _ binny.Installer = (*Installer)(nil)
 type InstallerParameters struct {
 	URL  string `json:"url" yaml:"url" mapstructure:"url"`
 	Args string `json:"args" yaml:"args" mapstructure:"args"`
+	// ScriptChecksum pins the digest of the install script (optionally prefixed with the hash algorithm, e.g.
+	// "sha256:..."). This is refreshed by "binny update".
+	ScriptChecksum string `json:"script-checksum" yaml:"script-checksum,omitempty" mapstructure:"script-checksum"`
+	// Binary is the path (relative to the destination) of the binary to install, for scripts that install several
+	// files. When not set the script must install exactly one file.
+	Binary string `json:"binary" yaml:"binary,omitempty" mapstructure:"binary"`
+	// Checksums pins the digest of the installed binary for each platform (e.g. "linux/amd64"). These are refreshed by
+	// "binny update", however only for the platform binny is running on.
+	Checksums binny.PinnedChecksums `json:"checksums" yaml:"checksums,omitempty" mapstructure:"checksums"`
 }
 
 type Installer struct {
 	config       InstallerParameters
-	scriptRunner func(scriptPath string, argStr string) error
+	scriptRunner func(ctx context.Context, scriptPath string, argStr string) error
 }
 
 func NewInstaller(cfg InstallerParameters) Installer {
@@ -43,10 +53,105 @@ func (i Installer) InstallTo(ctx context.Context, version, destDir string) (stri
 
 	lgr.Debug("installing from hosted shell script")
 
+	if err := i.config.Checksums.Validate(); err != nil {
+		return "", fmt.Errorf("invalid checksums for %s: %w", i.config.URL, err)
+	}
+
+	record := internal.ChecksumRecordFromContext(ctx)
+	pinned, ok := i.config.Checksums.ForPlatform(runtime.GOOS, runtime.GOARCH)
+	if !ok && i.config.ScriptChecksum == "" {
+		// there is no checksum for the script, so fail before downloading (let alone running) it when one is required
+		if err := record.Missing(fmt.Sprintf("install script %q", i.config.URL)); err != nil {
+			return "", err
+		}
+	}
+
+	binPath, err := i.runInstall(ctx, version, destDir)
+	if err != nil {
+		return "", err
+	}
+	record.SetSourceURL(i.config.URL)
+
+	if i.config.ScriptChecksum != "" {
+		record.Verified(fmt.Sprintf("script checksum for %q", i.config.URL))
+	}
+	if ok {
+		if err := internal.VerifyFile(binPath, pinned); err != nil {
+			return "", err
+		}
+		record.Verified("pinned checksum")
+	}
+
+	return binPath, nil
+}
+
+// PinChecksums installs the tool and returns the digest of the binary. Since the binary comes from running the script,
+// only the pin for the platform binny is running on can be refreshed. The pins for all other platforms are cleared
+// (set to "") so that they are refreshed the next time the tool is updated on that platform.
+func (i Installer) PinChecksums(ctx context.Context, version string, pinned binny.PinnedChecksums) (binny.PinnedChecksums, error) {
+	if err := pinned.Validate(); err != nil {
+		return nil, err
+	}
+
+	host := binny.Platform(runtime.GOOS, runtime.GOARCH)
+	refreshed := make(binny.PinnedChecksums, len(pinned))
+	for _, platform := range pinned.Platforms() {
+		if platform != host {
+			log.WithFields("url", i.config.URL, "platform", platform).Warn("unable to refresh pinned checksum for a platform other than the host, clearing it (run 'binny update' on that platform to pin it again)")
+			refreshed[platform] = ""
+		}
+	}
+
+	if _, ok := pinned[host]; !ok {
+		return refreshed, nil
+	}
+
+	tempDir, err := os.MkdirTemp("", "binny-hosted-shell-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	binPath, err := i.runInstall(ctx, version, tempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	fh, err := os.Open(binPath)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	digest, err := internal.Digest(fh, pinned[host])
+	if err != nil {
+		return nil, fmt.Errorf("unable to hash %q: %w", binPath, err)
+	}
+	refreshed[host] = digest
+	return refreshed, nil
+}
+
+// PinScriptChecksum downloads the install script and returns its digest.
+func (i Installer) PinScriptChecksum(ctx context.Context, pinned string) (string, error) {
+	reader, err := internal.DownloadURL(ctx, i.config.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download script: %v", err)
+	}
+	defer reader.Close()
+
+	digest, err := internal.Digest(reader, pinned)
+	if err != nil {
+		return "", fmt.Errorf("unable to hash script %q: %w", i.config.URL, err)
+	}
+	return digest, nil
+}
+
+// runInstall downloads and runs the install script, returning the path to the binary that it installed.
+func (i Installer) runInstall(ctx context.Context, version, destDir string) (string, error) {
 	const scriptName = "install.sh"
 
 	scriptPath := filepath.Join(destDir, scriptName)
-	if err := internal.DownloadFile(ctx, i.config.URL, scriptPath, ""); err != nil {
+	if err := internal.DownloadFile(ctx, i.config.URL, scriptPath, i.config.ScriptChecksum); err != nil {
 		return "", fmt.Errorf("failed to download script: %v", err)
 	}
 
@@ -55,7 +160,7 @@ func (i Installer) InstallTo(ctx context.Context, version, destDir string) (stri
 		return "", fmt.Errorf("failed to template args: %v", err)
 	}
 
-	if err = i.scriptRunner(scriptPath, argStr); err != nil {
+	if err = i.scriptRunner(ctx, scriptPath, argStr); err != nil {
 		return "", fmt.Errorf("failed to run script: %v", err)
 	}
 
@@ -72,16 +177,37 @@ func (i Installer) InstallTo(ctx context.Context, version, destDir string) (stri
 		}
 	}
 
-	var binPath string
+	if i.config.Binary != "" {
+		return selectBinary(destDir, i.config.Binary, files)
+	}
+
 	switch len(files) {
 	case 0:
 		return "", fmt.Errorf("no files found in destination directory")
 	case 1:
-		binPath = filepath.Join(destDir, files[0])
+		return filepath.Join(destDir, files[0]), nil
 	default:
-		return "", fmt.Errorf("multiple files found in destination directory: %s", strings.Join(files, ", "))
+		return "", fmt.Errorf("multiple files found in destination directory (use the binary option to select one): %s", strings.Join(files, ", "))
+	}
+}
+
+// selectBinary returns the path to the configured binary, which must be a file within the destination directory.
+func selectBinary(destDir, binary string, files []string) (string, error) {
+	if !filepath.IsLocal(binary) {
+		return "", fmt.Errorf("binary %q must be a relative path within the destination directory", binary)
 	}
 
+	binPath := filepath.Join(destDir, binary)
+	info, err := os.Lstat(binPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("binary %q not found in destination directory (found: %s)", binary, strings.Join(files, ", "))
+		}
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("binary %q is not a regular file", binary)
+	}
 	return binPath, nil
 }
 
@@ -114,7 +240,21 @@ func templateFlags(args string, version, destination string) (string, error) {
 	return result, nil
 }
 
-func runScript(scriptPath, argStr string) error {
+// scriptEnvPassthrough are the only variables from the environment that install scripts are run with, so that
+// credentials and other secrets are not exposed to the script.
+var scriptEnvPassthrough = []string{
+	"PATH",
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY",
+	"http_proxy", "https_proxy", "no_proxy",
+	"SSL_CERT_FILE", "SSL_CERT_DIR",
+}
+
+const (
+	defaultScriptPath = "/usr/local/bin:/usr/bin:/bin"
+	scriptWaitDelay   = 5 * time.Second
+)
+
+func runScript(ctx context.Context, scriptPath, argStr string) error {
 	if runtime.GOOS == "windows" {
 		return fmt.Errorf("script based installers are not supported on %s", runtime.GOOS)
 	}
@@ -127,9 +267,21 @@ func runScript(scriptPath, argStr string) error {
 	args := []string{scriptPath}
 	args = append(args, userArgs...)
 
+	// the script gets a throwaway home (and temp) directory, so that it cannot read or leave behind user config
+	home, err := os.MkdirTemp("", "binny-hosted-shell-home-")
+	if err != nil {
+		return fmt.Errorf("failed to create home directory for script: %v", err)
+	}
+	defer os.RemoveAll(home)
+
 	log.Trace("running: <script> " + strings.Join(args, " "))
 
-	cmd := exec.Command("sh", args...)
+	cmd := exec.CommandContext(ctx, "sh", args...)
+	cmd.Env = scriptEnv(home)
+	cmd.Dir = home
+	killProcessGroupOnCancel(cmd)
+	// don't wait on output from anything that outlives the script after it has been killed
+	cmd.WaitDelay = scriptWaitDelay
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -137,3 +289,17 @@ func runScript(scriptPath, argStr string) error {
 	}
 	return nil
 }
+
+// scriptEnv returns the minimal environment that install scripts are run with.
+func scriptEnv(home string) []string {
+	env := []string{"HOME=" + home, "TMPDIR=" + home}
+	for _, name := range scriptEnvPassthrough {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	if _, ok := os.LookupEnv("PATH"); !ok {
+		env = append(env, "PATH="+defaultScriptPath)
+	}
+	return env
+}
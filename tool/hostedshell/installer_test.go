@@ -2,16 +2,23 @@ package hostedshell
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/sha512"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/binny"
+	"github.com/anchore/binny/internal"
 )
 
 func TestInstaller_InstallTo(t *testing.T) {
@@ -21,7 +28,7 @@ func TestInstaller_InstallTo(t *testing.T) {
 
 	type fields struct {
 		config       InstallerParameters
-		scriptRunner func(scriptPath string, argStr string) error
+		scriptRunner func(ctx context.Context, scriptPath string, argStr string) error
 	}
 	type args struct {
 		version string
@@ -50,13 +57,13 @@ func TestInstaller_InstallTo(t *testing.T) {
 					}(),
 					Args: "{{ .Destination }} {{ .Version }} ",
 				},
-				scriptRunner: func(scriptPath string, argStr string) error {
+				scriptRunner: func(ctx context.Context, scriptPath string, argStr string) error {
 					contents, err := os.ReadFile(scriptPath)
 					require.NoError(t, err)
 					require.Equal(t, "set -eu; echo 'hello world'; touch $1/syft", string(contents))
 					require.NotEmpty(t, argStr)
 					require.Contains(t, argStr, "1.2.3")
-					require.NoError(t, runScript(scriptPath, argStr))
+					require.NoError(t, runScript(ctx, scriptPath, argStr))
 					return nil
 				},
 			},
@@ -80,3 +87,283 @@ func TestInstaller_InstallTo(t *testing.T) {
 		})
 	}
 }
+
+func TestInstaller_InstallTo_requireChecksum(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("the script must not be downloaded")
+	}))
+	t.Cleanup(s.Close)
+
+	i := NewInstaller(InstallerParameters{URL: s.URL, Args: "{{ .Destination }} {{ .Version }}"})
+	i.scriptRunner = func(_ context.Context, _ string, _ string) error {
+		t.Error("the script must not be run")
+		return nil
+	}
+
+	ctx, _ := internal.WithChecksumRecord(context.Background(), "tool", true)
+	_, err := i.InstallTo(ctx, "1.2.3", t.TempDir())
+
+	var required *internal.ErrChecksumRequired
+	require.ErrorAs(t, err, &required)
+	assert.Contains(t, err.Error(), s.URL)
+}
+
+func TestInstaller_InstallTo_pinnedChecksum(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("script based installer is not supported on windows")
+	}
+
+	const script = "set -eu; printf 'binary contents' > $1/syft"
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("binary contents")))
+	platform := binny.Platform(runtime.GOOS, runtime.GOARCH)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(script))
+	}))
+	t.Cleanup(s.Close)
+
+	tests := []struct {
+		name       string
+		pinned     binny.PinnedChecksums
+		wantSource string
+		wantErr    require.ErrorAssertionFunc
+	}{
+		{
+			name:       "matches the pinned checksum",
+			pinned:     binny.PinnedChecksums{platform: digest},
+			wantSource: "pinned checksum",
+		},
+		{
+			name:    "does not match the pinned checksum",
+			pinned:  binny.PinnedChecksums{platform: fmt.Sprintf("%x", sha256.Sum256([]byte("other")))},
+			wantErr: require.Error,
+		},
+		{
+			name:    "no pinned checksum for the platform",
+			pinned:  binny.PinnedChecksums{"plan9/mips": digest},
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			i := NewInstaller(InstallerParameters{URL: s.URL, Args: "{{ .Destination }} {{ .Version }}", Checksums: tt.pinned})
+
+			ctx, record := internal.WithChecksumRecord(context.Background(), "syft", true)
+			_, err := i.InstallTo(ctx, "1.2.3", t.TempDir())
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.wantSource, record.Source())
+		})
+	}
+}
+
+func TestInstaller_PinChecksums(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("script based installer is not supported on windows")
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("set -eu; printf \"binary $2\" > $1/syft"))
+	}))
+	t.Cleanup(s.Close)
+
+	host := binny.Platform(runtime.GOOS, runtime.GOARCH)
+	i := NewInstaller(InstallerParameters{URL: s.URL, Args: "{{ .Destination }} {{ .Version }}"})
+
+	got, err := i.PinChecksums(context.Background(), "1.2.3", binny.PinnedChecksums{host: "sha256:stale", "plan9/mips": "stale"})
+	require.NoError(t, err)
+	// only the host platform can be refreshed, the rest are cleared so they are pinned again on that platform
+	assert.Equal(t, binny.PinnedChecksums{
+		host:         fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("binary 1.2.3"))),
+		"plan9/mips": "",
+	}, got)
+
+	got, err = i.PinChecksums(context.Background(), "1.2.3", binny.PinnedChecksums{"plan9/mips": "stale"})
+	require.NoError(t, err)
+	assert.Equal(t, binny.PinnedChecksums{"plan9/mips": ""}, got)
+}
+
+func TestInstaller_InstallTo_scriptChecksum(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("script based installer is not supported on windows")
+	}
+
+	const script = "set -eu; touch $1/syft"
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(script))
+	}))
+	t.Cleanup(s.Close)
+
+	tests := []struct {
+		name           string
+		scriptChecksum string
+		wantSource     string
+		wantErr        require.ErrorAssertionFunc
+	}{
+		{
+			name:           "matches the script checksum",
+			scriptChecksum: fmt.Sprintf("%x", sha256.Sum256([]byte(script))),
+			wantSource:     fmt.Sprintf("script checksum for %q", s.URL),
+		},
+		{
+			name:           "matches a prefixed script checksum",
+			scriptChecksum: fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(script))),
+			wantSource:     fmt.Sprintf("script checksum for %q", s.URL),
+		},
+		{
+			name:           "script does not match",
+			scriptChecksum: fmt.Sprintf("%x", sha256.Sum256([]byte("other"))),
+			wantErr:        require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			i := NewInstaller(InstallerParameters{URL: s.URL, Args: "{{ .Destination }} {{ .Version }}", ScriptChecksum: tt.scriptChecksum})
+			ran := false
+			runner := i.scriptRunner
+			i.scriptRunner = func(ctx context.Context, scriptPath string, argStr string) error {
+				ran = true
+				return runner(ctx, scriptPath, argStr)
+			}
+
+			ctx, record := internal.WithChecksumRecord(context.Background(), "syft", true)
+			_, err := i.InstallTo(ctx, "1.2.3", t.TempDir())
+			tt.wantErr(t, err)
+			if err != nil {
+				assert.False(t, ran, "a script that does not match must not be run")
+				return
+			}
+			assert.Equal(t, tt.wantSource, record.Source())
+		})
+	}
+}
+
+func TestInstaller_InstallTo_binary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("script based installer is not supported on windows")
+	}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("set -eu; mkdir -p $1/bin; touch $1/bin/syft $1/LICENSE $1/README.md"))
+	}))
+	t.Cleanup(s.Close)
+
+	tests := []struct {
+		name    string
+		binary  string
+		want    string
+		wantErr require.ErrorAssertionFunc
+	}{
+		{
+			name:   "select the binary",
+			binary: "bin/syft",
+			want:   filepath.Join("bin", "syft"),
+		},
+		{
+			name:    "several files without a binary",
+			wantErr: require.Error,
+		},
+		{
+			name:    "binary not installed",
+			binary:  "grype",
+			wantErr: require.Error,
+		},
+		{
+			name:    "binary is a directory",
+			binary:  "bin",
+			wantErr: require.Error,
+		},
+		{
+			name:    "binary outside of the destination",
+			binary:  "../syft",
+			wantErr: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			destDir := t.TempDir()
+			i := NewInstaller(InstallerParameters{URL: s.URL, Args: "{{ .Destination }} {{ .Version }}", Binary: tt.binary})
+			got, err := i.InstallTo(context.Background(), "1.2.3", destDir)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, filepath.Join(destDir, tt.want), got)
+		})
+	}
+}
+
+func Test_runScript_environment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("script based installer is not supported on windows")
+	}
+
+	t.Setenv("BINNY_TEST_SECRET", "secret")
+	t.Setenv("HTTPS_PROXY", "http://proxy.example.com")
+
+	dir := t.TempDir()
+	scriptPath := filepath.Join(dir, "install.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte(`set -eu; env > "$1/env"; pwd > "$1/pwd"`), 0o600))
+
+	require.NoError(t, runScript(context.Background(), scriptPath, dir))
+
+	env, err := os.ReadFile(filepath.Join(dir, "env"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(env), "BINNY_TEST_SECRET")
+	assert.Contains(t, string(env), "HTTPS_PROXY=http://proxy.example.com")
+	assert.NotContains(t, string(env), "HOME="+os.Getenv("HOME")+"\n")
+
+	pwd, err := os.ReadFile(filepath.Join(dir, "pwd"))
+	require.NoError(t, err)
+	assert.Contains(t, string(env), "HOME="+string(pwd))
+	// the home directory is removed after the script runs
+	assert.NoDirExists(t, strings.TrimSpace(string(pwd)))
+}
+
+func Test_runScript_cancelled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("script based installer is not supported on windows")
+	}
+
+	scriptPath := filepath.Join(t.TempDir(), "install.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("sleep 30"), 0o600))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	require.Error(t, runScript(ctx, scriptPath, ""))
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestInstaller_PinScriptChecksum(t *testing.T) {
+	const script = "set -eu; touch $1/syft"
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(script))
+	}))
+	t.Cleanup(s.Close)
+
+	i := NewInstaller(InstallerParameters{URL: s.URL})
+
+	got, err := i.PinScriptChecksum(context.Background(), "sha256:stale")
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(script))), got)
+
+	got, err = i.PinScriptChecksum(context.Background(), "sha512:stale")
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("sha512:%x", sha512.Sum512([]byte(script))), got)
+}
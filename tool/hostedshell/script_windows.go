@@ -0,0 +1,6 @@
+package hostedshell
+
+import "os/exec"
+
+// killProcessGroupOnCancel is a no-op, since script based installers are not supported on windows.
+func killProcessGroupOnCancel(_ *exec.Cmd) {}
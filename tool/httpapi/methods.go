@@ -0,0 +1,30 @@
+package httpapi
+
+import "strings"
+
+const (
+	// JSONResolveMethod resolves versions from a JSON document.
+	JSONResolveMethod = "http-json"
+	// TextResolveMethod resolves versions from a plain text document (e.g. a "stable.txt" file).
+	TextResolveMethod = "http-text"
+)
+
+func IsResolveMethod(method string) bool {
+	return IsJSONResolveMethod(method) || IsTextResolveMethod(method)
+}
+
+func IsJSONResolveMethod(method string) bool {
+	switch strings.ToLower(method) {
+	case JSONResolveMethod, "httpjson", "http json":
+		return true
+	}
+	return false
+}
+
+func IsTextResolveMethod(method string) bool {
+	switch strings.ToLower(method) {
+	case TextResolveMethod, "httptext", "http text":
+		return true
+	}
+	return false
+}
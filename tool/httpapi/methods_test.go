@@ -0,0 +1,42 @@
+package httpapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethods(t *testing.T) {
+	tests := []struct {
+		name     string
+		methods  []string
+		wantJSON bool
+		wantText bool
+	}{
+		{
+			name:     "json",
+			methods:  []string{"http-json", "httpjson", "http json"},
+			wantJSON: true,
+		},
+		{
+			name:     "text",
+			methods:  []string{"http-text", "httptext", "http text"},
+			wantText: true,
+		},
+		{
+			name:    "invalid",
+			methods: []string{"made up", "http"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, method := range tt.methods {
+				t.Run(method, func(t *testing.T) {
+					assert.Equal(t, tt.wantJSON || tt.wantText, IsResolveMethod(method))
+					assert.Equal(t, tt.wantJSON, IsJSONResolveMethod(method))
+					assert.Equal(t, tt.wantText, IsTextResolveMethod(method))
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/itchyny/gojq"
+
+	"github.com/anchore/binny"
+	internalhttp "github.com/anchore/binny/internal/http"
+	"github.com/anchore/binny/internal/log"
+)
+
+const latest = "latest"
+
+var _ binny.VersionResolver = (*VersionResolver)(nil)
+
+type VersionResolver struct {
+	config          VersionResolutionParameters
+	text            bool
+	documentFetcher func(ctx context.Context, url string) ([]byte, error)
+}
+
+type VersionResolutionParameters struct {
+	// URL is the document to resolve versions from.
+	URL string `json:"url" yaml:"url" mapstructure:"url"`
+	// Query is a jq expression that is evaluated against the document (for http-text the document is a single string).
+	// Each result is either a version, an object with a "version" and an optional "date" field (RFC 3339 or
+	// YYYY-MM-DD), or an array of either.
+	Query string `json:"query" yaml:"query,omitempty" mapstructure:"query"`
+}
+
+// release is a version found in the document, along with when it was published (if known).
+type release struct {
+	version string
+	date    *time.Time
+}
+
+// NewVersionResolver creates a resolver for the given method, which determines how the document is interpreted.
+func NewVersionResolver(method string, cfg VersionResolutionParameters) *VersionResolver {
+	return &VersionResolver{
+		config:          cfg,
+		text:            IsTextResolveMethod(method),
+		documentFetcher: fetchDocument,
+	}
+}
+
+func (v VersionResolver) ResolveVersion(ctx context.Context, intent binny.VersionIntent) (string, error) {
+	log.FromContext(ctx).WithFields("url", v.config.URL, "version", intent.Want).Trace("resolving version from http document")
+
+	if intent.Want == latest {
+		return v.findLatestVersion(ctx, intent.Filter(), intent.Cooldown)
+	}
+
+	return intent.Want, nil
+}
+
+func (v VersionResolver) UpdateVersion(ctx context.Context, intent binny.VersionIntent) (string, error) {
+	if intent.Want == latest {
+		return intent.Want, nil
+	}
+
+	if filter := intent.Filter(); filter.IsVersion(intent.Want) {
+		return v.findLatestVersion(ctx, filter, intent.Cooldown)
+	}
+
+	return intent.Want, nil
+}
+
+func (v VersionResolver) findLatestVersion(ctx context.Context, filter binny.VersionFilter, cooldown time.Duration) (string, error) {
+	lgr := log.FromContext(ctx)
+
+	releases, err := v.fetchReleases(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var cutoff *time.Time
+	if cooldown > 0 {
+		if hasDates(releases) {
+			t := time.Now().Add(-cooldown)
+			cutoff = &t
+		} else {
+			lgr.WithFields("url", v.config.URL).
+				Warn("cooldown is configured but the query does not yield publish dates (ignoring)")
+		}
+	}
+
+	latestRelease, err := filterToLatestVersion(releases, filter, cutoff)
+	if err != nil {
+		return "", fmt.Errorf("unable to filter to latest version: %v", err)
+	}
+
+	if latestRelease == nil {
+		if cutoff != nil {
+			// find the absolute latest (without cooldown) to produce a helpful error message
+			cooldownErr := &binny.CooldownError{Cooldown: cooldown}
+			if absoluteLatest, _ := filterToLatestVersion(releases, filter, nil); absoluteLatest != nil {
+				cooldownErr.LatestVersion = absoluteLatest.version
+				cooldownErr.LatestDate = absoluteLatest.date
+			}
+			return "", cooldownErr
+		}
+		return "", fmt.Errorf("no version found in %q", v.config.URL)
+	}
+
+	lgr.WithFields("latest", latestRelease.version, "url", v.config.URL).
+		Trace("found latest version from http document")
+
+	return latestRelease.version, nil
+}
+
+// filterToLatestVersion finds the latest release that satisfies the version constraint, prerelease policy, and
+// cooldown cutoff. When a cutoff is given, releases without a publish date are not candidates.
+func filterToLatestVersion(releases []release, filter binny.VersionFilter, cutoff *time.Time) (*release, error) {
+	constraint, err := filter.Constraints()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		latestRelease *release
+		latestVersion binny.Version
+	)
+	for _, r := range releases {
+		vs, ok := filter.Tags.Version(r.version)
+		if !ok || vs == "" {
+			continue
+		}
+		ver, err := filter.Parse(vs)
+		if err != nil {
+			continue
+		}
+		if !filter.Allows(constraint, ver, false) {
+			continue
+		}
+		if cutoff != nil && (r.date == nil || r.date.After(*cutoff)) {
+			continue
+		}
+		if latestVersion == nil || ver.Compare(latestVersion) > 0 {
+			latestVersion = ver
+			latestRelease = &release{version: vs, date: r.date}
+		}
+	}
+
+	return latestRelease, nil
+}
+
+func hasDates(releases []release) bool {
+	for _, r := range releases {
+		if r.date != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchReleases retrieves the document and evaluates the query against it.
+func (v VersionResolver) fetchReleases(ctx context.Context) ([]release, error) {
+	if v.config.URL == "" {
+		return nil, fmt.Errorf("no URL configured to resolve versions from")
+	}
+
+	contents, err := v.documentFetcher(ctx, v.config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch %q: %w", v.config.URL, err)
+	}
+
+	var doc any
+	if v.text {
+		if v.config.Query == "" {
+			return releasesFromLines(string(contents)), nil
+		}
+		doc = string(contents)
+	} else if err := json.Unmarshal(contents, &doc); err != nil {
+		return nil, fmt.Errorf("unable to decode JSON from %q: %w", v.config.URL, err)
+	}
+
+	return evaluateQuery(ctx, v.config.Query, doc)
+}
+
+// releasesFromLines treats every non-empty line of a text document as a version.
+func releasesFromLines(contents string) []release {
+	var releases []release
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		releases = append(releases, release{version: line})
+	}
+	return releases
+}
+
+func evaluateQuery(ctx context.Context, expression string, doc any) ([]release, error) {
+	if expression == "" {
+		expression = "."
+	}
+
+	query, err := gojq.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse query %q: %w", expression, err)
+	}
+
+	var releases []release
+	iter := query.RunWithContext(ctx, doc)
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := result.(error); ok {
+			return nil, fmt.Errorf("unable to evaluate query %q: %w", expression, err)
+		}
+
+		found, err := releasesFromResult(result)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected result from query %q: %w", expression, err)
+		}
+		releases = append(releases, found...)
+	}
+	return releases, nil
+}
+
+func releasesFromResult(result any) ([]release, error) {
+	switch value := result.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		var releases []release
+		for _, item := range value {
+			found, err := releasesFromResult(item)
+			if err != nil {
+				return nil, err
+			}
+			releases = append(releases, found...)
+		}
+		return releases, nil
+	case map[string]any:
+		version, err := versionFromValue(value["version"])
+		if err != nil {
+			return nil, err
+		}
+		date, err := dateFromValue(value["date"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid date for version %q: %w", version, err)
+		}
+		return []release{{version: version, date: date}}, nil
+	default:
+		version, err := versionFromValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return []release{{version: version}}, nil
+	}
+}
+
+func versionFromValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return "", fmt.Errorf("empty version")
+		}
+		return v, nil
+	case float64:
+		// a number such as 1.10 has already lost its text (it is 1.1), so only whole numbers (e.g. build numbers) are
+		// taken as versions
+		if v != math.Trunc(v) {
+			return "", fmt.Errorf("version must be a string, got the number %v (quote the version in the document)", v)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int:
+		return strconv.Itoa(v), nil
+	}
+	return "", fmt.Errorf("version must be a string or number, got %T", value)
+}
+
+func dateFromValue(value any) (*time.Time, error) {
+	s, ok := value.(string)
+	if value == nil || ok && s == "" {
+		return nil, nil
+	}
+	if !ok {
+		return nil, fmt.Errorf("date must be a string, got %T", value)
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to parse %q (expected RFC 3339 or YYYY-MM-DD)", s)
+}
+
+func fetchDocument(ctx context.Context, url string) ([]byte, error) {
+	client := internalhttp.ResolutionClientFromContext(ctx)
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	log.FromContext(ctx).WithFields("http-status", resp.StatusCode).Tracef("http get %q", url)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,240 @@
+package httpapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/binny"
+)
+
+func staticDocument(contents string) func(context.Context, string) ([]byte, error) {
+	return func(context.Context, string) ([]byte, error) {
+		return []byte(contents), nil
+	}
+}
+
+func TestVersionResolver_ResolveVersion(t *testing.T) {
+	recent := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	old := time.Now().Add(-30 * 24 * time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name       string
+		method     string
+		config     VersionResolutionParameters
+		document   string
+		version    string
+		constraint string
+		cooldown   time.Duration
+		want       string
+		wantErr    require.ErrorAssertionFunc
+	}{
+		{
+			name:     "text document with a single version",
+			method:   TextResolveMethod,
+			document: "v1.30.2\n",
+			version:  "latest",
+			want:     "v1.30.2",
+		},
+		{
+			name:     "text document with a version per line",
+			method:   TextResolveMethod,
+			document: "v1.0.0\nv1.2.0\n\nv1.1.0\n",
+			version:  "latest",
+			want:     "v1.2.0",
+		},
+		{
+			name:     "text document with a query",
+			method:   TextResolveMethod,
+			config:   VersionResolutionParameters{Query: `split(",")[] | ltrimstr("release-")`},
+			document: "release-1.0.0,release-1.3.0,release-1.2.0",
+			version:  "latest",
+			want:     "1.3.0",
+		},
+		{
+			name:     "json array of versions",
+			method:   JSONResolveMethod,
+			document: `["1.0.0", "2.0.0", "1.5.0"]`,
+			version:  "latest",
+			want:     "2.0.0",
+		},
+		{
+			name:     "json query yielding versions",
+			method:   JSONResolveMethod,
+			config:   VersionResolutionParameters{Query: `.releases[].name`},
+			document: `{"releases": [{"name": "v3.1.0"}, {"name": "v3.2.0-rc.1"}, {"name": "v3.0.0"}]}`,
+			version:  "latest",
+			want:     "v3.1.0",
+		},
+		{
+			name:       "constraints are honored",
+			method:     JSONResolveMethod,
+			document:   `["1.0.0", "2.0.0", "1.5.0"]`,
+			version:    "latest",
+			constraint: "< 2.0.0",
+			want:       "1.5.0",
+		},
+		{
+			name:     "cooldown with publish dates",
+			method:   JSONResolveMethod,
+			config:   VersionResolutionParameters{Query: `.[] | {version: .v, date: .published}`},
+			document: fmt.Sprintf(`[{"v": "1.0.0", "published": %q}, {"v": "1.1.0", "published": %q}]`, old, recent),
+			version:  "latest",
+			cooldown: 7 * 24 * time.Hour,
+			want:     "1.0.0",
+		},
+		{
+			name:     "cooldown with no version old enough",
+			method:   JSONResolveMethod,
+			config:   VersionResolutionParameters{Query: `.[] | {version: .v, date: .published}`},
+			document: fmt.Sprintf(`[{"v": "1.1.0", "published": %q}]`, recent),
+			version:  "latest",
+			cooldown: 7 * 24 * time.Hour,
+			wantErr: func(t require.TestingT, err error, _ ...interface{}) {
+				var cooldownErr *binny.CooldownError
+				require.ErrorAs(t, err, &cooldownErr)
+				assert.Equal(t, "1.1.0", cooldownErr.LatestVersion)
+			},
+		},
+		{
+			name:     "cooldown is ignored without publish dates",
+			method:   JSONResolveMethod,
+			document: `["1.0.0", "1.1.0"]`,
+			version:  "latest",
+			cooldown: 7 * 24 * time.Hour,
+			want:     "1.1.0",
+		},
+		{
+			name:     "numeric versions",
+			method:   JSONResolveMethod,
+			config:   VersionResolutionParameters{Query: `.builds[].number`},
+			document: `{"builds": [{"number": 4567}, {"number": 4600}]}`,
+			version:  "latest",
+			want:     "4600",
+		},
+		{
+			name:     "fractional numeric versions are rejected",
+			method:   JSONResolveMethod,
+			document: `[1.9, 1.10]`,
+			version:  "latest",
+			wantErr: func(t require.TestingT, err error, _ ...interface{}) {
+				require.ErrorContains(t, err, "version must be a string")
+			},
+		},
+		{
+			name:     "pinned version is honored as is",
+			method:   JSONResolveMethod,
+			document: `not json`,
+			version:  "1.0.0",
+			want:     "1.0.0",
+		},
+		{
+			name:     "invalid json",
+			method:   JSONResolveMethod,
+			document: `not json`,
+			version:  "latest",
+			wantErr:  require.Error,
+		},
+		{
+			name:     "invalid query",
+			method:   JSONResolveMethod,
+			config:   VersionResolutionParameters{Query: `.[`},
+			document: `[]`,
+			version:  "latest",
+			wantErr:  require.Error,
+		},
+		{
+			name:     "query yielding unexpected values",
+			method:   JSONResolveMethod,
+			config:   VersionResolutionParameters{Query: `.[] | true`},
+			document: `["1.0.0"]`,
+			version:  "latest",
+			wantErr:  require.Error,
+		},
+		{
+			name:     "no versions found",
+			method:   JSONResolveMethod,
+			document: `[]`,
+			version:  "latest",
+			wantErr:  require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr == nil {
+				tt.wantErr = require.NoError
+			}
+
+			if tt.config.URL == "" {
+				tt.config.URL = "https://example.com/versions"
+			}
+
+			v := NewVersionResolver(tt.method, tt.config)
+			v.documentFetcher = staticDocument(tt.document)
+
+			got, err := v.ResolveVersion(context.Background(), binny.VersionIntent{
+				Want:       tt.version,
+				Constraint: tt.constraint,
+				Cooldown:   tt.cooldown,
+			})
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVersionResolver_UpdateVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		document   string
+		version    string
+		constraint string
+		want       string
+	}{
+		{
+			name:     "latest stays latest",
+			document: `["1.0.0", "2.0.0"]`,
+			version:  "latest",
+			want:     "latest",
+		},
+		{
+			name:     "pinned version is updated",
+			document: `["1.0.0", "2.0.0"]`,
+			version:  "1.0.0",
+			want:     "2.0.0",
+		},
+		{
+			name:       "pinned version is updated within the constraint",
+			document:   `["1.0.0", "1.1.0", "2.0.0"]`,
+			version:    "1.0.0",
+			constraint: "< 2.0.0",
+			want:       "1.1.0",
+		},
+		{
+			name:     "non-version is kept as is",
+			document: `["1.0.0", "2.0.0"]`,
+			version:  "nightly",
+			want:     "nightly",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVersionResolver(JSONResolveMethod, VersionResolutionParameters{URL: "https://example.com/versions"})
+			v.documentFetcher = staticDocument(tt.document)
+
+			got, err := v.UpdateVersion(context.Background(), binny.VersionIntent{
+				Want:       tt.version,
+				Constraint: tt.constraint,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/wagoodman/go-partybus"
@@ -13,7 +14,9 @@ import (
 
 	"github.com/anchore/binny"
 	"github.com/anchore/binny/event"
+	"github.com/anchore/binny/internal"
 	"github.com/anchore/binny/internal/bus"
+	"github.com/anchore/binny/internal/executable"
 	"github.com/anchore/binny/internal/log"
 )
 
@@ -44,6 +47,10 @@ func Install(ctx context.Context, tool binny.Tool, intent binny.VersionIntent, s
 		return fmt.Errorf("failed to resolve version for tool %q: %w", tool.Name(), err)
 	}
 
+	if err := checkSkipped(intent, resolvedVersion); err != nil {
+		return fmt.Errorf("refusing to install tool %q: %w", tool.Name(), err)
+	}
+
 	stage.Set("validating")
 
 	err = Check(store, tool.Name(), resolvedVersion, verifyConfig)
@@ -60,16 +67,46 @@ func Install(ctx context.Context, tool binny.Tool, intent binny.VersionIntent, s
 
 	stage.Set(fmt.Sprintf("installing %q", resolvedVersion))
 
+	// installers operate on the tag published by the source, which may differ from the version
+	tag, err := intent.Tags.Tag(resolvedVersion)
+	if err != nil {
+		return fmt.Errorf("failed to determine tag for tool %q: %w", tool.Name(), err)
+	}
+
 	// install the tool to a temp dir
-	binPath, err := tool.InstallTo(ctx, resolvedVersion, tmpdir)
+	ctx, checksums := internal.WithChecksumRecord(ctx, tool.Name(), verifyConfig.RequireChecksum)
+	ctx, linkageHint := executable.WithLinkageHint(ctx)
+	binPath, err := tool.InstallTo(ctx, tag, tmpdir)
 	if err != nil {
 		return err
 	}
 
+	// installers report what they verified, so this only catches installers that verify nothing at all
+	if checksums.Source() == "" {
+		if err := checksums.Missing(fmt.Sprintf("%s@%s", tool.Name(), tag)); err != nil {
+			return err
+		}
+	}
+
+	// asset selection is heuristic, so make certain that what was installed can run here
+	stage.Set("validating binary")
+	if err := executable.Validate(binPath, runtime.GOOS, runtime.GOARCH); err != nil {
+		return fmt.Errorf("installed file for tool %q is not usable: %w", tool.Name(), err)
+	}
+	if verifyConfig.VerifyLinkage {
+		if err := checkLinkage(tool.Name(), binPath, linkageHint.String()); err != nil {
+			return fmt.Errorf("installed file for tool %q is not usable: %w", tool.Name(), err)
+		}
+	}
+
 	stage.Set("storing")
 
 	// if the installation was successful, add the tool to the store
-	if err = store.AddTool(tool.Name(), resolvedVersion, binPath); err != nil {
+	if err = store.AddTool(tool.Name(), resolvedVersion, binPath, binny.ToolSource{
+		URL:            checksums.SourceURL(),
+		ChecksumSource: checksums.Source(),
+		ModuleHash:     checksums.ModuleHash(),
+	}); err != nil {
 		return err
 	}
 
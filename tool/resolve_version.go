@@ -4,12 +4,11 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/Masterminds/semver/v3"
-
 	"github.com/anchore/binny"
 	"github.com/anchore/binny/tool/git"
 	"github.com/anchore/binny/tool/githubrelease"
 	"github.com/anchore/binny/tool/goproxy"
+	"github.com/anchore/binny/tool/httpapi"
 )
 
 func VersionResolverMethods() []string {
@@ -17,31 +16,82 @@ func VersionResolverMethods() []string {
 		githubrelease.ResolveMethod,
 		goproxy.ResolveMethod,
 		git.ResolveMethod,
+		httpapi.JSONResolveMethod,
+		httpapi.TextResolveMethod,
 	}
 }
 
 func ResolveVersion(ctx context.Context, tool binny.VersionResolver, intent binny.VersionIntent) (string, error) {
-	constraint := intent.Constraint
+	resolvedVersion, _, err := ResolveVersionWithStrategy(ctx, tool, intent)
+	return resolvedVersion, err
+}
 
-	var resolvedVersion string
+// ResolveVersionWithStrategy resolves the version (see ResolveVersion) along with the strategy that produced it. The
+// strategy is empty when the version resolver does not report one.
+func ResolveVersionWithStrategy(ctx context.Context, tool binny.VersionResolver, intent binny.VersionIntent) (string, binny.LatestStrategy, error) {
+	var (
+		resolvedVersion string
+		strategy        binny.LatestStrategy
+		err             error
+	)
 
-	resolvedVersion, err := tool.ResolveVersion(ctx, intent)
+	if reporter, ok := tool.(binny.LatestStrategyReporter); ok {
+		resolvedVersion, strategy, err = reporter.ResolveVersionWithStrategy(ctx, intent)
+	} else {
+		resolvedVersion, err = tool.ResolveVersion(ctx, intent)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to resolve version: %w", err)
+		return "", "", fmt.Errorf("failed to resolve version: %w", err)
+	}
+
+	return resolvedVersion, strategy, checkResolvedVersion(intent, resolvedVersion)
+}
+
+// checkResolvedVersion returns an error if the resolved version is on the skip list or does not satisfy the constraint.
+func checkResolvedVersion(intent binny.VersionIntent, resolvedVersion string) error {
+	constraint := intent.Constraint
+
+	if err := checkSkipped(intent, resolvedVersion); err != nil {
+		return err
 	}
 
 	if constraint != "" {
-		ver, err := semver.NewVersion(resolvedVersion)
+		filter := intent.Filter()
+		ver, err := filter.Parse(resolvedVersion)
 		if err == nil {
-			constraintObj, err := semver.NewConstraint(constraint)
+			constraintObj, err := filter.Constraints()
 			if err != nil {
-				return resolvedVersion, fmt.Errorf("invalid version constraint: %v", err)
+				return fmt.Errorf("invalid version constraint: %v", err)
 			}
 
 			if !constraintObj.Check(ver) {
-				return resolvedVersion, fmt.Errorf("resolved version %q is unsatisfied by constraint %q. Remove the constraint or run 'update' to re-pin a valid version", resolvedVersion, constraint)
+				return fmt.Errorf("resolved version %q is unsatisfied by constraint %q. Remove the constraint or run 'update' to re-pin a valid version", resolvedVersion, constraint)
 			}
 		}
 	}
-	return resolvedVersion, nil
+	return nil
+}
+
+// checkSkipped returns an error if the resolved version is on the skip list (deny-list) of the intent.
+func checkSkipped(intent binny.VersionIntent, resolvedVersion string) error {
+	skipped, err := intent.Filter().IsSkipped(resolvedVersion)
+	if err != nil {
+		return fmt.Errorf("invalid skip list: %w", err)
+	}
+	if skipped {
+		return fmt.Errorf("resolved version %q is on the skip list. Pin a different version or run 'update' to re-pin a valid version", resolvedVersion)
+	}
+	return nil
+}
+
+// PrefetchReleases lists the GitHub releases of every given version resolver that lists them, with as few queries as
+// possible (see githubrelease.WithPrefetchedReleases), returning a context where resolving versions uses them.
+func PrefetchReleases(ctx context.Context, resolvers ...binny.VersionResolver) context.Context {
+	var repos []string
+	for _, resolver := range resolvers {
+		if lister, ok := resolver.(binny.GitHubReleasesLister); ok && lister.GitHubRepo() != "" {
+			repos = append(repos, lister.GitHubRepo())
+		}
+	}
+	return githubrelease.WithPrefetchedReleases(ctx, repos)
 }